@@ -52,40 +52,96 @@ var builtinSel BuiltinSelections
 
 //builtin functions
 var builtinFuncs = map[string]CallFunc{
-	"absHref":       builtinFun.AbsHref,
-	"attr":          builtinFun.Attr,
-	"attrConcat":    builtinFun.AttrConcat,
-	"attrEmpty":     builtinFun.AttrEmpty,
-	"attrSplit":     builtinFun.AttrSplit,
-	"eachAttr":      builtinFun.EachAttr,
-	"eachAttrEmpty": builtinFun.EachAttrEmpty,
-	"eachHtml":      builtinFun.EachHtml,
-	"eachOutHtml":   builtinFun.EachOutHtml,
-	"eachText":      builtinFun.EachText,
-	"eachTextEmpty": builtinFun.EachTextEmpty,
-	"eachTextJoin":  builtinFun.EachTextJoin,
-	"eqAndAttr":     builtinFun.EqAndAttr,
-	"eqAndHtml":     builtinFun.EqAndHtml,
-	"eqAndOutHtml":  builtinFun.EqAndOutHtml,
-	"eqAndText":     builtinFun.EqAndText,
-	"html":          builtinFun.Html,
-	"outerHtml":     builtinFun.OutHtml,
-	"size":          builtinFun.Size,
-	"text":          builtinFun.Text,
-	"textConcat":    builtinFun.TextConcat,
-	"textEmpty":     builtinFun.TextEmpty,
-	"textSplit":     builtinFun.TextSplit,
+	"absHref":        builtinFun.AbsHref,
+	"attr":           builtinFun.Attr,
+	"attrConcat":     builtinFun.AttrConcat,
+	"attrEmpty":      builtinFun.AttrEmpty,
+	"attrOr":         builtinFun.AttrOr,
+	"attrSplit":      builtinFun.AttrSplit,
+	"attrs":          builtinFun.Attrs,
+	"classList":      builtinFun.ClassList,
+	"comments":       builtinFun.Comments,
+	"compact":        builtinFun.Compact,
+	"contents":       builtinFun.Contents,
+	"dataAttrs":      builtinFun.DataAttrs,
+	"date":           builtinFun.Date,
+	"dateFuzzy":      builtinFun.DateFuzzy,
+	"dlMap":          builtinFun.DlMap,
+	"duration":       builtinFun.Duration,
+	"eachAttr":       builtinFun.EachAttr,
+	"eachAttrEmpty":  builtinFun.EachAttrEmpty,
+	"eachHtml":       builtinFun.EachHtml,
+	"eachOutHtml":    builtinFun.EachOutHtml,
+	"eachRegex":      builtinFun.EachRegex,
+	"eachText":       builtinFun.EachText,
+	"eachTextEmpty":  builtinFun.EachTextEmpty,
+	"eachTextJoin":   builtinFun.EachTextJoin,
+	"eqAndAttr":      builtinFun.EqAndAttr,
+	"eqAndHtml":      builtinFun.EqAndHtml,
+	"eqAndOutHtml":   builtinFun.EqAndOutHtml,
+	"eqAndText":      builtinFun.EqAndText,
+	"exists":         builtinFun.Exists,
+	"form":           builtinFun.Form,
+	"hasClass":       builtinFun.HasClass,
+	"html":           builtinFun.Html,
+	"humanNumber":    builtinFun.HumanNumber,
+	"ifExists":       builtinFun.IfExists,
+	"innerText":      builtinFun.InnerText,
+	"json":           builtinFun.Json,
+	"jsonLd":         builtinFun.JsonLd,
+	"lower":          builtinFun.Lower,
+	"markdown":       builtinFun.Markdown,
+	"metaMap":        builtinFun.MetaMap,
+	"microdata":      builtinFun.Microdata,
+	"number":         builtinFun.Number,
+	"og":             builtinFun.Og,
+	"outerHtml":      builtinFun.OutHtml,
+	"ownText":        builtinFun.OwnText,
+	"price":          builtinFun.Price,
+	"regex":          builtinFun.Regex,
+	"regexAttr":      builtinFun.RegexAttr,
+	"regexGroups":    builtinFun.RegexGroups,
+	"replace":        builtinFun.Replace,
+	"sanitize":       builtinFun.Sanitize,
+	"size":           builtinFun.Size,
+	"sortStrings":    builtinFun.SortStrings,
+	"srcset":         builtinFun.Srcset,
+	"stripTags":      builtinFun.StripTags,
+	"styleProp":      builtinFun.StyleProp,
+	"table":          builtinFun.Table,
+	"text":           builtinFun.Text,
+	"textConcat":     builtinFun.TextConcat,
+	"textEmpty":      builtinFun.TextEmpty,
+	"textNormalized": builtinFun.TextNormalized,
+	"textSplit":      builtinFun.TextSplit,
+	"textSplitRegex": builtinFun.TextSplitRegex,
+	"textTemplate":   builtinFun.TextTemplate,
+	"title":          builtinFun.Title,
+	"trim":           builtinFun.Trim,
+	"trimPrefix":     builtinFun.TrimPrefix,
+	"trimSuffix":     builtinFun.TrimSuffix,
+	"truncate":       builtinFun.Truncate,
+	"unique":         builtinFun.Unique,
+	"upper":          builtinFun.Upper,
 	// selector
 	"child":        builtinSel.Child,
+	"closest":      builtinSel.Closest,
 	"eq":           builtinSel.Eq,
+	"filter":       builtinSel.Filter,
+	"find":         builtinSel.Find,
 	"first":        builtinSel.First,
+	"has":          builtinSel.Has,
 	"last":         builtinSel.Last,
+	"mapOf":        builtinSel.MapOf,
 	"next":         builtinSel.Next,
+	"not":          builtinSel.Not,
 	"parent":       builtinSel.Parent,
 	"parents":      builtinSel.Parents,
 	"parentsUntil": builtinSel.ParentsUntil,
 	"prev":         builtinSel.Prev,
+	"selection":    builtinSel.Selection,
 	"siblings":     builtinSel.Siblings,
+	"slice":        builtinSel.Slice,
 }
 
 // RegisterFunc register function for parse result
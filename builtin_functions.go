@@ -1,12 +1,20 @@
 package pagser
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mattn/godown"
 	"github.com/spf13/cast"
+	"golang.org/x/net/html"
 	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // BuiltinFunctions builtin functions are registered with a lowercase initial, eg: Text -> text()
@@ -99,6 +107,24 @@ func (builtin BuiltinFunctions) AttrEmpty(node *goquery.Selection, args ...strin
 	return value, nil
 }
 
+// AttrOr attrOr(name1, [ name2, ... name_n, ] defaultValue) get the first non-empty attribute value of names in order,
+// if all empty will return defaultValue, return string.
+//	//<img data-src="/lazy.png" src="/placeholder.png">
+//	struct {
+//		Example string `pagser:"img->attrOr(data-src, data-original, src, '')"`
+//	}
+func (builtin BuiltinFunctions) AttrOr(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("attrOr(name1, [ name2, ... name_n, ] defaultValue) must has name and default value")
+	}
+	for _, name := range args[:len(args)-1] {
+		if value := strings.TrimSpace(node.AttrOr(name, "")); value != "" {
+			return value, nil
+		}
+	}
+	return args[len(args)-1], nil
+}
+
 // AttrSplit attrSplit(name, sep=',', trim='true')  get attribute value and split by separator to array string, return []string.
 //	struct {
 //		Examples []string `pagser:".selector->attrSplit('keywords', ',')"`
@@ -130,6 +156,178 @@ func (builtin BuiltinFunctions) AttrSplit(node *goquery.Selection, args ...strin
 	return list, nil
 }
 
+// Attrs attrs() get all attributes of the first element, return map[string]string.
+//	//<img src="/logo.png" alt="Pagser">
+//	struct {
+//		Example map[string]string `pagser:".selector->attrs()"`
+//	}
+func (builtin BuiltinFunctions) Attrs(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return nodeAttrs(node), nil
+}
+
+// ClassList classList() get the class names of the first element, return []string.
+//	//<li class="item active">
+//	struct {
+//		Examples []string `pagser:".selector->classList()"`
+//	}
+func (builtin BuiltinFunctions) ClassList(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return strings.Fields(node.AttrOr("class", "")), nil
+}
+
+// Comments comments() get the text of each html comment in the elements, return []string.
+//	//<div><!-- id: 123 --><span>text</span></div>
+//	struct {
+//		Examples []string `pagser:".selector->comments()"`
+//	}
+func (builtin BuiltinFunctions) Comments(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	list := make([]string, 0)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.CommentNode {
+			list = append(list, strings.TrimSpace(n.Data))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range node.Nodes {
+		walk(n)
+	}
+	return list, nil
+}
+
+// Compact compact() get each element text and remove the empty items, it is usually used after eachText() or
+// textSplit() in pipeline, return []string.
+//	struct {
+//		Examples []string `pagser:".selector->eachText()->compact()"`
+//	}
+func (builtin BuiltinFunctions) Compact(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	list := make([]string, 0)
+	node.Each(func(i int, selection *goquery.Selection) {
+		if text := strings.TrimSpace(selection.Text()); text != "" {
+			list = append(list, text)
+		}
+	})
+	return list, nil
+}
+
+// Contents contents() get the text of each direct text node of elements, the child elements are excluded,
+// blank text nodes are skipped, return []string.
+//	//<p>Price: <b>$10</b> only</p>
+//	struct {
+//		Examples []string `pagser:".selector->contents()"`
+//	}
+func (builtin BuiltinFunctions) Contents(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	list := make([]string, 0)
+	for _, text := range ownTexts(node) {
+		if text = strings.TrimSpace(text); text != "" {
+			list = append(list, text)
+		}
+	}
+	return list, nil
+}
+
+// DataAttrs dataAttrs() get all `data-*` attributes of the first element, the keys are without `data-` prefix,
+// return map[string]string.
+//	//<div data-id="123" data-user-name="Pagser">
+//	struct {
+//		Example map[string]string `pagser:".selector->dataAttrs()"` // {"id": "123", "user-name": "Pagser"}
+//	}
+func (builtin BuiltinFunctions) DataAttrs(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	attrs := make(map[string]string)
+	for key, value := range nodeAttrs(node) {
+		if name := strings.TrimPrefix(key, "data-"); name != key && name != "" {
+			attrs[name] = value
+		}
+	}
+	return attrs, nil
+}
+
+// Date date(layout='') get element text and parse it to time by the layout, return time.Time.
+// If layout is empty, the text is parsed by trying the common date formats.
+//	//<span class="date">2020-04-25</span>
+//	struct {
+//		Example time.Time `pagser:".selector->date('2006-01-02')"`
+//	}
+func (builtin BuiltinFunctions) Date(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	layout := ""
+	if len(args) > 0 {
+		layout = args[0]
+	}
+	value := strings.TrimSpace(node.Text())
+	t, err := toTimeE(value, layout)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date(layout) parse `%v` error: %v", value, err)
+	}
+	return t, nil
+}
+
+// DlMap dlMap(keySelector='dt', valueSelector='dd') get the text of keySelector elements as key and the text of the following
+// valueSelector elements as value in document order, the trailing colon of key is removed and multiple values of a key
+// are joined by ", ", it also works for the label/value pattern like `.specs->dlMap(.label, .value)`, return map[string]string.
+//	//<dl><dt>Weight:</dt><dd>1kg</dd><dt>Color</dt><dd>Red</dd><dd>Blue</dd></dl>
+//	struct {
+//		Example map[string]string `pagser:"dl->dlMap()"` // {"Weight": "1kg", "Color": "Red, Blue"}
+//	}
+func (builtin BuiltinFunctions) DlMap(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	keySelector := "dt"
+	valueSelector := "dd"
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		keySelector = strings.TrimSpace(args[0])
+	}
+	if len(args) > 1 && strings.TrimSpace(args[1]) != "" {
+		valueSelector = strings.TrimSpace(args[1])
+	}
+	values := make(map[string]string)
+	key := ""
+	hasKey := false
+	node.Find(keySelector + ", " + valueSelector).Each(func(i int, item *goquery.Selection) {
+		text := strings.TrimSpace(item.Text())
+		if item.Is(keySelector) {
+			key = strings.TrimSpace(strings.TrimSuffix(text, ":"))
+			hasKey = true
+			return
+		}
+		if !hasKey {
+			return
+		}
+		if value, ok := values[key]; ok && value != "" {
+			values[key] = value + ", " + text
+		} else {
+			values[key] = text
+		}
+	})
+	return values, nil
+}
+
+// DateFuzzy dateFuzzy() get element text and parse the human formatted date to time, such as `2 days ago`, `yesterday`,
+// `in 3 hours`, `Jan 3`, `March 5th, 2024`, the dates without year are in the current year, return time.Time.
+//	struct {
+//		Example time.Time `pagser:".selector->dateFuzzy()"`
+//	}
+func (builtin BuiltinFunctions) DateFuzzy(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	value := strings.TrimSpace(node.Text())
+	t, err := parseFuzzyTime(value, timeNow())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dateFuzzy() parse `%v` error: %v", value, err)
+	}
+	return t, nil
+}
+
+// Duration duration() get element text and parse it to duration, eg: `1h30m`, `90s`, return time.Duration.
+//	//<span class="uptime">1h30m</span>
+//	struct {
+//		Example time.Duration `pagser:".selector->duration()"`
+//	}
+func (builtin BuiltinFunctions) Duration(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	value := strings.TrimSpace(node.Text())
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Duration(0), fmt.Errorf("duration() parse `%v` error: %v", value, err)
+	}
+	return d, nil
+}
+
 // EachAttr eachAttr(name) get each element attribute value, return []string.
 //	//<a href="https://github.com/foolin/pagser">Pagser</a>
 //	struct {
@@ -212,6 +410,30 @@ func (builtin BuiltinFunctions) EachOutHtml(node *goquery.Selection, args ...str
 	return list, nil
 }
 
+// EachRegex eachRegex(pattern) get each element text and returns the first capture group of the first match,
+// or the whole match if pattern has no group, the elements not matched are skipped, return []string.
+//	//<li>Size: 42</li><li>Size: 43</li>
+//	struct {
+//		Examples []int `pagser:"li->eachRegex('Size: (\\d+)')"`
+//	}
+func (builtin BuiltinFunctions) EachRegex(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("eachRegex(pattern) must has pattern")
+	}
+	rx, err := compileRegexp(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("eachRegex(pattern) pattern `%v` is invalid: %v", args[0], err)
+	}
+	list := make([]string, 0)
+	node.Each(func(i int, selection *goquery.Selection) {
+		text := strings.TrimSpace(selection.Text())
+		if rx.MatchString(text) {
+			list = append(list, regexpFind(rx, text))
+		}
+	})
+	return list, nil
+}
+
 // EachText eachText() get each element text, return []string.
 //	struct {
 //		Examples []string `pagser:".selector->eachText('')"`
@@ -325,6 +547,38 @@ func (builtin BuiltinFunctions) EqAndText(node *goquery.Selection, args ...strin
 	return strings.TrimSpace(node.Eq(idx).Text()), nil
 }
 
+// Exists exists() returns true if the selector matches any element, return bool.
+//	struct {
+//		HasDiscount bool `pagser:".badge-sale->exists()"`
+//	}
+func (builtin BuiltinFunctions) Exists(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return node.Size() > 0, nil
+}
+
+// HasClass hasClass(name) returns true if any of the elements has the class name, return bool.
+//	struct {
+//		SoldOut bool `pagser:".product->hasClass(sold-out)"`
+//	}
+func (builtin BuiltinFunctions) HasClass(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return false, fmt.Errorf("hasClass(name) must has name")
+	}
+	return node.HasClass(strings.TrimSpace(args[0])), nil
+}
+
+// Form form() get the action, method and all named inputs, selects and textareas of the form element, return FormData.
+// The values are set to the fields by input name case-insensitively if the field is a custom struct.
+//	struct {
+//		Login pagser.FormData `pagser:"form#login->form()"`
+//		Search struct {
+//			Q    string
+//			Page int
+//		} `pagser:"form.search->form()"`
+//	}
+func (builtin BuiltinFunctions) Form(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return *newFormData(node), nil
+}
+
 // Html html() get element inner html, return string.
 //	struct {
 //		Example string `pagser:".selector->html()"`
@@ -333,6 +587,245 @@ func (builtin BuiltinFunctions) Html(node *goquery.Selection, args ...string) (o
 	return node.Html()
 }
 
+// HumanNumber humanNumber(locale='') get element text and parse the abbreviated number, such as `1.2k`, `3.4M`,
+// `5 mil`, `2.5 billion`, the number without suffix is parsed like number(locale), return float64.
+//	struct {
+//		Followers int `pagser:".followers->humanNumber()"`
+//	}
+func (builtin BuiltinFunctions) HumanNumber(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	locale := ""
+	if len(args) > 0 {
+		locale = args[0]
+	}
+	value := strings.TrimSpace(node.Text())
+	number, err := parseHumanNumber(value, locale)
+	if err != nil {
+		return 0.0, fmt.Errorf("humanNumber(locale) parse `%v` error: %v", value, err)
+	}
+	return number, nil
+}
+
+// IfExists ifExists(selector, then, else='') returns then if the element matches the selector or has the descendants
+// matched by selector, otherwise returns else, the elements are checked if the selector is empty, return string.
+//	struct {
+//		Stock string `pagser:".product->ifExists('.sold-out', 'no', 'yes')"`
+//		Badge string `pagser:".badge->ifExists('', 'new')"`
+//	}
+func (builtin BuiltinFunctions) IfExists(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("ifExists(selector, then, else='') must has selector and then value")
+	}
+	otherwise := ""
+	if len(args) > 2 {
+		otherwise = args[2]
+	}
+	selector := strings.TrimSpace(args[0])
+	exists := node.Size() > 0
+	if selector != "" {
+		exists = node.Is(selector) || node.Find(selector).Size() > 0
+	}
+	if exists {
+		return args[1], nil
+	}
+	return otherwise, nil
+}
+
+// InnerText innerText() get element text like browser `innerText`, the block elements such as `<p>`, `<div>`, `<li>`
+// are separated by line breaks, `<br>` is a line break, the whitespaces are collapsed except in `<pre>`, return string.
+//	struct {
+//		Body string `pagser:"article->innerText()"`
+//	}
+func (builtin BuiltinFunctions) InnerText(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return innerText(node), nil
+}
+
+// Json json() get element text as json, and unmarshal it to the field of any type, return json.RawMessage.
+//	//<script id="data" type="application/json">{"name": "pagser", "stars": 100}</script>
+//	struct {
+//		Example struct {
+//			Name  string `json:"name"`
+//			Stars int    `json:"stars"`
+//		} `pagser:"script#data->json()"`
+//	}
+func (builtin BuiltinFunctions) Json(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	text := strings.TrimSpace(node.Text())
+	if !json.Valid([]byte(text)) {
+		return nil, fmt.Errorf("json() text is not valid json: `%v`", text)
+	}
+	return json.RawMessage(text), nil
+}
+
+// JsonLd jsonLd(type='') get the JSON-LD structured data of `<script type="application/ld+json">` in the elements,
+// all items are returned if type is empty, otherwise the first item of the `@type` is returned, eg: `Product`, `Article`,
+// the invalid json scripts are skipped, return json.RawMessage to unmarshal to the field of any type.
+//	struct {
+//		Items   []map[string]interface{} `pagser:"html->jsonLd()"`
+//		Product struct {
+//			Name string `json:"name"`
+//			Sku  string `json:"sku"`
+//		} `pagser:"html->jsonLd(Product)"`
+//	}
+func (builtin BuiltinFunctions) JsonLd(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	items := jsonLdItems(node)
+	var data interface{} = items
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		item := findJsonLdType(items, strings.TrimSpace(args[0]))
+		if item == nil {
+			return json.RawMessage("null"), nil
+		}
+		data = item
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsonLd(type) marshal error: %v", err)
+	}
+	return json.RawMessage(raw), nil
+}
+
+// rxMarkdownBlankLines matches the runs of blank lines in markdown
+var rxMarkdownBlankLines = regexp.MustCompile(`(\r?\n+\s*){2,}`)
+
+// Lower lower() get element text in lower case, return string.
+//	struct {
+//		Example string `pagser:".selector->lower()"`
+//	}
+func (builtin BuiltinFunctions) Lower(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return strings.ToLower(strings.TrimSpace(node.Text())), nil
+}
+
+// Markdown markdown() get element inner html and convert it to markdown, the runs of blank lines are collapsed,
+// return string.
+//	struct {
+//		Example string `pagser:".selector->markdown()"`
+//	}
+func (builtin BuiltinFunctions) Markdown(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	content, err := node.Html()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = godown.Convert(&buf, strings.NewReader(content), &godown.Option{
+		Style:  true,
+		Script: false,
+	})
+	if err != nil {
+		return "", fmt.Errorf("markdown() convert error: %v", err)
+	}
+	return strings.TrimSpace(rxMarkdownBlankLines.ReplaceAllString(buf.String(), "\n\n")), nil
+}
+
+// MetaMap metaMap() get the content of all `<meta>` elements in the elements keyed by the `name`, `property`,
+// `http-equiv` or `itemprop` attribute, the first one is used for the duplicate keys, return map[string]string.
+//	//<meta name="description" content="Pagser"><meta property="og:title" content="Title">
+//	struct {
+//		Example map[string]string `pagser:"head->metaMap()"` // {"description": "Pagser", "og:title": "Title"}
+//	}
+func (builtin BuiltinFunctions) MetaMap(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	metas := make(map[string]string)
+	node.Filter("meta").AddSelection(node.Find("meta")).Each(func(i int, meta *goquery.Selection) {
+		if charset, ok := meta.Attr("charset"); ok {
+			if _, exists := metas["charset"]; !exists {
+				metas["charset"] = strings.TrimSpace(charset)
+			}
+			return
+		}
+		for _, attr := range []string{"name", "property", "http-equiv", "itemprop"} {
+			key := strings.TrimSpace(meta.AttrOr(attr, ""))
+			if key == "" {
+				continue
+			}
+			if _, exists := metas[key]; !exists {
+				metas[key] = strings.TrimSpace(meta.AttrOr("content", ""))
+			}
+			return
+		}
+	})
+	return metas, nil
+}
+
+// Microdata microdata(type='') get the microdata items of `itemscope` elements, the properties are keyed by `itemprop`
+// and `@type` is the `itemtype`, all top level items are returned if type is empty, otherwise the first item of the type
+// is returned, eg: `Product` matches `https://schema.org/Product`, return json.RawMessage to unmarshal to the field of any type.
+//	struct {
+//		Product struct {
+//			Name  string `json:"name"`
+//			Offer struct {
+//				Price string `json:"price"`
+//			} `json:"offers"`
+//		} `pagser:"body->microdata(Product)"`
+//	}
+func (builtin BuiltinFunctions) Microdata(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	items := microdataItems(node)
+	var data interface{} = items
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		item := findJsonLdType(items, strings.TrimSpace(args[0]))
+		if item == nil {
+			return json.RawMessage("null"), nil
+		}
+		data = item
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("microdata(type) marshal error: %v", err)
+	}
+	return json.RawMessage(raw), nil
+}
+
+// Number number(locale='') get element text and parse the first number by the decimal separator of locale,
+// the group separators are removed, eg: `1.234,56` (de), `1,234.56` (en), `1 234,56` (fr), the locale can be
+// the decimal separator `.` or `,`, and it is detected from text if locale is empty, return float64.
+//	struct {
+//		Example float64 `pagser:".selector->number(de)"`
+//	}
+func (builtin BuiltinFunctions) Number(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	locale := ""
+	if len(args) > 0 {
+		locale = args[0]
+	}
+	value := strings.TrimSpace(node.Text())
+	number, err := parseLocaleNumber(value, locale)
+	if err != nil {
+		return 0.0, fmt.Errorf("number(locale) parse `%v` error: %v", value, err)
+	}
+	return number, nil
+}
+
+// Og og(property) get the content of the first `<meta>` element whose `property` or `name` is the property,
+// such as OpenGraph `og:title` and Twitter Card `twitter:card`, the `og:` prefix is added if property has no prefix,
+// return string.
+//	//<meta property="og:title" content="Pagser">
+//	struct {
+//		Example string `pagser:"head->og(og:title)"`
+//		Title   string `pagser:"head->og(title)"`
+//	}
+func (builtin BuiltinFunctions) Og(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", fmt.Errorf("og(property) must has property")
+	}
+	property := strings.TrimSpace(args[0])
+	if !strings.Contains(property, ":") {
+		property = "og:" + property
+	}
+	content := ""
+	node.Filter("meta").AddSelection(node.Find("meta")).EachWithBreak(func(i int, meta *goquery.Selection) bool {
+		if meta.AttrOr("property", "") == property || meta.AttrOr("name", "") == property {
+			content = strings.TrimSpace(meta.AttrOr("content", ""))
+			return false
+		}
+		return true
+	})
+	return content, nil
+}
+
+// OwnText ownText() get the direct text of elements, the text of child elements is excluded, return string.
+//	//<p>Price: <b>$10</b> only</p>
+//	struct {
+//		Example string `pagser:".selector->ownText()"`
+//	}
+func (builtin BuiltinFunctions) OwnText(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return strings.TrimSpace(strings.Join(ownTexts(node), "")), nil
+}
+
 // OutHtml outerHtml() get element  outer html, return string.
 //	struct {
 //		Example string `pagser:".selector->outerHtml()"`
@@ -345,6 +838,106 @@ func (builtin BuiltinFunctions) OutHtml(node *goquery.Selection, args ...string)
 	return html, nil
 }
 
+// Price price(locale='') get element text and parse the amount and currency of price, such as `£1,299.00`,
+// `USD 49.99`, `1.234,56 €`, the currency is ISO 4217 code of the code or symbol in text, the amount is parsed like
+// number(locale), return Price, or set the `Amount` and `Currency` fields if the field is a custom struct.
+//	struct {
+//		Example pagser.Price `pagser:".selector->price()"`
+//	}
+func (builtin BuiltinFunctions) Price(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	locale := ""
+	if len(args) > 0 {
+		locale = args[0]
+	}
+	value := strings.TrimSpace(node.Text())
+	price, err := parsePrice(value, locale)
+	if err != nil {
+		return Price{}, fmt.Errorf("price(locale) parse `%v` error: %v", value, err)
+	}
+	return price, nil
+}
+
+// Regex regex(pattern) get element text and returns the first capture group of the first match,
+// or the whole match if pattern has no group, empty string if not matched, return string.
+//	struct {
+//		Example int `pagser:".selector->regex('(\\d+) comments')"`
+//	}
+func (builtin BuiltinFunctions) Regex(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("regex(pattern) must has pattern")
+	}
+	rx, err := compileRegexp(args[0])
+	if err != nil {
+		return "", fmt.Errorf("regex(pattern) pattern `%v` is invalid: %v", args[0], err)
+	}
+	return regexpFind(rx, strings.TrimSpace(node.Text())), nil
+}
+
+// RegexAttr regexAttr(name, pattern) get element attribute value and returns the first capture group of the first match,
+// or the whole match if pattern has no group, empty string if not matched, return string.
+//	struct {
+//		Example string `pagser:".selector->regexAttr(href, '/item/(\\w+)')"`
+//	}
+func (builtin BuiltinFunctions) RegexAttr(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("regexAttr(name, pattern) must has name and pattern")
+	}
+	rx, err := compileRegexp(args[1])
+	if err != nil {
+		return "", fmt.Errorf("regexAttr(name, pattern) pattern `%v` is invalid: %v", args[1], err)
+	}
+	return regexpFind(rx, node.AttrOr(args[0], "")), nil
+}
+
+// RegexGroups regexGroups(pattern) get element text and returns the named capture groups of the first match,
+// empty map if not matched, return map[string]string, the groups are set to the struct fields by name.
+//	struct {
+//		Post struct {
+//			Author string
+//			Date   time.Time
+//		} `pagser:".meta->regexGroups('by (?P<author>\\w+) on (?P<date>[\\d-]+)')"`
+//	}
+func (builtin BuiltinFunctions) RegexGroups(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return map[string]string{}, fmt.Errorf("regexGroups(pattern) must has pattern")
+	}
+	rx, err := compileRegexp(args[0])
+	if err != nil {
+		return map[string]string{}, fmt.Errorf("regexGroups(pattern) pattern `%v` is invalid: %v", args[0], err)
+	}
+	groups := make(map[string]string)
+	matches := rx.FindStringSubmatch(strings.TrimSpace(node.Text()))
+	if len(matches) == 0 {
+		return groups, nil
+	}
+	for i, name := range rx.SubexpNames() {
+		if i > 0 && name != "" {
+			groups[name] = matches[i]
+		}
+	}
+	return groups, nil
+}
+
+// Replace replace(old, new) get element text and replace all old strings with new, return string.
+//	struct {
+//		Example string `pagser:".selector->replace('-', ' ')"`
+//	}
+func (builtin BuiltinFunctions) Replace(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("replace(old, new) must has old and new")
+	}
+	return strings.ReplaceAll(strings.TrimSpace(node.Text()), args[0], args[1]), nil
+}
+
+// Sanitize sanitize() get element inner html with the basic formatting tags such as `<p>`, `<b>`, `<a>`, `<ul>` kept,
+// all other tags are stripped like stripTags(...), return string.
+//	struct {
+//		Example string `pagser:".selector->sanitize()"`
+//	}
+func (builtin BuiltinFunctions) Sanitize(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return stripTags(node, sanitizeTags)
+}
+
 // Size size() returns the number of elements in the Selection object, return int.
 //	struct {
 //		Size int `pagser:".selector->size()"`
@@ -353,6 +946,102 @@ func (builtin BuiltinFunctions) Size(node *goquery.Selection, args ...string) (o
 	return node.Size(), nil
 }
 
+// SortStrings sortStrings(order='asc') get each element text and sort them in `asc` or `desc` order, it is usually
+// used after eachText() or textSplit() in pipeline, return []string.
+//	struct {
+//		Examples []string `pagser:".selector->eachText()->sortStrings(desc)"`
+//	}
+func (builtin BuiltinFunctions) SortStrings(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	desc := false
+	if len(args) > 0 {
+		switch strings.ToLower(strings.TrimSpace(args[0])) {
+		case "", "asc":
+		case "desc":
+			desc = true
+		default:
+			return nil, fmt.Errorf("sortStrings(order='asc') order must be asc or desc, but got `%v`", args[0])
+		}
+	}
+	list := make([]string, 0)
+	node.Each(func(i int, selection *goquery.Selection) {
+		list = append(list, strings.TrimSpace(selection.Text()))
+	})
+	if desc {
+		sort.Sort(sort.Reverse(sort.StringSlice(list)))
+	} else {
+		sort.Strings(list)
+	}
+	return list, nil
+}
+
+// Srcset srcset(name='srcset') get the image candidates of `srcset` attribute, the attribute name can be specified
+// for lazy loading like `data-srcset`, return []ImageCandidate.
+//	//<img srcset="/a-480.png 480w, /a-800.png 800w">
+//	struct {
+//		Examples []pagser.ImageCandidate `pagser:"img->srcset()"`
+//	}
+func (builtin BuiltinFunctions) Srcset(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	name := "srcset"
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		name = strings.TrimSpace(args[0])
+	}
+	return parseSrcset(node.AttrOr(name, "")), nil
+}
+
+// StripTags stripTags(tag1, [ tag2, ... tag_n ]) get element inner html with all tags stripped except the allowed tags,
+// the elements such as `<script>` and `<style>` are dropped with their content, the attributes except the safe
+// `href`, `src` and `alt` are dropped, return string.
+//	struct {
+//		Text    string `pagser:".selector->stripTags()"`
+//		Example string `pagser:".selector->stripTags('b', 'i', 'a')"`
+//	}
+func (builtin BuiltinFunctions) StripTags(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	value, err := stripTags(node, args)
+	if err != nil {
+		return "", fmt.Errorf("stripTags(tag1, [ tag2, ... tag_n ]) error: %v", err)
+	}
+	return value, nil
+}
+
+// StyleProp styleProp(name, unwrapUrl='false') get the property value of element inline `style` attribute,
+// the `url(...)` is unwrapped if unwrapUrl is true, empty if the property not found, return string.
+//	//<div style="color: red; background-image: url('/bg.png')">
+//	struct {
+//		Example string `pagser:".selector->styleProp(background-image, true)"` // /bg.png
+//	}
+func (builtin BuiltinFunctions) StyleProp(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("styleProp(name, unwrapUrl='false') must has name")
+	}
+	unwrapUrl := false
+	if len(args) > 1 {
+		unwrapUrl, err = cast.ToBoolE(args[1])
+		if err != nil {
+			return "", fmt.Errorf("`unwrapUrl` must bool type value: true/false")
+		}
+	}
+	value := styleProperties(node.AttrOr("style", ""))[strings.ToLower(strings.TrimSpace(args[0]))]
+	if unwrapUrl {
+		value = unwrapCssUrl(value)
+	}
+	return value, nil
+}
+
+// Table table() get the cells text of each row in the table element, the `thead`, `tbody` rows are in document order,
+// `th` and `td` cells are included, and the cells of `colspan` and `rowspan` are expanded, return [][]string.
+// The rows are set to the struct items by the header if the field is slice of struct, the first row is the header,
+// each cell is set to the field whose `header` tag or name matches the header text.
+//	struct {
+//		Examples [][]string `pagser:"table.stats->table()"`
+//		Rows []struct {
+//			Name  string  `header:"Product Name"`
+//			Price float64 `header:"Price"`
+//		} `pagser:"table.stats->table()"`
+//	}
+func (builtin BuiltinFunctions) Table(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return tableRows(node), nil
+}
+
 // Text text() get element  text, return string, this is default function, if not define function in struct tag.
 //	struct {
 //		Example string `pagser:".selector->text()"`
@@ -399,6 +1088,15 @@ func (builtin BuiltinFunctions) TextEmpty(node *goquery.Selection, args ...strin
 	return value, nil
 }
 
+// TextNormalized textNormalized() get element text, the runs of whitespaces and newlines are collapsed to single space,
+// the escaped entities in text such as `&amp;amp;` are unescaped, return string.
+//	struct {
+//		Example string `pagser:".selector->textNormalized()"`
+//	}
+func (builtin BuiltinFunctions) TextNormalized(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return normalizeSpace(html.UnescapeString(node.Text())), nil
+}
+
 // TextSplit textSplit(sep=',', trim='true') get element text and split by separator to array string, return []string.
 //	struct {
 //		Examples []string `pagser:".selector->textSplit('|')"`
@@ -424,3 +1122,257 @@ func (builtin BuiltinFunctions) TextSplit(node *goquery.Selection, args ...strin
 	}
 	return list, nil
 }
+
+// TextSplitRegex textSplitRegex(pattern, trim=true) get element text and split by the separators matched by regular
+// expression pattern, the items are trimmed and the empty items are removed if trim is true, return []string.
+//	struct {
+//		Examples []string `pagser:".selector->textSplitRegex('\\s*[,;|]\\s*')"`
+//	}
+func (builtin BuiltinFunctions) TextSplitRegex(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("textSplitRegex(pattern, trim=true) must has pattern")
+	}
+	rx, err := compileRegexp(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("textSplitRegex(pattern, trim=true) pattern `%v` is invalid: %v", args[0], err)
+	}
+	trim := true
+	if len(args) > 1 {
+		trim, err = cast.ToBoolE(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("`trim` must bool type value: true/false")
+		}
+	}
+	list := rx.Split(node.Text(), -1)
+	if !trim {
+		return list, nil
+	}
+	items := make([]string, 0, len(list))
+	for _, v := range list {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+	return items, nil
+}
+
+// TextTemplate textTemplate(template) get element text and attributes and render them by the Go text template,
+// the data of template are `.Text` the element text, `.Html` the inner html and `.Attr` the attributes of
+// the first element, the missing attribute is empty string, return string.
+//	//<a id="1" href="/foo">Foo</a>
+//	struct {
+//		Example string `pagser:".selector->textTemplate('{{.Text}} ({{.Attr.id}})')"`
+//	}
+func (builtin BuiltinFunctions) TextTemplate(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("textTemplate(template) must has template")
+	}
+	tpl, err := parseTemplate(args[0])
+	if err != nil {
+		return "", fmt.Errorf("textTemplate(template) template `%v` is invalid: %v", args[0], err)
+	}
+	content, err := node.Html()
+	if err != nil {
+		return "", err
+	}
+	data := struct {
+		Text string
+		Html string
+		Attr map[string]string
+	}{
+		Text: strings.TrimSpace(node.Text()),
+		Html: content,
+		Attr: nodeAttrs(node),
+	}
+	var sb strings.Builder
+	if err := tpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("textTemplate(template) execute error: %v", err)
+	}
+	return sb.String(), nil
+}
+
+// Title title() get element text with the first letter of each word in upper case and the other letters in lower case,
+// eg: `HELLO world` -> `Hello World`, return string.
+//	struct {
+//		Example string `pagser:".selector->title()"`
+//	}
+func (builtin BuiltinFunctions) Title(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return titleCase(strings.TrimSpace(node.Text())), nil
+}
+
+// Trim trim(cutset='') get element text and remove the leading and trailing cutset, white space is removed if cutset is empty,
+// return string.
+//	struct {
+//		Example string `pagser:".selector->text()->trim('$')"`
+//	}
+func (builtin BuiltinFunctions) Trim(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	text := node.Text()
+	if len(args) > 0 && args[0] != "" {
+		return strings.Trim(text, args[0]), nil
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// TrimPrefix trimPrefix(prefix) get element text without the leading prefix, return string.
+//	struct {
+//		Example string `pagser:".selector->trimPrefix('Price:')"`
+//	}
+func (builtin BuiltinFunctions) TrimPrefix(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("trimPrefix(prefix) must has prefix")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(node.Text()), args[0])), nil
+}
+
+// TrimSuffix trimSuffix(suffix) get element text without the trailing suffix, return string.
+//	struct {
+//		Example string `pagser:".selector->trimSuffix('views')"`
+//	}
+func (builtin BuiltinFunctions) TrimSuffix(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("trimSuffix(suffix) must has suffix")
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(node.Text()), args[0])), nil
+}
+
+// Truncate truncate(n, suffix='') get the first n characters of element text, the suffix such as `...` is appended
+// if the text is truncated, return string.
+//	struct {
+//		Example string `pagser:".selector->truncate(100, '...')"`
+//	}
+func (builtin BuiltinFunctions) Truncate(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("truncate(n, suffix='') must has n")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(args[0]))
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("truncate(n, suffix='') n must be non-negative integer, but got `%v`", args[0])
+	}
+	text := []rune(strings.TrimSpace(node.Text()))
+	if len(text) <= n {
+		return string(text), nil
+	}
+	suffix := ""
+	if len(args) > 1 {
+		suffix = args[1]
+	}
+	return strings.TrimSpace(string(text[:n])) + suffix, nil
+}
+
+// Unique unique() get each element text and remove the duplicate items, the first item is kept in order, it is usually
+// used after eachText() or eachAttr() in pipeline, return []string.
+//	struct {
+//		Examples []string `pagser:".tags a->eachText()->unique()"`
+//	}
+func (builtin BuiltinFunctions) Unique(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	list := make([]string, 0)
+	seen := make(map[string]bool)
+	node.Each(func(i int, selection *goquery.Selection) {
+		text := strings.TrimSpace(selection.Text())
+		if !seen[text] {
+			seen[text] = true
+			list = append(list, text)
+		}
+	})
+	return list, nil
+}
+
+// Upper upper() get element text in upper case, return string.
+//	struct {
+//		Example string `pagser:".selector->upper()"`
+//	}
+func (builtin BuiltinFunctions) Upper(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return strings.ToUpper(strings.TrimSpace(node.Text())), nil
+}
+
+// ownTexts returns the data of the direct text nodes of each element
+func ownTexts(node *goquery.Selection) []string {
+	texts := make([]string, 0)
+	for _, n := range node.Nodes {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				texts = append(texts, c.Data)
+			}
+		}
+	}
+	return texts
+}
+
+// styleProperties parses the declarations of inline style, the semicolons in quotes or parentheses are ignored,
+// the property names are lowercase.
+func styleProperties(style string) map[string]string {
+	props := make(map[string]string)
+	depth := 0
+	var quote rune
+	start := 0
+	declarations := make([]string, 0)
+	for pos, ch := range style {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')' && depth > 0:
+			depth--
+		case ch == ';' && depth == 0:
+			declarations = append(declarations, style[start:pos])
+			start = pos + 1
+		}
+	}
+	declarations = append(declarations, style[start:])
+	for _, declaration := range declarations {
+		name, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			props[name] = strings.TrimSpace(value)
+		}
+	}
+	return props
+}
+
+// unwrapCssUrl returns the url of css value like `url('/bg.png')`, or the value itself if it is not a url
+func unwrapCssUrl(value string) string {
+	text := strings.TrimSpace(value)
+	start := strings.Index(strings.ToLower(text), "url(")
+	if start < 0 {
+		return value
+	}
+	text = text[start+len("url("):]
+	end := strings.Index(text, ")")
+	if end < 0 {
+		return value
+	}
+	return strings.Trim(strings.TrimSpace(text[:end]), `'"`)
+}
+
+// normalizeSpace collapses the runs of whitespaces in text to single space and trims the text,
+// the unicode spaces such as `&nbsp;` are included.
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// titleCase returns the text with the first letter of each word in upper case and the other letters in lower case
+func titleCase(text string) string {
+	runes := []rune(text)
+	start := true
+	for i, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' {
+			if start {
+				runes[i] = unicode.ToUpper(r)
+			} else {
+				runes[i] = unicode.ToLower(r)
+			}
+			start = false
+		} else {
+			start = true
+		}
+	}
+	return string(runes)
+}
@@ -3,8 +3,10 @@ package pagser
 import (
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 type funcWantError struct {
@@ -23,6 +25,495 @@ func newTewSelection(content string) *goquery.Selection {
 	return doc.Selection.Find("body").Children()
 }
 
+type funcWantValue struct {
+	tag  string
+	data string
+	want interface{}
+}
+
+func (fwv funcWantValue) String() string {
+	return fmt.Sprintf("tag `%v` parse `%v`", fwv.tag, fwv.data)
+}
+
+// testFuncValues parses each data to the field of tag, the type of field is the type of want
+func testFuncValues(t *testing.T, p *Pagser, tests []funcWantValue) {
+	for _, tt := range tests {
+		field := reflect.StructField{
+			Name: "Value",
+			Type: reflect.TypeOf(tt.want),
+			Tag:  reflect.StructTag(fmt.Sprintf("%v:%q", p.Config.TagName, tt.tag)),
+		}
+		val := reflect.New(reflect.StructOf([]reflect.StructField{field}))
+		if err := p.Parse(val.Interface(), tt.data); err != nil {
+			t.Errorf("%v error: %v", tt.String(), err)
+			continue
+		}
+		if got := val.Elem().Field(0).Interface(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v got %#v, want %#v", tt.String(), got, tt.want)
+		}
+	}
+}
+
+func TestBuiltinFunctions(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	type postMeta struct {
+		Author string
+		Date   time.Time `layout:"2006-01-02"`
+		Views  int
+	}
+	type tableRow struct {
+		Name    string    `header:"Product Name"`
+		Price   float64   `header:"price"`
+		Date    time.Time `header:"Updated" layout:"2006/01/02"`
+		Stock   int
+		Missing string `header:"Missing"`
+	}
+	type searchForm struct {
+		Q    string
+		Page int
+		Sort string
+	}
+	type jsonLdOffer struct {
+		Price         string `json:"price"`
+		PriceCurrency string `json:"priceCurrency"`
+	}
+	type jsonLdProduct struct {
+		Name   string      `json:"name"`
+		Sku    string      `json:"sku"`
+		Offers jsonLdOffer `json:"offers"`
+	}
+	type microdataOffer struct {
+		Price    string `json:"price"`
+		Currency string `json:"priceCurrency"`
+	}
+	type microdataProduct struct {
+		Type   string         `json:"@type"`
+		Name   string         `json:"name"`
+		Image  string         `json:"image"`
+		Colors []string       `json:"color"`
+		Offers microdataOffer `json:"offers"`
+	}
+	type productPrice struct {
+		Amount   float64
+		Currency string
+	}
+	type groupText struct {
+		Value string `pagser:"->textNormalized()"`
+	}
+	type product struct {
+		Name    string `pagser:"h2"`
+		Stock   string `pagser:"->ifExists('.sold-out', 'no', 'yes')"`
+		Badge   string `pagser:"->ifExists('.badge', 'sale')"`
+		Missing string `pagser:".new->ifExists('', 'new', 'old')"`
+	}
+
+	regexHtml := `<a class="comments" href="/item/abc, ok">There are 12 comments (3 new)</a>`
+	metaHtml := `<p class="meta">Posted by Alice on 2024-01-02, 15 views</p>`
+	ownTextHtml := `<div class="product"><!-- id: 123 --><p class="price">Price: <b>$10</b> only<!-- sale --></p></div>`
+	attrOrHtml := `
+<img class="lazy" data-src="/lazy.png" src="/placeholder.png">
+<img class="origin" data-src=" " data-original="/origin.png" src="/placeholder.png">
+<img class="plain" src="/plain.png">
+<img class="none">
+`
+	dataAttrsHtml := `<div class="product" id="p1" data-id="123" data-user-name="Pagser" data-="x"></div><span class="stock" data-count="5"></span>`
+	classHtml := `<div class="product  item active"></div>`
+	styleHtml := `
+<div class="banner" style="color: red; Background-Image: url('/bg.png?a=1;b=2'); width:100px"></div>
+<div class="data" style="background: #fff url(&quot;data:image/png;base64,AAA=&quot;) no-repeat"></div>
+`
+	tableHtml := `
+<table class="stats">
+	<thead><tr><th>Name</th><th>Price</th></tr></thead>
+	<tbody>
+		<tr><td>Apple</td><td>1.5</td></tr>
+		<tr><td>Pear</td><td>2</td></tr>
+	</tbody>
+</table>
+<div class="spans"><table>
+	<tr><th rowspan="2">A</th><th colspan="2">B</th></tr>
+	<tr><td>b1</td><td>b2</td></tr>
+	<tr><td>a3</td><td rowspan="2" colspan="2">c</td></tr>
+	<tr><td>a4</td></tr>
+</table></div>
+<table class="outer">
+	<tr><td>x</td><td><table><tr><td>inner</td></tr></table></td></tr>
+</table>
+`
+	hugeSpansHtml := `
+<table>
+	<tr><td colspan="2000000000">a</td><td rowspan="2000000000">b</td></tr>
+	<tr><td rowspan="0">c</td></tr>
+	<tr><td>d</td></tr>
+</table>
+`
+	// the spans are clamped to 1000, so `a` spans 1000 columns and `b` spans all rows
+	hugeSpans := [][]string{make([]string, 1001), make([]string, 1001), make([]string, 1001)}
+	for i := 0; i < 1000; i++ {
+		hugeSpans[0][i] = "a"
+	}
+	for _, row := range hugeSpans {
+		row[1000] = "b"
+	}
+	hugeSpans[1][0], hugeSpans[2][0] = "c", "d"
+	tableRowsHtml := `
+<table>
+	<thead><tr><th>Product Name</th><th>Price</th><th>Stock</th><th>Updated</th></tr></thead>
+	<tbody>
+		<tr><td>Apple</td><td>1.5</td><td>10</td><td>2024/01/02</td></tr>
+		<tr><td>Pear</td><td>2</td></tr>
+	</tbody>
+</table>
+`
+	tableRows := []tableRow{
+		{Name: "Apple", Price: 1.5, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Stock: 10},
+		{Name: "Pear", Price: 2},
+	}
+	dlHtml := `
+<dl><dd>orphan</dd><dt>Weight:</dt><dd>1kg</dd><dt>Color</dt><dd>Red</dd><dd>Blue</dd><dt>Size</dt></dl>
+<div class="specs">
+	<div class="row"><span class="label">Brand</span><span class="value">Pagser</span></div>
+	<div class="row"><span class="label">Model</span><span class="value">P1</span></div>
+</div>
+`
+	formHtml := `
+<form id="login" action="/login" method="post">
+	<input type="text" name="user" value="pagser">
+	<input type="password" name="password">
+	<input type="checkbox" name="remember" checked>
+	<input type="hidden" name="token" value="abc" disabled>
+	<textarea name="note">hello</textarea>
+	<select name="lang"><option value="en">English</option><option value="zh" selected>Chinese</option></select>
+	<select name="tags" multiple><option>a</option><option>b</option></select>
+	<input type="submit" value="Login">
+</form>
+<form class="search" action="/search">
+	<input name="q" value="golang">
+	<input type="hidden" name="page" value="2">
+	<input type="radio" name="sort" value="new">
+	<input type="radio" name="sort" value="hot" checked>
+	<select name="size"><option>10</option><option>20</option></select>
+</form>
+`
+	loginForm := FormData{
+		Action: "/login",
+		Method: "POST",
+		Fields: []FormField{
+			{Name: "user", Type: "text", Value: "pagser"},
+			{Name: "password", Type: "password"},
+			{Name: "remember", Type: "checkbox", Value: "on", Checked: true},
+			{Name: "token", Type: "hidden", Value: "abc", Disabled: true},
+			{Name: "note", Type: "textarea", Value: "hello"},
+			{Name: "lang", Type: "select", Value: "zh", Options: []FormOption{{Value: "en", Text: "English"}, {Value: "zh", Text: "Chinese", Selected: true}}},
+			{Name: "tags", Type: "select", Multiple: true, Options: []FormOption{{Value: "a", Text: "a"}, {Value: "b", Text: "b"}}},
+		},
+	}
+	metaMapHtml := `<html><head>
+<meta charset="utf-8">
+<meta name="description" content=" Pagser example ">
+<meta property="og:title" content="Pagser">
+<meta property="og:image" content="/a.png">
+<meta property="og:image" content="/b.png">
+<meta http-equiv="refresh" content="30">
+<meta name="" content="ignored">
+</head><body></body></html>`
+	ogHtml := `<html><head>
+<meta property="og:title" content="Pagser">
+<meta property="og:type" content="website">
+<meta property="og:url" content="https://github.com/foolin/pagser">
+<meta property="og:image" content="https://example.com/a.png">
+<meta property="og:image" content="https://example.com/b.png">
+<meta property="og:description" content="Simple and deserialize html page to struct">
+<meta name="twitter:card" content="summary">
+<meta name="twitter:site" content="@pagser">
+</head><body></body></html>`
+	jsonLdHtml := `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "WebPage",
+	"mainEntity": {"@type": ["Product", "Thing"], "name": "Pagser", "sku": "P1", "offers": {"price": "9.99", "priceCurrency": "USD"}}}</script>
+<script type="application/ld+json">{"@graph": [{"@type": "http://schema.org/Article", "name": "News"}, {"@type": "BreadcrumbList", "itemListElement": []}]}</script>
+<script type="application/ld+json">{ invalid }</script>
+</head><body></body></html>`
+	microdataHtml := `<html><body>
+<div itemscope itemtype="https://schema.org/Product">
+	<h1 itemprop="name">Pagser</h1>
+	<img itemprop="image" src="/p.png">
+	<span itemprop="color">Red</span><span itemprop="color">Blue</span>
+	<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+		<span itemprop="price">9.99</span>
+		<meta itemprop="priceCurrency" content="USD">
+	</div>
+</div>
+<div itemscope itemtype="https://schema.org/Person"><span itemprop="name">Foolin</span></div>
+</body></html>`
+	microdataOffers := microdataOffer{Price: "9.99", Currency: "USD"}
+	srcsetHtml := `
+<img class="widths" srcset="/a-480.png 480w, /a-800.png 800w,/a.png">
+<img class="densities" data-srcset="/b.png, /b@2x.png 2x , /b@bad.png 2y, /b@3x.png 3x">
+<img class="data" srcset="data:image/png;base64,AA,BB 1x, /c.png 2x">
+`
+	numberHtml := `<span class="de">1.234,56 €</span><span class="en">12,345 views</span><span class="fr">1 234,5</span>`
+	priceHtml := `<span class="price">£1,299.00</span><span class="sale">1.099,00 €</span>`
+	humanHtml := `<span class="followers">1.2k followers</span><span class="views">3.4M</span><span class="votes">987</span>`
+	normalizedHtml := rawParseHtml + `<p> Tom &amp;amp;&nbsp;Jerry
+		show </p>`
+	contentHtml := `<div class="content"><p class="intro">Hello <b>World</b><span>!</span></p><script>alert(1)</script></div>`
+	stringHtml := `<h1> HELLO pagser's world </h1>
+		<span class="price">Price: $10</span>
+		<span class="views">1,234 views</span>
+		<span class="slug">go-html-parser</span>
+		<p>The quick brown fox jumps over the lazy dog</p>`
+	tagsHtml := `<div class="tags">go, html ;parser|  scraper;</div>`
+	tagCloudHtml := `<div class="tags" >
+		<a data-name="b,a,c">go</a><a>html</a><a> </a><a>go</a><a>css</a>
+	</div>`
+	sizeHtml := `<ul><li>Size: 42 (EU4)</li><li>Out of stock</li><li>Size: 43 (UK9)</li></ul>`
+	linkHtml := `<a id="1" href="/foo"> <b>Foo</b> </a>`
+
+	tests := []funcWantValue{
+		//regex and regexAttr
+		{".comments->regex('(\\d+) comments')", regexHtml, 12},
+		{".comments->regex('\\d+')", regexHtml, "12"},
+		{".comments->regex('views: (\\d+)')", regexHtml, ""},
+		{".comments->regexAttr(href, '/item/(\\w+), ok')", regexHtml, "abc"},
+		//regexGroups
+		{".meta->regexGroups('Posted by (?P<author>\\w+) on (?P<date>[\\d-]+), (?P<views>\\d+) views')", metaHtml,
+			postMeta{Author: "Alice", Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Views: 15}},
+		{".meta->regexGroups('Posted by (?P<Author>\\w+)')", metaHtml, &postMeta{Author: "Alice"}},
+		{".meta->regexGroups('on (?P<date>[\\d-]+)')", metaHtml, map[string]string{"date": "2024-01-02"}},
+		{".meta->regexGroups('Edited by (?P<author>\\w+)')", metaHtml, postMeta{}},
+		//exists
+		{"h1->exists()", rawParseHtml, true},
+		{".badge-sale->exists()", rawParseHtml, false},
+		//ownText, contents and comments
+		{".price->ownText()", ownTextHtml, "Price:  only"},
+		{".price->contents()", ownTextHtml, []string{"Price:", "only"}},
+		{".product->comments()", ownTextHtml, []string{"id: 123", "sale"}},
+		{".product->comments()->regex('id: (\\d+)')", ownTextHtml, 123},
+		//attrOr
+		{".lazy->attrOr(data-src, data-original, src, '')", attrOrHtml, "/lazy.png"},
+		{".origin->attrOr(data-src, data-original, src, '')", attrOrHtml, "/origin.png"},
+		{".plain->attrOr(data-src, data-original, src, '')", attrOrHtml, "/plain.png"},
+		{".none->attrOr(data-src, src, '/default.png')", attrOrHtml, "/default.png"},
+		//dataAttrs
+		{".product->dataAttrs()", dataAttrsHtml, map[string]string{"id": "123", "user-name": "Pagser"}},
+		{".stock->dataAttrs()", dataAttrsHtml, map[string]int{"count": 5}},
+		{".none->dataAttrs()", dataAttrsHtml, map[string]string{}},
+		//classList and hasClass
+		{".product->classList()", classHtml, []string{"product", "item", "active"}},
+		{".product->hasClass(active)", classHtml, true},
+		{".product->hasClass(sold-out)", classHtml, false},
+		{".none->classList()", classHtml, []string{}},
+		//styleProp
+		{".banner->styleProp(color)", styleHtml, "red"},
+		{".banner->styleProp(background-image)", styleHtml, "url('/bg.png?a=1;b=2')"},
+		{".banner->styleProp(BACKGROUND-IMAGE, true)", styleHtml, "/bg.png?a=1;b=2"},
+		{".data->styleProp(background, true)", styleHtml, "data:image/png;base64,AAA="},
+		{".banner->styleProp(width)->trim(px)", styleHtml, 100},
+		{".banner->styleProp(height)", styleHtml, ""},
+		//table
+		{"table.stats->table()", tableHtml, [][]string{{"Name", "Price"}, {"Apple", "1.5"}, {"Pear", "2"}}},
+		{".spans->table()", tableHtml, [][]string{{"A", "B", "B"}, {"A", "b1", "b2"}, {"a3", "c", "c"}, {"a4", "c", "c"}}},
+		{".outer->table()", tableHtml, [][]string{{"x", "inner"}}},
+		{".none->table()", tableHtml, [][]string{}},
+		{"table->table()", hugeSpansHtml, hugeSpans},
+		//table rows mapped by header
+		{"table->table()", tableRowsHtml, tableRows},
+		{"table->table()", tableRowsHtml, []*tableRow{&tableRows[0], &tableRows[1]}},
+		{".none->table()", tableRowsHtml, []tableRow{}},
+		//dlMap
+		{"dl->dlMap()", dlHtml, map[string]string{"Weight": "1kg", "Color": "Red, Blue"}},
+		{".specs->dlMap(.label, .value)", dlHtml, map[string]string{"Brand": "Pagser", "Model": "P1"}},
+		{".none->dlMap()", dlHtml, map[string]string{}},
+		//form
+		{"#login->form()", formHtml, loginForm},
+		{".search->form()", formHtml, searchForm{Q: "golang", Page: 2, Sort: "hot"}},
+		{"body->form()", formHtml, &loginForm},
+		//metaMap
+		{"head->metaMap()", metaMapHtml, map[string]string{
+			"charset":     "utf-8",
+			"description": "Pagser example",
+			"og:title":    "Pagser",
+			"og:image":    "/a.png",
+			"refresh":     "30",
+		}},
+		{"body->metaMap()", metaMapHtml, map[string]string{}},
+		//og
+		{"head", ogHtml, OpenGraph{
+			Title:       "Pagser",
+			Type:        "website",
+			URL:         "https://github.com/foolin/pagser",
+			Image:       "https://example.com/a.png",
+			Description: "Simple and deserialize html page to struct",
+			TwitterCard: "summary",
+			TwitterSite: "@pagser",
+		}},
+		{"head->og(title)", ogHtml, "Pagser"},
+		{"meta->og(twitter:card)", ogHtml, "summary"},
+		{"head->og(og:missing)", ogHtml, ""},
+		//jsonLd
+		{"html->jsonLd()", jsonLdHtml, []map[string]interface{}{
+			{"@context": "https://schema.org", "@type": "WebPage", "mainEntity": map[string]interface{}{
+				"@type": []interface{}{"Product", "Thing"}, "name": "Pagser", "sku": "P1",
+				"offers": map[string]interface{}{"price": "9.99", "priceCurrency": "USD"},
+			}},
+			{"@type": "http://schema.org/Article", "name": "News"},
+			{"@type": "BreadcrumbList", "itemListElement": []interface{}{}},
+		}},
+		{"html->jsonLd(Product)", jsonLdHtml, jsonLdProduct{Name: "Pagser", Sku: "P1", Offers: jsonLdOffer{Price: "9.99", PriceCurrency: "USD"}}},
+		{"html->jsonLd(Article)", jsonLdHtml, &jsonLdProduct{Name: "News"}},
+		{"html->jsonLd(BreadcrumbList)", jsonLdHtml, map[string]interface{}{"@type": "BreadcrumbList", "itemListElement": []interface{}{}}},
+		{"html->jsonLd(Event)", jsonLdHtml, jsonLdProduct{}},
+		//microdata
+		{"body->microdata()", microdataHtml, []map[string]interface{}{
+			{"@type": "https://schema.org/Product", "name": "Pagser", "image": "/p.png", "color": []interface{}{"Red", "Blue"},
+				"offers": map[string]interface{}{"@type": "https://schema.org/Offer", "price": "9.99", "priceCurrency": "USD"}},
+			{"@type": "https://schema.org/Person", "name": "Foolin"},
+		}},
+		{"body->microdata(Product)", microdataHtml, microdataProduct{
+			Type:   "https://schema.org/Product",
+			Name:   "Pagser",
+			Image:  "/p.png",
+			Colors: []string{"Red", "Blue"},
+			Offers: microdataOffers,
+		}},
+		{"body->microdata(Offer)", microdataHtml, microdataOffers},
+		{"body->microdata(Event)", microdataHtml, (*microdataProduct)(nil)},
+		//links, the relative urls are kept without base url
+		{"head->links()", `<link rel="canonical" href="/post-1">`, PageLinks{
+			Canonical:  "/post-1",
+			Alternates: []PageLink{},
+			Icons:      []PageLink{},
+			All:        []PageLink{{Rel: "canonical", Href: "/post-1"}},
+		}},
+		//srcset
+		{".widths->srcset()", srcsetHtml, []ImageCandidate{{URL: "/a-480.png", Width: 480}, {URL: "/a-800.png", Width: 800}, {URL: "/a.png", Density: 1}}},
+		{".densities->srcset(data-srcset)", srcsetHtml, []ImageCandidate{{URL: "/b.png", Density: 1}, {URL: "/b@2x.png", Density: 2}, {URL: "/b@3x.png", Density: 3}}},
+		{".data->srcset()", srcsetHtml, []ImageCandidate{{URL: "data:image/png;base64,AA,BB", Density: 1}, {URL: "/c.png", Density: 2}}},
+		{".none->srcset()", srcsetHtml, []ImageCandidate{}},
+		//dateFuzzy
+		{"span->dateFuzzy()", `<span>2 days ago</span>`, now.AddDate(0, 0, -2)},
+		{"span->dateFuzzy()", `<span>Jan 3</span>`, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		//number
+		{".de->number(de)", numberHtml, 1234.56},
+		{".en->number(en)", numberHtml, 12345},
+		{".fr->number()", numberHtml, float32(1234.5)},
+		{".de->number(',')", numberHtml, 1234.56},
+		//price
+		{".price->price()", priceHtml, Price{Amount: 1299, Currency: "GBP"}},
+		{".sale->price(de)", priceHtml, productPrice{Amount: 1099, Currency: "EUR"}},
+		{".price->price()", priceHtml, &Price{Amount: 1299, Currency: "GBP"}},
+		//humanNumber
+		{".followers->humanNumber()", humanHtml, 1200},
+		{".views->humanNumber()", humanHtml, int64(3400000)},
+		{".votes->humanNumber()", humanHtml, float64(987)},
+		//textNormalized
+		{".group", normalizedHtml, []groupText{
+			{"Email pagser@foolin.github hello@pagser.foolin"},
+			{"Bool true false"},
+			{"Number 12345 67890"},
+			{"Float 123.45 678.90"},
+		}},
+		{".words->textNormalized()", normalizedHtml, "A|B|C|D"},
+		{"p->textNormalized()", normalizedHtml, "Tom & Jerry show"},
+		//innerText
+		{"article->innerText()", `<article>
+		<h1>Title</h1>
+		<p>First <b>paragraph</b>.</p>
+		<p>Second<br>line</p>
+	</article>`, "Title\n\nFirst paragraph.\n\nSecond\nline"},
+		//stripTags and sanitize
+		{".content->stripTags()", contentHtml, "Hello World!"},
+		{".content->stripTags('b')", contentHtml, "Hello <b>World</b>!"},
+		{".content->sanitize()", contentHtml, "<p>Hello <b>World</b>!</p>"},
+		//markdown
+		{"article->markdown()", `<article><h1>Title</h1><p>Hello <b>World</b> and <a href="/docs">docs</a></p>
+
+
+	<ul><li>A</li><li>B</li></ul></article>`, "# Title\n\nHello **World** and [docs](/docs)\n\n* A\n* B"},
+		//lower, upper, title, trimPrefix, trimSuffix, replace and truncate
+		{"h1->lower()", stringHtml, "hello pagser's world"},
+		{"h1->upper()", stringHtml, "HELLO PAGSER'S WORLD"},
+		{"h1->title()", stringHtml, "Hello Pagser's World"},
+		{".price->trimPrefix('Price:')", stringHtml, "$10"},
+		{".views->trimSuffix('views')", stringHtml, "1,234"},
+		{".slug->replace('-', ' ')", stringHtml, "go html parser"},
+		{"p->truncate(10, '...')", stringHtml, "The quick..."},
+		{".slug->truncate(20, '...')", stringHtml, "go-html-parser"},
+		{".slug->replace('-', ' ')->title()", stringHtml, "Go Html Parser"},
+		//textSplitRegex
+		{".tags->textSplitRegex('\\s*[,;|]\\s*')", tagsHtml, []string{"go", "html", "parser", "scraper"}},
+		{".tags->textSplitRegex('[,;|]', false)", tagsHtml, []string{"go", " html ", "parser", "  scraper", ""}},
+		//unique, compact and sortStrings
+		{".tags a->eachText()->unique()", tagCloudHtml, []string{"go", "html", "", "css"}},
+		{".tags a->eachText()->compact()", tagCloudHtml, []string{"go", "html", "go", "css"}},
+		{".tags a->eachText()->compact()->unique()->sortStrings()", tagCloudHtml, []string{"css", "go", "html"}},
+		{".tags a->attr(data-name)->textSplit(',')->sortStrings(desc)", tagCloudHtml, []string{"c", "b", "a"}},
+		{".tags a->unique()", tagCloudHtml, []string{"go", "html", "", "css"}},
+		//eachRegex
+		{"li->eachRegex('Size: (\\d+)')", sizeHtml, []int{42, 43}},
+		{"li->eachRegex('[A-Z]{2}\\d')", sizeHtml, []string{"EU4", "UK9"}},
+		//textTemplate
+		{"a->textTemplate('{{.Text}} ({{.Attr.id}})')", linkHtml, "Foo (1)"},
+		{"a->textTemplate('{{.Text}}{{.Attr.title}}')", linkHtml, "Foo"},
+		{"a->textTemplate('[{{.Html}}]({{.Attr.href}})')", linkHtml, "[ <b>Foo</b> ](/foo)"},
+		{"a->textTemplate('{{printf \"%s-%s\" .Attr.id .Text}}')", linkHtml, "1-Foo"},
+		//ifExists
+		{".product", `<div class="product"><h2>A</h2><span class="badge">-10%</span></div>
+		<div class="product sold-out"><h2>B</h2></div>`, []product{
+			{Name: "A", Stock: "yes", Badge: "sale", Missing: "old"},
+			{Name: "B", Stock: "no", Badge: "", Missing: "old"},
+		}},
+	}
+
+	testFuncValues(t, New(), tests)
+
+	// the relative urls of links are resolved against the base url
+	p, err := NewWithConfig(Config{TagName: "pagser", FuncSymbol: "->", BaseURL: "https://example.com/blog/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFuncValues(t, p, []funcWantValue{
+		{"head->links()", `<html><head>
+<link rel="canonical" href="/blog/post-1">
+<link rel="next" href="post-1?page=2">
+<link rel="prev" href="https://example.com/blog/post-0">
+<link rel="alternate" hreflang="en" href="/en/post-1">
+<link rel="alternate" hreflang="x-default" href="/post-1">
+<link rel="alternate" type="application/rss+xml" title="Feed" href="/feed.xml">
+<link rel="shortcut icon" href="/favicon.ico">
+<link rel="apple-touch-icon" sizes="180x180" href="/apple.png">
+<link rel="stylesheet" href="/style.css">
+</head><body></body></html>`, PageLinks{
+			Canonical: "https://example.com/blog/post-1",
+			Next:      "https://example.com/blog/post-1?page=2",
+			Prev:      "https://example.com/blog/post-0",
+			Alternates: []PageLink{
+				{Rel: "alternate", Href: "https://example.com/en/post-1", HrefLang: "en"},
+				{Rel: "alternate", Href: "https://example.com/post-1", HrefLang: "x-default"},
+				{Rel: "alternate", Href: "https://example.com/feed.xml", Type: "application/rss+xml", Title: "Feed"},
+			},
+			Icons: []PageLink{
+				{Rel: "shortcut icon", Href: "https://example.com/favicon.ico"},
+				{Rel: "apple-touch-icon", Href: "https://example.com/apple.png", Sizes: "180x180"},
+			},
+			All: []PageLink{
+				{Rel: "canonical", Href: "https://example.com/blog/post-1"},
+				{Rel: "next", Href: "https://example.com/blog/post-1?page=2"},
+				{Rel: "prev", Href: "https://example.com/blog/post-0"},
+				{Rel: "alternate", Href: "https://example.com/en/post-1", HrefLang: "en"},
+				{Rel: "alternate", Href: "https://example.com/post-1", HrefLang: "x-default"},
+				{Rel: "alternate", Href: "https://example.com/feed.xml", Type: "application/rss+xml", Title: "Feed"},
+				{Rel: "shortcut icon", Href: "https://example.com/favicon.ico"},
+				{Rel: "apple-touch-icon", Href: "https://example.com/apple.png", Sizes: "180x180"},
+				{Rel: "stylesheet", Href: "https://example.com/style.css"},
+			},
+		}},
+	})
+}
+
 func TestBuiltinFunctionsErrors(t *testing.T) {
 	tests := []funcWantError{
 		//not baseUrl
@@ -41,10 +532,20 @@ func TestBuiltinFunctionsErrors(t *testing.T) {
 		{true, "attrConcat", []string{"href", "$value"}, `<a href="/foo">a</a>`},
 		//not default value
 		{true, "attrEmpty", []string{"href"}, `<a href="/foo">a</a>`},
+		//not default value
+		{true, "attrOr", []string{"href"}, `<a href="/foo">a</a>`},
 		//not attr name
 		{true, "attrSplit", []string{}, `<a href="/foo">a</a>`},
 		//trim value '1234' is not bool type
 		{true, "attrSplit", []string{"href", "|", "1234"}, `<a href="/foo">a</a>`},
+		//text not match layout
+		{true, "date", []string{"2006-01-02"}, `<span>2020/04/25</span>`},
+		//text not a date
+		{true, "date", []string{}, `<span>abc</span>`},
+		//text not a fuzzy date
+		{true, "dateFuzzy", []string{}, `<span>abc</span>`},
+		//text not a duration
+		{true, "duration", []string{}, `<span>abc</span>`},
 		//not attr name
 		{true, "eachAttr", []string{}, `<a href="/foo">a</a>`},
 		//not default value
@@ -71,14 +572,75 @@ func TestBuiltinFunctionsErrors(t *testing.T) {
 		{true, "eqAndText", []string{}, `<a href="/foo">a</a>`},
 		//not name value
 		{true, "eqAndText", []string{"a"}, `<a href="/foo">a</a>`},
+		//not class name
+		{true, "hasClass", []string{}, `<a class="foo">a</a>`},
+		//text not a number
+		{true, "number", []string{}, `<span>abc</span>`},
+		{true, "humanNumber", []string{}, `<span>k</span>`},
+		//empty tag
+		{true, "stripTags", []string{"b", ""}, `<p><b>a</b></p>`},
+		//not prefix
+		{true, "trimPrefix", []string{}, `<p>a</p>`},
+		//not suffix
+		{true, "trimSuffix", []string{}, `<p>a</p>`},
+		//not new
+		{true, "replace", []string{"a"}, `<p>a</p>`},
+		//not n
+		{true, "truncate", []string{}, `<p>a</p>`},
+		//n not integer
+		{true, "truncate", []string{"-1"}, `<p>a</p>`},
+		//locale invalid
+		{true, "number", []string{"english"}, `<span>1,234</span>`},
+		//not property
+		{true, "og", []string{}, `<meta property="og:title" content="a">`},
+		//text not a price
+		{true, "price", []string{}, `<span>free</span>`},
+		//not valid json
+		{true, "json", []string{}, `<script>{"a": }</script>`},
 		//html error
 		//{true, "outerHtml", []string{"a"}, `</aa</a<>`},
+		//not pattern
+		{true, "regex", []string{}, `<a href="/foo">a</a>`},
+		//pattern invalid
+		{true, "regex", []string{"(a"}, `<a href="/foo">a</a>`},
+		//not pattern
+		{true, "regexAttr", []string{"href"}, `<a href="/foo">a</a>`},
+		//pattern invalid
+		{true, "regexAttr", []string{"href", "(a"}, `<a href="/foo">a</a>`},
+		//not pattern
+		{true, "regexGroups", []string{}, `<a href="/foo">a</a>`},
+		//pattern invalid
+		{true, "regexGroups", []string{"(?P<a"}, `<a href="/foo">a</a>`},
+		//not property name
+		{true, "styleProp", []string{}, `<a style="color: red">a</a>`},
+		//unwrapUrl not bool
+		{true, "styleProp", []string{"color", "1.2"}, `<a style="color: red">a</a>`},
 		//not args
 		{true, "textConcat", []string{"$value"}, `</a>a</a>`},
 		//not args
 		{true, "textEmpty", []string{}, `<a href="/foo">a</a>`},
 		//not bool
 		{true, "textSplit", []string{",", "1.2"}, `<a href="/foo">a</a>`},
+		//not pattern
+		{true, "eachRegex", []string{}, `<a href="/foo">a</a>`},
+		//pattern invalid
+		{true, "eachRegex", []string{"(a"}, `<a href="/foo">a</a>`},
+		//not then value
+		{true, "ifExists", []string{".badge"}, `<a href="/foo">a</a>`},
+		//not template
+		{true, "textTemplate", []string{}, `<a href="/foo">a</a>`},
+		//template invalid
+		{true, "textTemplate", []string{"{{.Text"}, `<a href="/foo">a</a>`},
+		//template execute error
+		{true, "textTemplate", []string{"{{.Unknown}}"}, `<a href="/foo">a</a>`},
+		//order invalid
+		{true, "sortStrings", []string{"up"}, `<a href="/foo">a</a>`},
+		//not pattern
+		{true, "textSplitRegex", []string{}, `<a href="/foo">a</a>`},
+		//pattern invalid
+		{true, "textSplitRegex", []string{"[a"}, `<a href="/foo">a</a>`},
+		//trim not bool
+		{true, "textSplitRegex", []string{",", "1.2"}, `<a href="/foo">a</a>`},
 	}
 
 	for _, tt := range tests {
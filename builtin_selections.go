@@ -11,6 +11,13 @@ import (
 type BuiltinSelections struct {
 }
 
+// SelectionMap the keyed sub selections for map field, any function returns *SelectionMap will be parsed to map field,
+// the keys are cast to the key type of map, and the values are parsed as the nested value of map.
+type SelectionMap struct {
+	Keys   []string
+	Values []*goquery.Selection
+}
+
 // Child child(selector='') gets the child elements of each element in the Selection,
 // Filtered by the specified selector if selector not empty,
 // It returns Selection object containing these elements for nested struct..
@@ -30,6 +37,21 @@ func (builtin BuiltinSelections) Child(node *goquery.Selection, args ...string)
 	return node.Children(), nil
 }
 
+// Closest closest(selector) gets the first element that matches the selector by testing the element itself
+// and traversing up through its ancestors for each element in the Selection.
+// It returns Selection object containing these elements for nested struct.
+//	struct {
+//		SubStruct struct {
+//			Example string `pagser:".selector->text()"`
+//		}	`pagser:".selector->closest('.item')"`
+//	}
+func (builtin BuiltinSelections) Closest(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", fmt.Errorf("closest(selector) must has `selector` value")
+	}
+	return node.Closest(strings.TrimSpace(args[0])), nil
+}
+
 // Eq eq(index) reduces the set of matched elements to the one at the specified index.
 // If a negative index is given, it counts backwards starting at the end of the set.
 // It returns a Selection object for nested struct, and an empty Selection object if the
@@ -51,6 +73,33 @@ func (builtin BuiltinSelections) Eq(node *goquery.Selection, args ...string) (ou
 	return node.Eq(idx), nil
 }
 
+// Filter filter(selector) reduces the set of matched elements to those that match the selector.
+// It returns Selection object containing these elements for nested struct or slice.
+//	struct {
+//		Examples []string `pagser:"li->filter('.active')"`
+//	}
+func (builtin BuiltinSelections) Filter(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", fmt.Errorf("filter(selector) must has `selector` value")
+	}
+	return node.Filter(strings.TrimSpace(args[0])), nil
+}
+
+// Find find(selector) gets the descendants of each element in the Selection that match the selector,
+// it refines the selection after other node functions, eg: `->parent()->find('.meta')`.
+// It returns Selection object containing these elements for nested struct or slice.
+//	struct {
+//		SubStruct struct {
+//			Example string `pagser:".selector->text()"`
+//		}	`pagser:".selector->parent()->find('.meta')"`
+//	}
+func (builtin BuiltinSelections) Find(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", fmt.Errorf("find(selector) must has `selector` value")
+	}
+	return node.Find(strings.TrimSpace(args[0])), nil
+}
+
 // First first() First reduces the set of matched elements to the first in the set.
 // It returns a new Selection object, and an empty Selection object if the
 // the selection is empty.
@@ -64,6 +113,18 @@ func (builtin BuiltinSelections) First(node *goquery.Selection, args ...string)
 	return node.First(), nil
 }
 
+// Has has(selector) reduces the set of matched elements to those that have a descendant that matches the selector.
+// It returns Selection object containing these elements for nested struct or slice.
+//	struct {
+//		Examples []string `pagser:".card->has('img')"`
+//	}
+func (builtin BuiltinSelections) Has(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", fmt.Errorf("has(selector) must has `selector` value")
+	}
+	return node.Has(strings.TrimSpace(args[0])), nil
+}
+
 // Last last(selector='') reduces the set of matched elements to the last in the set.
 // It returns a new Selection object, and an empty Selection object if
 // the selection is empty.
@@ -76,6 +137,36 @@ func (builtin BuiltinSelections) Last(node *goquery.Selection, args ...string) (
 	return node.Last(), nil
 }
 
+// MapOf mapOf(keySelector, valueSelector='') gets the text of keySelector as key and the elements of valueSelector as value
+// for each element in the Selection, the element itself is the value if valueSelector is empty.
+// It returns *SelectionMap object for map field.
+//	struct {
+//		Examples map[string][]string `pagser:".group->mapOf(h2, .item)"`
+//	}
+func (builtin BuiltinSelections) MapOf(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("mapOf(keySelector, valueSelector) must has keySelector")
+	}
+	keySelector := strings.TrimSpace(args[0])
+	valueSelector := ""
+	if len(args) > 1 {
+		valueSelector = strings.TrimSpace(args[1])
+	}
+	selMap := &SelectionMap{
+		Keys:   make([]string, 0, node.Size()),
+		Values: make([]*goquery.Selection, 0, node.Size()),
+	}
+	node.Each(func(i int, selection *goquery.Selection) {
+		value := selection
+		if valueSelector != "" {
+			value = selection.Find(valueSelector)
+		}
+		selMap.Keys = append(selMap.Keys, strings.TrimSpace(selection.Find(keySelector).First().Text()))
+		selMap.Values = append(selMap.Values, value)
+	})
+	return selMap, nil
+}
+
 // Next next(selector='') gets the immediately following sibling of each element in the Selection.
 // Filtered by the specified selector if selector not empty,
 // It returns Selection object containing these elements for nested struct.
@@ -95,6 +186,18 @@ func (builtin BuiltinSelections) Next(node *goquery.Selection, args ...string) (
 	return node.Next(), nil
 }
 
+// Not not(selector) removes elements from the set of matched elements that match the selector.
+// It returns Selection object containing these elements for nested struct or slice.
+//	struct {
+//		Examples []string `pagser:"li->not('.ad')"`
+//	}
+func (builtin BuiltinSelections) Not(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", fmt.Errorf("not(selector) must has `selector` value")
+	}
+	return node.Not(strings.TrimSpace(args[0])), nil
+}
+
 // Parent parent(selector='') gets the parent elements of each element in the Selection.
 // Filtered by the specified selector if selector not empty,
 // It returns Selection object containing these elements for nested struct.
@@ -169,6 +272,15 @@ func (builtin BuiltinSelections) Prev(node *goquery.Selection, args ...string) (
 	return node.Prev(), nil
 }
 
+// Selection selection() returns the matched Selection object itself,
+// it is used to set the matched elements to *goquery.Selection field for advanced processing.
+//	struct {
+//		Example *goquery.Selection `pagser:".selector->selection()"`
+//	}
+func (builtin BuiltinSelections) Selection(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return node, nil
+}
+
 // Siblings siblings() gets the siblings of each element in the Selection.
 // Filtered by the specified selector if selector not empty,
 // It returns Selection object containing these elements for nested struct.
@@ -187,3 +299,53 @@ func (builtin BuiltinSelections) Siblings(node *goquery.Selection, args ...strin
 	}
 	return node.Siblings(), nil
 }
+
+// Slice slice(start, end='') reduces the set of matched elements to the range from start to end (exclusive).
+// If a negative index is given, it counts backwards starting at the end of the set,
+// the range is to the end of the set if end is empty, and out of range indexes are clamped.
+// It returns Selection object containing these elements for nested struct or slice.
+//	struct {
+//		Rows []struct {
+//			Example string `pagser:".selector->text()"`
+//		}	`pagser:"tr->slice(1, -1)"`
+//	}
+func (builtin BuiltinSelections) Slice(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("slice(start, end='') must has `start` value")
+	}
+	size := node.Size()
+	start, err := sliceIndex(args[0], size)
+	if err != nil {
+		return "", err
+	}
+	end := size
+	if len(args) > 1 && strings.TrimSpace(args[1]) != "" {
+		end, err = sliceIndex(args[1], size)
+		if err != nil {
+			return "", err
+		}
+	}
+	if start > end {
+		start = end
+	}
+	return node.Slice(start, end), nil
+}
+
+// sliceIndex parses the index of slice(start, end), negative index counts backwards and the result is clamped to [0, size]
+func sliceIndex(value string, size int) (int, error) {
+	indexValue := strings.TrimSpace(value)
+	idx, err := strconv.Atoi(indexValue)
+	if err != nil {
+		return 0, fmt.Errorf("index=`" + indexValue + "` is not number: " + err.Error())
+	}
+	if idx < 0 {
+		idx += size
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > size {
+		idx = size
+	}
+	return idx, nil
+}
@@ -4,15 +4,71 @@ import (
 	"testing"
 )
 
+func TestBuiltinSelections(t *testing.T) {
+	type navItem struct {
+		Name string `pagser:"a"`
+	}
+	type navID struct {
+		ID int `pagser:"->attr(id)"`
+	}
+	type group struct {
+		Title string `pagser:"h2"`
+	}
+	type linkName struct {
+		Name string `pagser:"->text()"`
+	}
+	tests := []funcWantValue{
+		//slice
+		{".navlink li->slice(1, -1)", rawParseHtml, []string{"Web page", "Pc Page"}},
+		{".navlink li->slice(-2)", rawParseHtml, []string{"Pc Page", "Mobile Page"}},
+		{".navlink li->slice(0, 2)", rawParseHtml, []navItem{{"Index"}, {"Web page"}}},
+		{".navlink li->slice(3, 1)", rawParseHtml, []string{}},
+		{".navlink li->slice(-10, 10)->eachText()", rawParseHtml, []string{"Index", "Web page", "Pc Page", "Mobile Page"}},
+		//filter, not and has
+		{".navlink a->filter('[title]')", rawParseHtml, []string{"Web page", "Pc Page", "Mobile Page"}},
+		{".navlink li->not(':first-child')->eachText()", rawParseHtml, []string{"Web page", "Pc Page", "Mobile Page"}},
+		{".navlink li->has('a[title]')", rawParseHtml, []navID{{2}, {3}, {4}}},
+		{".group->has('.item')->size()", rawParseHtml, 4},
+		//closest
+		{".item[name=email]->first()->closest('.group')->attr(id)", rawParseHtml, "a"},
+		{".group->first()->closest('.group')->attr(id)", rawParseHtml, "a"},
+		{".item->first()->closest('div')", rawParseHtml, group{"Email"}},
+		{"h1->closest('.group')->size()", rawParseHtml, 0},
+		//find
+		{"h2->first()->parent()->find('.item')->eachText()", rawParseHtml, []string{"pagser@foolin.github", "hello@pagser.foolin"}},
+		{".navlink li->eq(1)->parent()->find('a[title]')", rawParseHtml, []linkName{{"Web page"}, {"Pc Page"}, {"Mobile Page"}}},
+	}
+
+	testFuncValues(t, New(), tests)
+}
+
 //test errors
 func TestBuiltinSelectionsErrors(t *testing.T) {
 	tests := []funcWantError{
+		//not args
+		{true, "closest", []string{}, ``},
 		//not args
 		{true, "eq", []string{}, ``},
 		//index not number
 		{true, "eq", []string{"a"}, ``},
 		//not args
+		{true, "filter", []string{}, ``},
+		//not args
+		{true, "find", []string{}, ``},
+		//not args
+		{true, "has", []string{}, ``},
+		//not args
+		{true, "mapOf", []string{}, ``},
+		//not args
+		{true, "not", []string{" "}, ``},
+		//not args
 		{true, "parentsUntil", []string{}, ``},
+		//not args
+		{true, "slice", []string{}, ``},
+		//start not number
+		{true, "slice", []string{"a"}, ``},
+		//end not number
+		{true, "slice", []string{"0", "b"}, ``},
 	}
 
 	for _, tt := range tests {
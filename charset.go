@@ -0,0 +1,48 @@
+package pagser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
+)
+
+// newDocumentFromReader reads and decodes the html of reader to UTF-8 by its charset and parses it to document,
+// the charset is determined by the BOM, the charset of contentType, such as the `Content-Type` header of response,
+// or the `<meta charset>` of html, in that order. The html which is valid UTF-8 is never decoded without the BOM or
+// contentType, because the charset of `<meta>` or the default `windows-1252` are only guesses.
+func (p *Pagser) newDocumentFromReader(reader io.Reader, contentType string) (*goquery.Document, error) {
+	content, err := p.readDocument(reader)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(decodeCharset(content, contentType))
+}
+
+// readDocument reads all bytes of document, at most Config.MaxDocumentBytes bytes are read
+func (p *Pagser) readDocument(reader io.Reader) ([]byte, error) {
+	limit := p.Config.MaxDocumentBytes
+	if limit <= 0 {
+		return io.ReadAll(reader)
+	}
+	content, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("%w: the limit is %v bytes", ErrDocumentTooLarge, limit)
+	}
+	return content, nil
+}
+
+// decodeCharset returns the reader of UTF-8 html decoded from content, see newDocumentFromReader
+func decodeCharset(content []byte, contentType string) io.Reader {
+	enc, name, certain := charset.DetermineEncoding(content, contentType)
+	if name == "utf-8" || (!certain && utf8.Valid(content)) {
+		return bytes.NewReader(content)
+	}
+	return enc.NewDecoder().Reader(bytes.NewReader(content))
+}
@@ -0,0 +1,115 @@
+package pagser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+type charsetData struct {
+	Title string `pagser:"h1"`
+}
+
+func encodeCharset(t *testing.T, enc encoding.Encoding, s string) []byte {
+	data, err := enc.NewEncoder().Bytes([]byte(s))
+	require.NoError(t, err)
+	return data
+}
+
+func TestPagser_ParseReaderCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		document []byte
+		title    string
+	}{
+		{
+			name:     "gbk meta",
+			document: encodeCharset(t, simplifiedchinese.GBK, `<meta charset="gbk"><h1>你好，世界</h1>`),
+			title:    "你好，世界",
+		},
+		{
+			name: "windows-1251 meta",
+			document: encodeCharset(t, charmap.Windows1251,
+				`<meta http-equiv="Content-Type" content="text/html; charset=windows-1251"><h1>Привет</h1>`),
+			title: "Привет",
+		},
+		{
+			name:     "utf-8 without meta",
+			document: []byte(strings.Repeat(" ", 2048) + `<h1>你好</h1>`),
+			title:    "你好",
+		},
+		{
+			name:     "utf-8 with wrong meta",
+			document: []byte(`<meta charset="gbk"><h1>你好</h1>`),
+			title:    "你好",
+		},
+		{
+			name:     "utf-16 bom",
+			document: append([]byte{0xff, 0xfe}, encodeCharset(t, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), `<h1>こんにちは</h1>`)...),
+			title:    "こんにちは",
+		},
+	}
+	p := New()
+	for _, tt := range tests {
+		var data charsetData
+		require.NoError(t, p.ParseReader(&data, bytes.NewReader(tt.document)), tt.name)
+		require.Equal(t, tt.title, data.Title, tt.name)
+
+		data, err := ParseReaderAs[charsetData](p, bytes.NewReader(tt.document))
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.title, data.Title, tt.name)
+	}
+}
+
+func TestPagser_ParseURLCharset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
+		_, _ = w.Write(encodeCharset(t, japanese.ShiftJIS, `<h1>こんにちは</h1>`))
+	}))
+	defer server.Close()
+
+	var data charsetData
+	require.NoError(t, New().ParseURL(&data, server.URL))
+	require.Equal(t, "こんにちは", data.Title)
+}
+
+func TestPagser_MaxDocumentBytes(t *testing.T) {
+	document := `<h1>Title</h1>`
+	cfg := DefaultConfig()
+	cfg.MaxDocumentBytes = int64(len(document))
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+	var data charsetData
+	require.NoError(t, p.ParseReader(&data, strings.NewReader(document)))
+	require.Equal(t, "Title", data.Title)
+
+	err = p.ParseReader(&data, strings.NewReader(document+" "))
+	require.True(t, errors.Is(err, ErrDocumentTooLarge), "%v", err)
+	require.EqualError(t, err, "document is too large: the limit is 14 bytes")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat(document, 100)))
+	}))
+	defer server.Close()
+	err = p.ParseURL(&data, server.URL)
+	require.True(t, errors.Is(err, ErrDocumentTooLarge), "%v", err)
+
+	// the decompressed bytes are counted
+	res := &http.Response{
+		Header: http.Header{"Content-Encoding": {"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader(compressBody(t, "gzip", []byte(strings.Repeat(document, 100))))),
+	}
+	err = p.ParseResponse(&data, res)
+	require.True(t, errors.Is(err, ErrDocumentTooLarge), "%v", err)
+}
@@ -0,0 +1,102 @@
+package pagser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SelectorResult the result of the selector of field checked by CheckSelectors
+type SelectorResult struct {
+	Path     string // field path from the root struct, the items of slice are checked together, eg: `NavList[].Name`
+	Selector string // selector of tag
+	Matched  int    // count of the nodes matched by the selector
+}
+
+// CheckSelectors evaluates the selectors of tags against the html and reports the count of matched nodes
+// without calling the functions and filling the struct, eg: validate the tags by a sample page in tests.
+// The nested struct fields are checked against the nodes matched by the field if it has no function,
+// and the items of slice are checked against all matched nodes together.
+func (p *Pagser) CheckSelectors(v interface{}, document string) ([]SelectorResult, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("%v is non-pointer", t)
+	}
+	if t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", t.Elem())
+	}
+	results := make([]SelectorResult, 0)
+	return results, p.checkFields(&results, t.Elem(), "", doc.Selection)
+}
+
+// checkFields checks the selectors of the fields of struct type against the selection
+func (p *Pagser) checkFields(results *[]SelectorResult, t reflect.Type, parent string, selection *goquery.Selection) error {
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		tagValue, tagOk := fieldType.Tag.Lookup(p.Config.TagName)
+		if !tagOk && fieldType.Anonymous {
+			if embeddedType := indirectType(fieldType.Type); embeddedType.Kind() == reflect.Struct {
+				if err := p.checkFields(results, embeddedType, parent, selection); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !tagOk || tagValue == ignoreSymbol {
+			continue
+		}
+		tag, err := p.loadTag(tagValue)
+		if err != nil {
+			return err
+		}
+		if tag.Computed != nil {
+			continue
+		}
+
+		path := joinFieldPath(parent, fieldType.Name)
+		node := tag.find(selection)
+		if tag.Selector != "" {
+			*results = append(*results, SelectorResult{Path: path, Selector: tag.Selector, Matched: node.Size()})
+		}
+
+		// the nodes of nested struct are known only if there is no function, the recursive structs stop at no node
+		elemType := indirectType(fieldType.Type)
+		if len(tag.Funcs) > 0 || node.Size() == 0 || elemType.Kind() != reflect.Struct || !p.isNestedStruct(elemType) {
+			continue
+		}
+		if k := indirectPtrType(fieldType.Type).Kind(); k == reflect.Slice || k == reflect.Array {
+			path += "[]"
+		}
+		if err := p.checkFields(results, elemType, path, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isNestedStruct reports whether the struct type is parsed by its tagged fields
+func (p *Pagser) isNestedStruct(t reflect.Type) bool {
+	return !p.isValueType(t) && !reflect.PtrTo(t).Implements(unmarshalerType) && !t.Implements(unmarshalerType)
+}
+
+// indirectType returns the item type of pointers, slices and arrays, eg: `[]*Item` -> `Item`
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// indirectPtrType returns the type pointed by pointers, eg: `*[]Item` -> `[]Item`
+func indirectPtrType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
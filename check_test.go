@@ -0,0 +1,45 @@
+package pagser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSelectors(t *testing.T) {
+	type Link struct {
+		Url string `pagser:"->attr(href)"`
+	}
+	type NavItem struct {
+		Name  string `pagser:"span"`
+		Icon  string `pagser:"i->attr(class)"`
+		Link  *Link  `pagser:"a"`
+		Total int    `pagser:"=Name + 1"`
+	}
+	type CheckData struct {
+		Title   string    `pagser:"h1 || h2"`
+		NavList []NavItem `pagser:".nav li"`
+		First   NavItem   `pagser:".nav li->eq(0)"`
+		Missing []NavItem `pagser:".missing li"`
+		Ignored string    `pagser:"-"`
+	}
+
+	p := New()
+
+	var data CheckData
+	results, err := p.CheckSelectors(&data, `<h2>Nav</h2><ul class="nav"><li><span>A</span><a href="/a">A</a></li><li><span>B</span></li></ul>`)
+	require.NoError(t, err)
+	require.Equal(t, []SelectorResult{
+		{Path: "Title", Selector: "h1 || h2", Matched: 1},
+		{Path: "NavList", Selector: ".nav li", Matched: 2},
+		{Path: "NavList[].Name", Selector: "span", Matched: 2},
+		{Path: "NavList[].Icon", Selector: "i", Matched: 0},
+		{Path: "NavList[].Link", Selector: "a", Matched: 1},
+		{Path: "First", Selector: ".nav li", Matched: 2},
+		{Path: "Missing", Selector: ".missing li", Matched: 0},
+	}, results)
+	require.Equal(t, "", data.Title)
+
+	_, err = p.CheckSelectors(data, `<h1></h1>`)
+	require.Error(t, err)
+}
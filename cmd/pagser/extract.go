@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"plugin"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/foolin/pagser"
+)
+
+// defaultTimeout the default timeout of the url request
+const defaultTimeout = 30 * time.Second
+
+// command the options of command
+type command struct {
+	schema     string
+	plugin     string
+	symbol     string
+	format     string
+	rows       string
+	tagName    string
+	funcSymbol string
+	userAgent  string
+	timeout    time.Duration
+	input      string
+	stdin      io.Reader
+}
+
+// parseFunc parses the document to the data
+type parseFunc func(doc *goquery.Document) (interface{}, error)
+
+// parser returns the parseFunc of the schema or the struct of plugin
+func (cmd *command) parser() (parseFunc, error) {
+	cfg := pagser.DefaultConfig()
+	cfg.TagName = cmd.tagName
+	cfg.FuncSymbol = cmd.funcSymbol
+	if isURL(cmd.input) {
+		cfg.BaseURL = cmd.input
+	}
+	p, err := pagser.NewWithConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.schema != "" {
+		schema, err := p.LoadSchemaFile(cmd.schema)
+		if err != nil {
+			return nil, err
+		}
+		return func(doc *goquery.Document) (interface{}, error) {
+			return schema.ParseDocument(doc)
+		}, nil
+	}
+	t, err := lookupPluginType(cmd.plugin, cmd.symbol)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := p.Compile(t)
+	if err != nil {
+		return nil, err
+	}
+	return func(doc *goquery.Document) (interface{}, error) {
+		v := reflect.New(t).Interface()
+		return v, schema.ParseDocument(v, doc)
+	}, nil
+}
+
+// extract parses the input by the schema or the struct of plugin
+func (cmd *command) extract() (interface{}, error) {
+	parse, err := cmd.parser()
+	if err != nil {
+		return nil, err
+	}
+	return cmd.extractWith(parse)
+}
+
+// extractWith reads and parses the input, and returns the data decoded from its JSON,
+// so the structs and the maps are written in the same way
+func (cmd *command) extractWith(parse parseFunc) (interface{}, error) {
+	doc, err := cmd.readDocument()
+	if err != nil {
+		return nil, err
+	}
+	value, err := parse(doc)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	return data, json.Unmarshal(raw, &data)
+}
+
+// readDocument reads the document of input file, url or stdin
+func (cmd *command) readDocument() (*goquery.Document, error) {
+	if cmd.input == "-" {
+		return goquery.NewDocumentFromReader(cmd.stdin)
+	}
+	if !isURL(cmd.input) {
+		file, err := os.Open(cmd.input)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return goquery.NewDocumentFromReader(file)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cmd.input, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", cmd.userAgent)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("request %v error: unexpected status %v", cmd.input, res.Status)
+	}
+	return goquery.NewDocumentFromReader(res.Body)
+}
+
+// lookupPluginType returns the struct type of the variable exported by the plugin
+func lookupPluginType(path, symbol string) (reflect.Type, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := plug.Lookup(symbol)
+	if err != nil {
+		return nil, err
+	}
+	// the symbol of variable is the pointer to it
+	t := reflect.TypeOf(sym)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("symbol %v of plugin %v is %v, not a struct variable", symbol, path, t)
+	}
+	return t.Elem(), nil
+}
+
+// isURL reports whether the input is http or https url
+func isURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
@@ -0,0 +1,87 @@
+// Command pagser extracts the data of html file or url by the schema and prints it as JSON, CSV or NDJSON,
+// the schema is the JSON or YAML schema definition of pagser.LoadSchema, or the struct exported by the Go plugin, eg:
+//
+//	go install github.com/foolin/pagser/cmd/pagser@latest
+//	pagser -schema rules.yaml https://example.com
+//	pagser -schema rules.yaml -format csv -rows items page.html
+//	curl -s https://example.com | pagser -schema rules.yaml -format ndjson -rows items -
+//	pagser -plugin page.so -symbol PageData page.html
+//
+// The plugin exports a variable of the pagser tagged struct, eg: `var PageData pagser.PageData`, built by
+// `go build -buildmode=plugin`. The records of CSV and NDJSON are the items of the list of `-rows` if set,
+// or the extracted data itself.
+//
+// The watch command re-fetches and re-parses the url by the interval, and prints the changes of extracted values
+// as NDJSON, eg: `{"time":"...","path":"items[0].price","op":"changed","old":1.5,"new":2}`:
+//
+//	pagser watch -url https://example.com -schema rules.yaml -interval 5m
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintf(os.Stderr, "pagser: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run runs the command with the arguments, the input `-` is read from stdin, the watch command runs until ctx is done
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) > 0 && args[0] == "watch" {
+		return runWatch(ctx, args[1:], stdout, stderr)
+	}
+	cmd := &command{stdin: stdin}
+	flags := cmd.flagSet("pagser", stderr)
+	flags.StringVar(&cmd.format, "format", formatJSON, "output format: json, csv or ndjson")
+	flags.StringVar(&cmd.rows, "rows", "", "key of the list whose items are the records of csv and ndjson")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: pagser (-schema file | -plugin file -symbol name) [flags] <file | url | ->\n"+
+			"       pagser watch -url url (-schema file | -plugin file -symbol name) [flags]\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || !cmd.validSchema() {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	cmd.input = flags.Arg(0)
+
+	data, err := cmd.extract()
+	if err != nil {
+		return err
+	}
+	return writeOutput(stdout, cmd.format, data, cmd.rows)
+}
+
+// flagSet returns the flag set with the flags of schema and request
+func (cmd *command) flagSet(name string, output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.StringVar(&cmd.schema, "schema", "", "JSON or YAML schema definition file")
+	flags.StringVar(&cmd.plugin, "plugin", "", "Go plugin file which exports the struct variable of -symbol")
+	flags.StringVar(&cmd.symbol, "symbol", "", "name of the struct variable exported by -plugin")
+	flags.StringVar(&cmd.tagName, "tagname", "pagser", "struct tag name of pagser.Config.TagName")
+	flags.StringVar(&cmd.funcSymbol, "funcsymbol", "->", "function symbol of pagser.Config.FuncSymbol")
+	flags.StringVar(&cmd.userAgent, "useragent", "pagser", "User-Agent header of the url request")
+	flags.DurationVar(&cmd.timeout, "timeout", defaultTimeout, "timeout of the url request")
+	return flags
+}
+
+// validSchema reports whether either the schema or the plugin with symbol is set
+func (cmd *command) validSchema() bool {
+	return (cmd.schema == "") != (cmd.plugin == "") && (cmd.plugin != "") == (cmd.symbol != "")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	page := filepath.Join("testdata", "page.html")
+	schema := filepath.Join("testdata", "schema.yaml")
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-schema", schema, "-format", "ndjson", "-rows", "items", page},
+			`{"link":"/apple","name":"Apple","price":1.5,"tags":["fruit","red"]}` + "\n" +
+				`{"link":"/pear","name":"Pear, Green","price":2,"tags":[]}` + "\n"},
+		{[]string{"-schema", schema, "-format", "csv", "-rows", "items", page},
+			"link,name,price,tags\n/apple,Apple,1.5,\"[\"\"fruit\"\",\"\"red\"\"]\"\n/pear,\"Pear, Green\",2,[]\n"},
+		{[]string{"-schema", schema, "-format", "csv", page},
+			"items,title\n" + `"[{""link"":""/apple"",""name"":""Apple"",""price"":1.5,""tags"":[""fruit"",""red""]},{""link"":""/pear"",""name"":""Pear, Green"",""price"":2,""tags"":[]}]",Pagser Shop` + "\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := run(context.Background(), tt.args, nil, &out, io.Discard); err != nil {
+			t.Fatalf("%v error: %v", tt.args, err)
+		}
+		if out.String() != tt.want {
+			t.Fatalf("%v want:\n%v\nbut got:\n%v", tt.args, tt.want, out.String())
+		}
+	}
+}
+
+func TestRunInput(t *testing.T) {
+	html, err := os.ReadFile(filepath.Join("testdata", "page.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := filepath.Join("testdata", "schema.yaml")
+
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"-schema", schema, "-"}, bytes.NewReader(html), &out, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"title": "Pagser Shop"`) {
+		t.Fatalf("stdin output: %v", out.String())
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(html)
+	}))
+	defer server.Close()
+	out.Reset()
+	if err := run(context.Background(), []string{"-schema", schema, "-format", "ndjson", server.URL}, nil, &out, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"title":"Pagser Shop"`) {
+		t.Fatalf("url output: %v", out.String())
+	}
+	if err := run(context.Background(), []string{"-schema", schema, server.URL + "/missing"}, nil, &out, io.Discard); err == nil {
+		t.Fatal("not found url must return error")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	page := filepath.Join("testdata", "page.html")
+	schema := filepath.Join("testdata", "schema.yaml")
+	tests := [][]string{
+		{page},
+		{"-schema", schema},
+		{"-schema", schema, "-plugin", "page.so", "-symbol", "Page", page},
+		{"-plugin", "page.so", page},
+		{"-schema", schema, "-format", "xml", page},
+		{"-schema", schema, "-format", "csv", "-rows", "title", page},
+		{"-schema", filepath.Join("testdata", "missing.yaml"), page},
+		{"-schema", schema, filepath.Join("testdata", "missing.html")},
+		{"-plugin", filepath.Join("testdata", "missing.so"), "-symbol", "Page", page},
+	}
+	for _, args := range tests {
+		var out bytes.Buffer
+		if err := run(context.Background(), args, nil, &out, io.Discard); err == nil {
+			t.Fatalf("%v must return error", args)
+		}
+	}
+}
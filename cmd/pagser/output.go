@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// output formats
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// writeOutput writes the data in the format, the records of csv and ndjson are the items of list of rows key if set
+func writeOutput(w io.Writer, format string, data interface{}, rows string) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case formatCSV, formatNDJSON:
+	default:
+		return fmt.Errorf("format %v is not supported", format)
+	}
+
+	records := []interface{}{data}
+	if rows != "" {
+		object, _ := data.(map[string]interface{})
+		list, ok := object[rows].([]interface{})
+		if !ok && object[rows] != nil {
+			return fmt.Errorf("rows %v is not a list", rows)
+		}
+		records = list
+	}
+	if format == formatNDJSON {
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return writeCSV(w, records)
+}
+
+// writeCSV writes the records with the header of their sorted keys, the values which are not string are written as JSON
+func writeCSV(w io.Writer, records []interface{}) error {
+	keySet := make(map[string]bool)
+	for _, record := range records {
+		object, ok := record.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("csv record %v is not an object", record)
+		}
+		for key := range object {
+			keySet[key] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(keys); err != nil {
+		return err
+	}
+	for _, record := range records {
+		object := record.(map[string]interface{})
+		row := make([]string, len(keys))
+		for i, key := range keys {
+			switch v := object[key].(type) {
+			case nil:
+			case string:
+				row[i] = v
+			default:
+				raw, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				row[i] = string(raw)
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
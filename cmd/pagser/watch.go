@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// defaultInterval the default interval of watch command
+const defaultInterval = 5 * time.Minute
+
+// change ops
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+// change the change of extracted value
+type change struct {
+	Time time.Time   `json:"time"`
+	Path string      `json:"path"` // eg: `items[0].price`, empty for the root
+	Op   string      `json:"op"`   // added, removed or changed
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// runWatch runs the watch command until ctx is done, the errors of fetching and parsing are printed to stderr
+// and the next interval is tried again
+func runWatch(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cmd := &command{}
+	flags := cmd.flagSet("pagser watch", stderr)
+	flags.StringVar(&cmd.input, "url", "", "url of the page to watch")
+	interval := flags.Duration("interval", defaultInterval, "interval of re-fetching the url")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: pagser watch -url url (-schema file | -plugin file -symbol name) [flags]\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 || cmd.input == "" || !cmd.validSchema() || *interval <= 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	parse, err := cmd.parser()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	var last interface{}
+	fetched := false
+	for {
+		data, err := cmd.extractWith(parse)
+		if err != nil {
+			fmt.Fprintf(stderr, "pagser: %v\n", err)
+		} else {
+			if fetched {
+				now := time.Now()
+				changes := make([]change, 0)
+				diffValues(&changes, "", last, data)
+				for _, c := range changes {
+					c.Time = now
+					if err := encoder.Encode(c); err != nil {
+						return err
+					}
+				}
+			}
+			last, fetched = data, true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// diffValues appends the changes from the old value to the new value decoded from JSON,
+// the objects are compared by keys and the lists are compared by indexes
+func diffValues(changes *[]change, path string, oldValue, newValue interface{}) {
+	oldObject, oldOk := oldValue.(map[string]interface{})
+	newObject, newOk := newValue.(map[string]interface{})
+	if oldOk && newOk {
+		keys := make([]string, 0, len(oldObject)+len(newObject))
+		for key := range oldObject {
+			keys = append(keys, key)
+		}
+		for key := range newObject {
+			if _, ok := oldObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			diffItem(changes, keyPath, oldObject, newObject, key)
+		}
+		return
+	}
+
+	oldList, oldOk := oldValue.([]interface{})
+	newList, newOk := newValue.([]interface{})
+	if oldOk && newOk {
+		for i := 0; i < len(oldList) || i < len(newList); i++ {
+			itemPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(newList):
+				*changes = append(*changes, change{Path: itemPath, Op: changeRemoved, Old: oldList[i]})
+			case i >= len(oldList):
+				*changes = append(*changes, change{Path: itemPath, Op: changeAdded, New: newList[i]})
+			default:
+				diffValues(changes, itemPath, oldList[i], newList[i])
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, change{Path: path, Op: changeChanged, Old: oldValue, New: newValue})
+	}
+}
+
+// diffItem appends the changes of the key of objects
+func diffItem(changes *[]change, path string, oldObject, newObject map[string]interface{}, key string) {
+	oldValue, oldOk := oldObject[key]
+	newValue, newOk := newObject[key]
+	switch {
+	case !newOk:
+		*changes = append(*changes, change{Path: path, Op: changeRemoved, Old: oldValue})
+	case !oldOk:
+		*changes = append(*changes, change{Path: path, Op: changeAdded, New: newValue})
+	default:
+		diffValues(changes, path, oldValue, newValue)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiffValues(t *testing.T) {
+	var oldValue, newValue interface{}
+	json.Unmarshal([]byte(`{"title":"A","items":[{"price":1},{"price":2}],"gone":true}`), &oldValue)
+	json.Unmarshal([]byte(`{"title":"B","items":[{"price":1.5}],"tag":"new"}`), &newValue)
+
+	changes := make([]change, 0)
+	diffValues(&changes, "", oldValue, newValue)
+	want := []change{
+		{Path: "gone", Op: changeRemoved, Old: true},
+		{Path: "items[0].price", Op: changeChanged, Old: 1.0, New: 1.5},
+		{Path: "items[1]", Op: changeRemoved, Old: map[string]interface{}{"price": 2.0}},
+		{Path: "tag", Op: changeAdded, New: "new"},
+		{Path: "title", Op: changeChanged, Old: "A", New: "B"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("want %v, but got %v", want, changes)
+	}
+
+	changes = changes[:0]
+	diffValues(&changes, "", oldValue, oldValue)
+	if len(changes) != 0 {
+		t.Fatalf("want no change, but got %v", changes)
+	}
+}
+
+// lineWriter sends the written lines to the channel
+type lineWriter chan string
+
+func (w lineWriter) Write(data []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		w <- line
+	}
+	return len(data), nil
+}
+
+func TestRunWatch(t *testing.T) {
+	var version int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<title>Version %d</title>`, atomic.AddInt32(&version, 1)/3)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(lineWriter, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, []string{"watch", "-url", server.URL, "-schema", filepath.Join("testdata", "schema.yaml"),
+			"-interval", "10ms"}, nil, lines, io.Discard)
+	}()
+
+	select {
+	case line := <-lines:
+		var c change
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Path != "title" || c.Op != changeChanged || c.Old != "Version 0" || c.New != "Version 1" || c.Time.IsZero() {
+			t.Fatalf("unexpected change: %v", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change is printed")
+	}
+	cancel()
+	go func() {
+		for range lines {
+		}
+	}()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	close(lines)
+
+	for _, args := range [][]string{
+		{"watch", "-schema", "schema.yaml"},
+		{"watch", "-url", server.URL},
+		{"watch", "-url", server.URL, "-schema", "schema.yaml", "-interval", "0s"},
+		{"watch", "-url", server.URL, "-schema", filepath.Join("testdata", "missing.yaml")},
+	} {
+		if err := run(context.Background(), args, nil, io.Discard, io.Discard); err == nil {
+			t.Fatalf("%v must return error", args)
+		}
+	}
+}
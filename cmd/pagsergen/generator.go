@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/foolin/pagser"
+)
+
+// castFuncs the cast functions of the supported basic field types
+var castFuncs = map[string]string{
+	"string":  "ToString",
+	"bool":    "ToBool",
+	"int":     "ToInt",
+	"int8":    "ToInt8",
+	"int16":   "ToInt16",
+	"int32":   "ToInt32",
+	"int64":   "ToInt64",
+	"uint":    "ToUint",
+	"uint8":   "ToUint8",
+	"uint16":  "ToUint16",
+	"uint32":  "ToUint32",
+	"uint64":  "ToUint64",
+	"float32": "ToFloat32",
+	"float64": "ToFloat64",
+}
+
+// castSliceFuncs the cast functions of the supported slice field types
+var castSliceFuncs = map[string]string{
+	"string": "ToStringSlice",
+	"bool":   "ToBoolSlice",
+	"int":    "ToIntSlice",
+}
+
+// builtinMethods the builtin functions whose method name is not the title of function name
+var builtinMethods = map[string]string{
+	"outerHtml": "OutHtml",
+}
+
+type fieldKind int
+
+const (
+	kindBasic fieldKind = iota
+	kindBasicSlice
+	kindStruct
+	kindStructPtr
+	kindStructSlice
+	kindStructPtrSlice
+)
+
+// fieldInfo the type info of field
+type fieldInfo struct {
+	kind     fieldKind
+	typeName string // the basic type or struct type name
+}
+
+// generator generates the parse functions of the structs in package
+type generator struct {
+	p        *pagser.Pagser
+	pkgName  string
+	structs  map[string]*ast.StructType // type name => struct
+	methods  map[string]map[string]bool // type name => method names
+	queue    []string                   // struct types to generate
+	done     map[string]bool
+	exported []string // types with exported ParseXxx function
+}
+
+// newGenerator parses the go files in dir, the test files and the output file are ignored
+func newGenerator(p *pagser.Pagser, dir string, output string) (*generator, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != filepath.Base(output)
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("found %v packages in %v, want 1", len(pkgs), dir)
+	}
+	g := &generator{
+		p:       p,
+		structs: make(map[string]*ast.StructType),
+		methods: make(map[string]map[string]bool),
+		done:    make(map[string]bool),
+	}
+	for name, pkg := range pkgs {
+		g.pkgName = name
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				g.collect(decl)
+			}
+		}
+	}
+	return g, nil
+}
+
+// collect collects the struct types and methods of declaration
+func (g *generator) collect(decl ast.Decl) {
+	switch d := decl.(type) {
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+				if st, ok := typeSpec.Type.(*ast.StructType); ok {
+					g.structs[typeSpec.Name.Name] = st
+				}
+			}
+		}
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return
+		}
+		recv := d.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if ident, ok := recv.(*ast.Ident); ok {
+			if g.methods[ident.Name] == nil {
+				g.methods[ident.Name] = make(map[string]bool)
+			}
+			g.methods[ident.Name][d.Name.Name] = true
+		}
+	}
+}
+
+// generate generates the parse functions of types and the nested structs, returns the formatted source
+func (g *generator) generate(types []string) ([]byte, error) {
+	for _, name := range types {
+		if _, ok := g.structs[name]; !ok {
+			return nil, fmt.Errorf("struct type %v is not found", name)
+		}
+		g.exported = append(g.exported, name)
+		g.enqueue(name)
+	}
+	body := &bytes.Buffer{}
+	for _, name := range g.exported {
+		fmt.Fprintf(body, "\n// Parse%v parses the document to %v without reflection\n", name, name)
+		fmt.Fprintf(body, "func Parse%v(doc *goquery.Document) (%v, error) {\n\treturn parse%vSelection(doc.Selection)\n}\n", name, name, name)
+	}
+	for len(g.queue) > 0 {
+		name := g.queue[0]
+		g.queue = g.queue[1:]
+		if err := g.genStruct(body, name); err != nil {
+			return nil, err
+		}
+	}
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by pagsergen; DO NOT EDIT.\n\npackage %v\n\nimport (\n", g.pkgName)
+	code := body.String()
+	for i, group := range [][]string{{"fmt", "strings"}, {"github.com/PuerkitoBio/goquery", "github.com/foolin/pagser", "github.com/spf13/cast"}} {
+		if i > 0 {
+			src.WriteString("\n")
+		}
+		for _, pkg := range group {
+			if strings.Contains(code, path.Base(pkg)+".") {
+				fmt.Fprintf(src, "\t%v\n", strconv.Quote(pkg))
+			}
+		}
+	}
+	src.WriteString(")\n")
+	src.WriteString(code)
+	return format.Source(src.Bytes())
+}
+
+func (g *generator) enqueue(name string) {
+	if !g.done[name] {
+		g.done[name] = true
+		g.queue = append(g.queue, name)
+	}
+}
+
+// genStruct generates the parse function of struct type against the selection
+func (g *generator) genStruct(w *bytes.Buffer, name string) error {
+	fmt.Fprintf(w, "\nfunc parse%vSelection(sel *goquery.Selection) (%v, error) {\n", name, name)
+	w.WriteString("\tvar (\n\t\tv      " + name + "\n\t\tnode   *goquery.Selection\n\t\tout    interface{}\n\t\thasOut bool\n\t\terr    error\n\t)\n")
+	w.WriteString("\t_, _, _, _ = node, out, hasOut, err\n")
+	for _, field := range g.structs[name].Fields.List {
+		if err := g.genField(w, name, field); err != nil {
+			return err
+		}
+	}
+	w.WriteString("\treturn v, nil\n}\n")
+	return nil
+}
+
+// genField generates the code to parse the field
+func (g *generator) genField(w *bytes.Buffer, structName string, field *ast.Field) error {
+	var tagValue string
+	var hasTag bool
+	if field.Tag != nil {
+		rawTag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return err
+		}
+		structTag := reflect.StructTag(rawTag)
+		tagValue, hasTag = structTag.Lookup(g.p.Config.TagName)
+		if _, ok := structTag.Lookup("depends"); ok && hasTag {
+			return fmt.Errorf("%v: the depends tag is not supported", structName)
+		}
+	}
+	if tagValue == "-" {
+		return nil
+	}
+	if len(field.Names) == 0 {
+		// embedded struct is parsed against the current selection
+		info, err := g.fieldInfo(field.Type)
+		if err != nil || (info.kind != kindStruct && info.kind != kindStructPtr) {
+			return fmt.Errorf("%v: the embedded field %v is not supported", structName, exprString(field.Type))
+		}
+		return g.genValue(w, structName, info.typeName, tagValue, field.Type)
+	}
+	if !hasTag {
+		return nil
+	}
+	for _, name := range field.Names {
+		if err := g.genValue(w, structName, name.Name, tagValue, field.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genValue generates the code to parse the field of name by the tag
+func (g *generator) genValue(w *bytes.Buffer, structName string, name string, tagValue string, fieldType ast.Expr) error {
+	info, err := g.fieldInfo(fieldType)
+	if err != nil {
+		return fmt.Errorf("%v.%v: %v", structName, name, err)
+	}
+	tag, err := g.p.ParseTag(tagValue)
+	if err != nil {
+		return fmt.Errorf("%v.%v: %v", structName, name, err)
+	}
+	if tag.Computed != "" {
+		return fmt.Errorf("%v.%v: the computed field is not supported", structName, name)
+	}
+	isStruct := info.kind != kindBasic && info.kind != kindBasicSlice
+	if tag.HasDefault && isStruct {
+		return fmt.Errorf("%v.%v: the default value of struct is not supported", structName, name)
+	}
+
+	if tagValue == "" {
+		fmt.Fprintf(w, "\n\t// %v\n", name)
+	} else {
+		fmt.Fprintf(w, "\n\t// %v `%v:%v`\n", name, g.p.Config.TagName, strconv.Quote(tagValue))
+	}
+	if len(tag.Selectors) == 0 {
+		w.WriteString("\tnode = sel\n")
+	} else {
+		fmt.Fprintf(w, "\tnode = pagser.FindSelection(sel, %v)\n", quoteList(tag.Selectors))
+	}
+
+	body := &bytes.Buffer{}
+	if err := g.genPipeline(body, structName, name, tag, info); err != nil {
+		return err
+	}
+	if err := g.genAssign(body, name, tag, info); err != nil {
+		return err
+	}
+
+	switch {
+	case tag.HasDefault:
+		fmt.Fprintf(w, "\tif node.Size() == 0 {\n\t\tv.%v = cast.%v(%v)\n\t} else {\n%v\t}\n",
+			name, castFunc(info), strconv.Quote(tag.Default), indent(body.String()))
+	case tag.Required || tag.Strict:
+		fmt.Fprintf(w, "\tif node.Size() == 0 {\n\t\treturn v, fmt.Errorf(\"field %v is required: selector matched no nodes\")\n\t}\n%v",
+			name, body.String())
+	case tag.OmitEmpty:
+		fmt.Fprintf(w, "\tif node.Size() > 0 {\n%v\t}\n", indent(body.String()))
+	default:
+		w.WriteString(body.String())
+	}
+	return nil
+}
+
+// genPipeline generates the call of function pipeline
+func (g *generator) genPipeline(w *bytes.Buffer, structName string, name string, tag *pagser.Tag, info fieldInfo) error {
+	if len(tag.Funcs) == 0 {
+		return nil
+	}
+	funcs := make([]string, 0)
+	for _, fn := range tag.Funcs {
+		expr, err := g.funcExpr(structName, fn.Name)
+		if err != nil {
+			return fmt.Errorf("%v.%v: %v", structName, name, err)
+		}
+		args := append([]string{expr}, quoteStrings(fn.Params)...)
+		funcs = append(funcs, fmt.Sprintf("pagser.BindFunc(%v)", strings.Join(args, ", ")))
+	}
+	fmt.Fprintf(w, "\tnode, out, hasOut, err = pagser.CallPipeline(node, %v, %v)\n", info.kind == kindBasicSlice, strings.Join(funcs, ", "))
+	fmt.Fprintf(w, "\tif err != nil {\n\t\treturn v, fmt.Errorf(\"field %v: %%w\", err)\n\t}\n", name)
+	return nil
+}
+
+// genAssign generates the code to set the value of field
+func (g *generator) genAssign(w *bytes.Buffer, name string, tag *pagser.Tag, info fieldInfo) error {
+	hasFuncs := len(tag.Funcs) > 0
+	switch info.kind {
+	case kindBasic, kindBasicSlice:
+		text := "pagser.NodeText(node)"
+		if info.kind == kindBasicSlice {
+			text = "pagser.NodeTexts(node)"
+		}
+		if info.typeName != "string" {
+			text = fmt.Sprintf("cast.%v(%v)", castFunc(info), text)
+		}
+		if hasFuncs {
+			fmt.Fprintf(w, "\tif hasOut {\n\t\tv.%v = cast.%v(out)\n\t} else {\n\t\tv.%v = %v\n\t}\n", name, castFunc(info), name, text)
+		} else {
+			fmt.Fprintf(w, "\tv.%v = %v\n", name, text)
+		}
+		if tag.Required && info.kind == kindBasic && info.typeName == "string" {
+			fmt.Fprintf(w, "\tif strings.TrimSpace(v.%v) == \"\" {\n\t\treturn v, fmt.Errorf(\"field %v is required: value is empty\")\n\t}\n", name, name)
+		}
+		return nil
+	}
+
+	if hasFuncs {
+		fmt.Fprintf(w, "\tif hasOut {\n\t\treturn v, fmt.Errorf(\"field %v: the function output %%T is not a selection\", out)\n\t}\n", name)
+	}
+	g.enqueue(info.typeName)
+	parse := "parse" + info.typeName + "Selection"
+	switch info.kind {
+	case kindStruct:
+		fmt.Fprintf(w, "\tif v.%v, err = %v(node); err != nil {\n\t\treturn v, fmt.Errorf(\"field %v: %%w\", err)\n\t}\n", name, parse, name)
+	case kindStructPtr:
+		fmt.Fprintf(w, "\t{\n\t\titem, err := %v(node)\n\t\tif err != nil {\n\t\t\treturn v, fmt.Errorf(\"field %v: %%w\", err)\n\t\t}\n\t\tv.%v = &item\n\t}\n",
+			parse, name, name)
+	case kindStructSlice, kindStructPtrSlice:
+		itemType, item := info.typeName, "item"
+		if info.kind == kindStructPtrSlice {
+			itemType, item = "*"+info.typeName, "&item"
+		}
+		fmt.Fprintf(w, "\t{\n\t\titems := make([]%v, 0, node.Size())\n\t\tfor i := range node.Nodes {\n"+
+			"\t\t\titem, err := %v(node.Eq(i))\n\t\t\tif err != nil {\n\t\t\t\treturn v, fmt.Errorf(\"field %v[%%v]: %%w\", i, err)\n\t\t\t}\n"+
+			"\t\t\titems = append(items, %v)\n\t\t}\n\t\tv.%v = items\n\t}\n", itemType, parse, name, item, name)
+	}
+	return nil
+}
+
+// funcExpr returns the go expression of the function, the methods of struct take precedence over the builtin functions
+func (g *generator) funcExpr(structName string, name string) (string, error) {
+	if g.methods[structName][name] {
+		return "v." + name, nil
+	}
+	methodName, ok := builtinMethods[name]
+	if !ok {
+		methodName = strings.ToUpper(name[:1]) + name[1:]
+	}
+	if _, ok := reflect.TypeOf(pagser.BuiltinFunctions{}).MethodByName(methodName); ok {
+		return "pagser.BuiltinFunctions{}." + methodName, nil
+	}
+	if _, ok := reflect.TypeOf(pagser.BuiltinSelections{}).MethodByName(methodName); ok {
+		return "pagser.BuiltinSelections{}." + methodName, nil
+	}
+	return "", fmt.Errorf("function %v is neither builtin nor the method of %v", name, structName)
+}
+
+// fieldInfo returns the type info of the supported field type
+func (g *generator) fieldInfo(expr ast.Expr) (fieldInfo, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if _, ok := castFuncs[t.Name]; ok {
+			return fieldInfo{kind: kindBasic, typeName: t.Name}, nil
+		}
+		if _, ok := g.structs[t.Name]; ok {
+			return fieldInfo{kind: kindStruct, typeName: t.Name}, nil
+		}
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok && g.structs[ident.Name] != nil {
+			return fieldInfo{kind: kindStructPtr, typeName: ident.Name}, nil
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		elem, err := g.fieldInfo(t.Elt)
+		if err != nil {
+			break
+		}
+		switch elem.kind {
+		case kindBasic:
+			if _, ok := castSliceFuncs[elem.typeName]; ok {
+				return fieldInfo{kind: kindBasicSlice, typeName: elem.typeName}, nil
+			}
+		case kindStruct:
+			return fieldInfo{kind: kindStructSlice, typeName: elem.typeName}, nil
+		case kindStructPtr:
+			return fieldInfo{kind: kindStructPtrSlice, typeName: elem.typeName}, nil
+		}
+	}
+	return fieldInfo{}, fmt.Errorf("type %v is not supported", exprString(expr))
+}
+
+// castFunc returns the cast function name of field type
+func castFunc(info fieldInfo) string {
+	if info.kind == kindBasicSlice {
+		return castSliceFuncs[info.typeName]
+	}
+	return castFuncs[info.typeName]
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+func quoteStrings(list []string) []string {
+	quoted := make([]string, 0, len(list))
+	for _, s := range list {
+		quoted = append(quoted, strconv.Quote(s))
+	}
+	return quoted
+}
+
+func quoteList(list []string) string {
+	return strings.Join(quoteStrings(list), ", ")
+}
+
+func indent(code string) string {
+	lines := strings.SplitAfter(code, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "\t" + line
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/foolin/pagser"
+)
+
+func TestGenerateExample(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	output := filepath.Join(dir, "page_pagser.go")
+	g, err := newGenerator(pagser.New(), dir, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := g.generate([]string{"Page"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(want) {
+		t.Fatalf("%v is out of date, run go generate ./...", output)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"type T struct {\n\tA map[string]string `pagser:\"a\"`\n}", "T.A: type map[string]string is not supported"},
+		{"type T struct {\n\tA int `pagser:\"=B * 2\"`\n\tB int `pagser:\"b\"`\n}", "T.A: the computed field is not supported"},
+		{"type T struct {\n\tA string `pagser:\"a->unknownFunc()\"`\n}", "function unknownFunc is neither builtin nor the method of T"},
+		{"type T struct {\n\tA string `pagser:\"a->text(\"`\n}", "function `text(` syntax error"},
+		{"type T struct {\n\tA string `pagser:\"a\" depends:\"B\"`\n\tB string `pagser:\"b\"`\n}", "the depends tag is not supported"},
+		{"type T struct {\n\tA S `pagser:\"a,default=x\"`\n}\ntype S struct{}", "the default value of struct is not supported"},
+		{"type S struct{}", "struct type T is not found"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package t\n\n"+tt.src+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		g, err := newGenerator(pagser.New(), dir, "t_pagser.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = g.generate([]string{"T"})
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("want error %q, but got %v", tt.err, err)
+		}
+	}
+}
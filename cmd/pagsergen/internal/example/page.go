@@ -0,0 +1,46 @@
+// Package example is the example of the generated code of pagsergen, it is tested to be the same as pagser.Parse.
+package example
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+//go:generate go run github.com/foolin/pagser/cmd/pagsergen -type=Page
+
+type Meta struct {
+	Keywords    []string `pagser:"meta[name='keywords']->attrSplit(content)"`
+	Description string   `pagser:"meta[name='description']->attr(content)"`
+}
+
+type Link struct {
+	Name string `pagser:"->text()"`
+	Url  string `pagser:"->attr(href)"`
+}
+
+type Item struct {
+	Name   string   `pagser:"h2->text(),required"`
+	Price  float64  `pagser:".price->number()"`
+	Stock  int      `pagser:".stock,default=0"`
+	Tags   []string `pagser:".tag"`
+	OnSale bool     `pagser:".sale->exists()"`
+	Link   *Link    `pagser:"a,omitempty"`
+}
+
+type Page struct {
+	Meta
+	Title    string  `pagser:"h1 || title"`
+	Lang     string  `pagser:"html->attr(lang)->Upper()"`
+	Nav      []Link  `pagser:".nav a"`
+	First    Link    `pagser:".nav a->first()"`
+	Items    []*Item `pagser:".item"`
+	Count    int     `pagser:".item->size()"`
+	Ignored  string  `pagser:"-"`
+	Untagged string
+}
+
+// Upper the struct function of field
+func (p Page) Upper(node *goquery.Selection, args ...string) (interface{}, error) {
+	return strings.ToUpper(node.Text()), nil
+}
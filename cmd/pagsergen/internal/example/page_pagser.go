@@ -0,0 +1,254 @@
+// Code generated by pagsergen; DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/foolin/pagser"
+	"github.com/spf13/cast"
+)
+
+// ParsePage parses the document to Page without reflection
+func ParsePage(doc *goquery.Document) (Page, error) {
+	return parsePageSelection(doc.Selection)
+}
+
+func parsePageSelection(sel *goquery.Selection) (Page, error) {
+	var (
+		v      Page
+		node   *goquery.Selection
+		out    interface{}
+		hasOut bool
+		err    error
+	)
+	_, _, _, _ = node, out, hasOut, err
+
+	// Meta
+	node = sel
+	if v.Meta, err = parseMetaSelection(node); err != nil {
+		return v, fmt.Errorf("field Meta: %w", err)
+	}
+
+	// Title `pagser:"h1 || title"`
+	node = pagser.FindSelection(sel, "h1", "title")
+	v.Title = pagser.NodeText(node)
+
+	// Lang `pagser:"html->attr(lang)->Upper()"`
+	node = pagser.FindSelection(sel, "html")
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Attr, "lang"), pagser.BindFunc(v.Upper))
+	if err != nil {
+		return v, fmt.Errorf("field Lang: %w", err)
+	}
+	if hasOut {
+		v.Lang = cast.ToString(out)
+	} else {
+		v.Lang = pagser.NodeText(node)
+	}
+
+	// Nav `pagser:".nav a"`
+	node = pagser.FindSelection(sel, ".nav a")
+	{
+		items := make([]Link, 0, node.Size())
+		for i := range node.Nodes {
+			item, err := parseLinkSelection(node.Eq(i))
+			if err != nil {
+				return v, fmt.Errorf("field Nav[%v]: %w", i, err)
+			}
+			items = append(items, item)
+		}
+		v.Nav = items
+	}
+
+	// First `pagser:".nav a->first()"`
+	node = pagser.FindSelection(sel, ".nav a")
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinSelections{}.First))
+	if err != nil {
+		return v, fmt.Errorf("field First: %w", err)
+	}
+	if hasOut {
+		return v, fmt.Errorf("field First: the function output %T is not a selection", out)
+	}
+	if v.First, err = parseLinkSelection(node); err != nil {
+		return v, fmt.Errorf("field First: %w", err)
+	}
+
+	// Items `pagser:".item"`
+	node = pagser.FindSelection(sel, ".item")
+	{
+		items := make([]*Item, 0, node.Size())
+		for i := range node.Nodes {
+			item, err := parseItemSelection(node.Eq(i))
+			if err != nil {
+				return v, fmt.Errorf("field Items[%v]: %w", i, err)
+			}
+			items = append(items, &item)
+		}
+		v.Items = items
+	}
+
+	// Count `pagser:".item->size()"`
+	node = pagser.FindSelection(sel, ".item")
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Size))
+	if err != nil {
+		return v, fmt.Errorf("field Count: %w", err)
+	}
+	if hasOut {
+		v.Count = cast.ToInt(out)
+	} else {
+		v.Count = cast.ToInt(pagser.NodeText(node))
+	}
+	return v, nil
+}
+
+func parseMetaSelection(sel *goquery.Selection) (Meta, error) {
+	var (
+		v      Meta
+		node   *goquery.Selection
+		out    interface{}
+		hasOut bool
+		err    error
+	)
+	_, _, _, _ = node, out, hasOut, err
+
+	// Keywords `pagser:"meta[name='keywords']->attrSplit(content)"`
+	node = pagser.FindSelection(sel, "meta[name='keywords']")
+	node, out, hasOut, err = pagser.CallPipeline(node, true, pagser.BindFunc(pagser.BuiltinFunctions{}.AttrSplit, "content"))
+	if err != nil {
+		return v, fmt.Errorf("field Keywords: %w", err)
+	}
+	if hasOut {
+		v.Keywords = cast.ToStringSlice(out)
+	} else {
+		v.Keywords = pagser.NodeTexts(node)
+	}
+
+	// Description `pagser:"meta[name='description']->attr(content)"`
+	node = pagser.FindSelection(sel, "meta[name='description']")
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Attr, "content"))
+	if err != nil {
+		return v, fmt.Errorf("field Description: %w", err)
+	}
+	if hasOut {
+		v.Description = cast.ToString(out)
+	} else {
+		v.Description = pagser.NodeText(node)
+	}
+	return v, nil
+}
+
+func parseLinkSelection(sel *goquery.Selection) (Link, error) {
+	var (
+		v      Link
+		node   *goquery.Selection
+		out    interface{}
+		hasOut bool
+		err    error
+	)
+	_, _, _, _ = node, out, hasOut, err
+
+	// Name `pagser:"->text()"`
+	node = sel
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Text))
+	if err != nil {
+		return v, fmt.Errorf("field Name: %w", err)
+	}
+	if hasOut {
+		v.Name = cast.ToString(out)
+	} else {
+		v.Name = pagser.NodeText(node)
+	}
+
+	// Url `pagser:"->attr(href)"`
+	node = sel
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Attr, "href"))
+	if err != nil {
+		return v, fmt.Errorf("field Url: %w", err)
+	}
+	if hasOut {
+		v.Url = cast.ToString(out)
+	} else {
+		v.Url = pagser.NodeText(node)
+	}
+	return v, nil
+}
+
+func parseItemSelection(sel *goquery.Selection) (Item, error) {
+	var (
+		v      Item
+		node   *goquery.Selection
+		out    interface{}
+		hasOut bool
+		err    error
+	)
+	_, _, _, _ = node, out, hasOut, err
+
+	// Name `pagser:"h2->text(),required"`
+	node = pagser.FindSelection(sel, "h2")
+	if node.Size() == 0 {
+		return v, fmt.Errorf("field Name is required: selector matched no nodes")
+	}
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Text))
+	if err != nil {
+		return v, fmt.Errorf("field Name: %w", err)
+	}
+	if hasOut {
+		v.Name = cast.ToString(out)
+	} else {
+		v.Name = pagser.NodeText(node)
+	}
+	if strings.TrimSpace(v.Name) == "" {
+		return v, fmt.Errorf("field Name is required: value is empty")
+	}
+
+	// Price `pagser:".price->number()"`
+	node = pagser.FindSelection(sel, ".price")
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Number))
+	if err != nil {
+		return v, fmt.Errorf("field Price: %w", err)
+	}
+	if hasOut {
+		v.Price = cast.ToFloat64(out)
+	} else {
+		v.Price = cast.ToFloat64(pagser.NodeText(node))
+	}
+
+	// Stock `pagser:".stock,default=0"`
+	node = pagser.FindSelection(sel, ".stock")
+	if node.Size() == 0 {
+		v.Stock = cast.ToInt("0")
+	} else {
+		v.Stock = cast.ToInt(pagser.NodeText(node))
+	}
+
+	// Tags `pagser:".tag"`
+	node = pagser.FindSelection(sel, ".tag")
+	v.Tags = pagser.NodeTexts(node)
+
+	// OnSale `pagser:".sale->exists()"`
+	node = pagser.FindSelection(sel, ".sale")
+	node, out, hasOut, err = pagser.CallPipeline(node, false, pagser.BindFunc(pagser.BuiltinFunctions{}.Exists))
+	if err != nil {
+		return v, fmt.Errorf("field OnSale: %w", err)
+	}
+	if hasOut {
+		v.OnSale = cast.ToBool(out)
+	} else {
+		v.OnSale = cast.ToBool(pagser.NodeText(node))
+	}
+
+	// Link `pagser:"a,omitempty"`
+	node = pagser.FindSelection(sel, "a")
+	if node.Size() > 0 {
+		{
+			item, err := parseLinkSelection(node)
+			if err != nil {
+				return v, fmt.Errorf("field Link: %w", err)
+			}
+			v.Link = &item
+		}
+	}
+	return v, nil
+}
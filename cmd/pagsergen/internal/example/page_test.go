@@ -0,0 +1,79 @@
+package example
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/foolin/pagser"
+)
+
+const rawPageHtml = `
+<html lang="en">
+<head>
+	<title>Page Title</title>
+	<meta name="keywords" content="a,b,c">
+	<meta name="description" content="desc">
+</head>
+<body>
+	<ul class="nav"><li><a href="/a">A</a></li><li><a href="/b">B</a></li></ul>
+	<div class="item"><h2>One</h2><span class="price">$1,200.50</span><span class="stock">3</span>
+		<i class="tag">x</i><i class="tag">y</i><b class="sale">sale</b><a href="/one">more</a></div>
+	<div class="item"><h2>Two</h2><span class="price">9</span></div>
+</body>
+</html>
+`
+
+func TestParsePage(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawPageHtml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParsePage(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want Page
+	if err := pagser.New().ParseDocument(&want, doc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %#v, but got %#v", want, got)
+	}
+	if got.Title != "Page Title" || got.Lang != "EN" || got.Count != 2 || got.Items[0].Price != 1200.5 || got.Items[1].Link != nil {
+		t.Fatalf("unexpected page %#v", got)
+	}
+
+	doc, err = goquery.NewDocumentFromReader(strings.NewReader(`<div class="item"><span class="price">9</span></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParsePage(doc); err == nil || !strings.Contains(err.Error(), "field Items[0]: field Name is required") {
+		t.Fatalf("want required error, but got %v", err)
+	}
+}
+
+func BenchmarkParsePage(b *testing.B) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawPageHtml))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := ParsePage(doc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("reflection", func(b *testing.B) {
+		p := pagser.New()
+		for i := 0; i < b.N; i++ {
+			var page Page
+			if err := p.ParseDocument(&page, doc); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,74 @@
+// Command pagsergen generates the parse functions of the pagser tagged structs without reflection,
+// it is run by go generate in the package of structs, eg:
+//
+//	//go:generate go run github.com/foolin/pagser/cmd/pagsergen -type=PageData
+//
+// The generated `ParsePageData(doc *goquery.Document) (PageData, error)` parses the fields in declaration order
+// with the builtin functions and the methods of structs, the field types are the basic types, the slices of string,
+// int and bool, and the structs of package, the computed fields and the depends tag are not supported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/foolin/pagser"
+)
+
+var (
+	flagType       = flag.String("type", "", "comma separated names of the struct types, required")
+	flagOutput     = flag.String("output", "", "output file name, default is <type>_pagser.go")
+	flagTagName    = flag.String("tagname", "pagser", "struct tag name of pagser.Config.TagName")
+	flagFuncSymbol = flag.String("funcsymbol", "->", "function symbol of pagser.Config.FuncSymbol")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: pagsergen -type=T[,T...] [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *flagType == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "pagsergen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*flagType, ",")
+	output := *flagOutput
+	if output == "" {
+		output = strings.ToLower(types[0]) + "_pagser.go"
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+
+	cfg := pagser.DefaultConfig()
+	cfg.TagName = *flagTagName
+	cfg.FuncSymbol = *flagFuncSymbol
+	p, err := pagser.NewWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+	g, err := newGenerator(p, dir, output)
+	if err != nil {
+		return err
+	}
+	src, err := g.generate(types)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
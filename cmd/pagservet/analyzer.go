@@ -0,0 +1,148 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/foolin/pagser"
+	"golang.org/x/tools/go/analysis"
+)
+
+// badFuncSymbols the mistyped function symbols of the default `->`
+var badFuncSymbols = []string{"=>", "- >", "->>", "—>"}
+
+var (
+	flagTagName    string
+	flagFuncSymbol string
+	flagFuncs      string
+)
+
+// Analyzer checks the pagser struct tags
+var Analyzer = &analysis.Analyzer{
+	Name: "pagservet",
+	Doc: "check the pagser struct tags\n\n" +
+		"The tags are checked for malformed selectors, unknown functions, unsupported field types and bad function symbols, " +
+		"the functions must be builtin, registered by -funcs or the methods of the types in package.",
+	Run: run,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&flagTagName, "tagname", "pagser", "struct tag name of pagser.Config.TagName")
+	Analyzer.Flags.StringVar(&flagFuncSymbol, "funcsymbol", "->", "function symbol of pagser.Config.FuncSymbol")
+	Analyzer.Flags.StringVar(&flagFuncs, "funcs", "", "comma separated names of the functions registered by pagser.RegisterFunc")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	cfg := pagser.DefaultConfig()
+	cfg.TagName = flagTagName
+	cfg.FuncSymbol = flagFuncSymbol
+	p, err := pagser.NewWithConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	methods := packageMethods(pass.Pkg)
+	for _, name := range strings.Split(flagFuncs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			methods[name] = true
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			st, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				checkField(pass, p, methods, field)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// checkField reports the problems of the pagser tag of field
+func checkField(pass *analysis.Pass, p *pagser.Pagser, methods map[string]bool, field *ast.Field) {
+	if field.Tag == nil {
+		return
+	}
+	rawTag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+	tagValue, ok := reflect.StructTag(rawTag).Lookup(flagTagName)
+	if !ok || tagValue == "-" {
+		return
+	}
+
+	if flagFuncSymbol == "->" {
+		for _, symbol := range badFuncSymbols {
+			if strings.Contains(tagValue, symbol) {
+				pass.Reportf(field.Tag.Pos(), "pagser tag `%v` has bad function symbol `%v`, use `->`", tagValue, symbol)
+				return
+			}
+		}
+	}
+
+	funcs, err := p.ValidateTag(tagValue)
+	if err != nil {
+		pass.Reportf(field.Tag.Pos(), "pagser tag is invalid: %v", strings.ReplaceAll(err.Error(), "\n", "; "))
+		return
+	}
+	for _, name := range funcs {
+		if !methods[name] {
+			pass.Reportf(field.Tag.Pos(), "pagser tag `%v` calls unknown function %v", tagValue, name)
+		}
+	}
+
+	if t := pass.TypesInfo.TypeOf(field.Type); t != nil && !strings.HasPrefix(strings.TrimSpace(tagValue), "=") {
+		if unsupported := unsupportedType(t); unsupported != nil {
+			pass.Reportf(field.Type.Pos(), "pagser can not parse the field of type %v", unsupported)
+		}
+	}
+}
+
+// packageMethods returns the method names of the types in package, the functions of tags can be these methods
+func packageMethods(pkg *types.Package) map[string]bool {
+	methods := make(map[string]bool)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		methodSet := types.NewMethodSet(types.NewPointer(typeName.Type()))
+		for i := 0; i < methodSet.Len(); i++ {
+			methods[methodSet.At(i).Obj().Name()] = true
+		}
+	}
+	return methods
+}
+
+// unsupportedType returns the type which can not be set from the html, nil if t is supported
+func unsupportedType(t types.Type) types.Type {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return unsupportedType(u.Elem())
+	case *types.Slice:
+		return unsupportedType(u.Elem())
+	case *types.Array:
+		return unsupportedType(u.Elem())
+	case *types.Map:
+		if unsupported := unsupportedType(u.Key()); unsupported != nil {
+			return unsupported
+		}
+		return unsupportedType(u.Elem())
+	case *types.Chan, *types.Signature:
+		return t
+	case *types.Basic:
+		if u.Info()&types.IsComplex != 0 || u.Kind() == types.UnsafePointer {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+module github.com/foolin/pagser/cmd/pagservet
+
+go 1.21
+
+require (
+	github.com/foolin/pagser v0.2.0
+	golang.org/x/tools v0.24.1
+)
+
+require (
+	github.com/PuerkitoBio/goquery v1.8.1 // indirect
+	github.com/andybalholm/brotli v1.1.1 // indirect
+	github.com/andybalholm/cascadia v1.3.2 // indirect
+	github.com/mattn/go-runewidth v0.0.8 // indirect
+	github.com/mattn/godown v0.0.1 // indirect
+	github.com/spf13/cast v1.5.1 // indirect
+	golang.org/x/mod v0.20.0 // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,13 @@
+// Command pagservet checks the pagser struct tags like go vet, the malformed selectors, unknown functions,
+// unsupported field types and bad function symbols are reported at build time.
+//
+//	go install github.com/foolin/pagser/cmd/pagservet@latest
+//	pagservet ./...
+//	go vet -vettool=$(which pagservet) -funcs=MyFunc ./...
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
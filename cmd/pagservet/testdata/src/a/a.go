@@ -0,0 +1,28 @@
+package a
+
+import "time"
+
+type Item struct {
+	Name  string    `pagser:"span->text()"`
+	Time  time.Time `pagser:"time->attr(datetime)" layout:"2006-01-02"`
+	Price string    `pagser:".price->FormatPrice()"`
+	Label string    `pagser:"=Name + ' ' + Price"`
+	Skip  chan int  `pagser:"-"`
+	Field string
+}
+
+func (item Item) FormatPrice() {}
+
+type Page struct {
+	Title   string                `pagser:"h1 || h2->trim()"`
+	Items   []Item                `pagser:".items li"`
+	Bad     string                `pagser:"h1[->text()"`  // want "pagser tag is invalid: selector `h1\\[` is invalid"
+	Syntax  string                `pagser:"h1->text("`    // want "function `text\\(` syntax error"
+	Unknown string                `pagser:"h1->txet()"`   // want "calls unknown function txet"
+	Symbol  string                `pagser:"h1=>text()"`   // want "bad function symbol `=>`"
+	Chan    chan string           `pagser:"h1"`           // want "can not parse the field of type chan string"
+	Funcs   []func() string       `pagser:"li"`           // want "can not parse the field of type func\\(\\) string"
+	Complex map[string]complex128 `pagser:"li->mapOf(a)"` // want "can not parse the field of type complex128"
+	Expr    int                   `pagser:"=Title *"`     // want "expression"
+	Custom  string                `pagser:"h1->MyFunc()"`
+}
@@ -0,0 +1,45 @@
+// Package collyext integrates pagser with colly, the elements matched by the selector of OnHTML are parsed to
+// structs automatically, eg:
+//
+//	c := colly.NewCollector()
+//	collyext.OnHTMLStruct(c, "div.item", func(e *colly.HTMLElement, item Item, err error) {
+//		if err != nil {
+//			log.Printf("parse %v error: %v", e.Request.URL, err)
+//			return
+//		}
+//		items = append(items, item)
+//	})
+//	err := c.Visit("https://example.com")
+package collyext
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/foolin/pagser"
+	"github.com/gocolly/colly/v2"
+)
+
+// OnHTMLStruct registers the function which is called with each element matched by the selector and the value of
+// type T parsed from the element by the default pagser, T may be a struct or a pointer to a struct
+func OnHTMLStruct[T any](c *colly.Collector, selector string, fn func(e *colly.HTMLElement, data T, err error)) {
+	OnHTMLStructWith(c, pagser.New(), selector, fn)
+}
+
+// OnHTMLStructWith registers the function like OnHTMLStruct, but the value is parsed by p, eg: with the functions
+// registered by RegisterFunc
+func OnHTMLStructWith[T any](c *colly.Collector, p *pagser.Pagser, selector string,
+	fn func(e *colly.HTMLElement, data T, err error)) {
+	c.OnHTML(selector, func(e *colly.HTMLElement) {
+		data, err := ParseElement[T](p, e)
+		fn(e, data, err)
+	})
+}
+
+// ParseElement parses the element to a new value of type T by p, the relative urls of url.URL fields, absHref() and
+// links() are resolved against the url of the request of element
+func ParseElement[T any](p *pagser.Pagser, e *colly.HTMLElement) (T, error) {
+	doc := &goquery.Document{Selection: e.DOM}
+	if e.Request != nil {
+		doc.Url = e.Request.URL
+	}
+	return pagser.ParseDocumentAs[T](p, doc)
+}
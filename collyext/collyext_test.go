@@ -0,0 +1,66 @@
+package collyext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/foolin/pagser"
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Name  string  `pagser:"h2"`
+	Price float64 `pagser:".price->Upper()"`
+	Link  string  `pagser:"a->absHref()"`
+}
+
+type itemName struct {
+	Name string `pagser:"h2"`
+}
+
+const page = `<html><body>
+<div class="item"><h2>Apple</h2><span class="price">1.5</span><a href="/apple">More</a></div>
+<div class="item"><h2>Banana</h2><span class="price">x</span><a href="/banana">More</a></div>
+</body></html>`
+
+func TestOnHTMLStruct(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	cfg := pagser.DefaultConfig()
+	cfg.CastError = true
+	p, err := pagser.NewWithConfig(cfg)
+	require.NoError(t, err)
+	p.RegisterFunc("Upper", func(node *goquery.Selection, args ...string) (interface{}, error) {
+		return strings.ToUpper(node.Text()), nil
+	})
+
+	c := colly.NewCollector()
+	var items []item
+	var errs []error
+	OnHTMLStructWith(c, p, "div.item", func(e *colly.HTMLElement, data item, err error) {
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		items = append(items, data)
+	})
+	var names []string
+	OnHTMLStruct(c, "div.item", func(e *colly.HTMLElement, data *itemName, err error) {
+		require.NoError(t, err)
+		names = append(names, data.Name)
+	})
+	require.NoError(t, c.Visit(server.URL+"/list"))
+
+	require.Equal(t, []item{{Name: "Apple", Price: 1.5, Link: server.URL + "/apple"}}, items)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "field Price")
+	require.Equal(t, []string{"Apple", "Banana"}, names)
+}
@@ -1,13 +1,50 @@
 package pagser
 
+import "time"
+
 const ignoreSymbol = "-"
 
+// layoutTagName struct tag name of the time layout for time.Time fields, eg: `layout:"2006-01-02"`
+const layoutTagName = "layout"
+
+// headerTagName struct tag name of the table column header for the fields of table row, eg: `header:"Price"`
+const headerTagName = "header"
+
+// dependsTagName struct tag name of the fields which must be parsed before the field, eg: `depends:"Price,Quantity"`
+const dependsTagName = "depends"
+
+// matchTagName struct tag name of the selector which detects the page type of the struct of Variant,
+// eg: `pagser_match:"div.product"`
+const matchTagName = "pagser_match"
+
 // Config configuration
 type Config struct {
 	TagName    string //struct tag name, default is `pagser`
 	FuncSymbol string //Function symbol, default is `->`
 	CastError  bool   //Returns an error when the type cannot be converted, default is `false`
 	Debug      bool   //Debug mode, debug will print some log, default is `false`
+	BaseURL    string //Base url to resolve relative url.URL fields and absHref(), the url of ParseURL and `<base href>` of document take precedence, default is empty
+	//OnField is called after each field is parsed with the field path like `Items[0].Name`, the selector of tag,
+	//the count of matched nodes, the field value and the error, for logging and statistics, default is nil
+	OnField func(path string, selector string, matched int, value interface{}, err error)
+	//CollectErrors continues past the failing fields and returns all errors of fields joined by errors.Join,
+	//the failed fields are left as is and the other fields are filled, default is `false`
+	CollectErrors bool
+	//StrictSelectors returns an error if the selector of any field matches nothing, the fields with `default=` or
+	//`omitempty` and the fields of exists(), ifExists() and size() are allowed to be missing, default is `false`
+	StrictSelectors bool
+	//MaxDocumentBytes the max bytes of the document read by ParseReader, ParseURL and ParseResponse, the decompressed
+	//bytes are counted, an error wrapping ErrDocumentTooLarge is returned if it is exceeded, default is 0 that is unlimited
+	MaxDocumentBytes int64
+	//SliceWorkers the max goroutines which parse the items of each slice field concurrently, the items are kept in order,
+	//the methods of structs, the registered functions and OnField must be safe for concurrent use if it is greater than 1,
+	//default is 0 that parses the items one by one
+	SliceWorkers int
+	//ParseTimeout the max duration of each parse, the parsing is aborted with an error wrapping ErrParseTimeout and
+	//context.DeadlineExceeded if it is exceeded, even if a single selector or function is slow. The value is parsed into
+	//a new value which is set to it when the parse completes, so the value is left unchanged if the parse times out and
+	//its fields without tag are reset, default is 0 that is unlimited
+	ParseTimeout time.Duration
 }
 
 var defaultCfg = Config{
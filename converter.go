@@ -0,0 +1,50 @@
+package pagser
+
+import (
+	"reflect"
+
+	"github.com/spf13/cast"
+)
+
+// ConvertFunc convert text to the value of registered type
+//
+//	p.RegisterTypeConverter(reflect.TypeOf(decimal.Decimal{}), func(text string) (interface{}, error) {
+//		return decimal.NewFromString(text)
+//	})
+//
+//	type PageData struct{
+//	     Price decimal.Decimal `pagser:".price->text()"`
+//	}
+type ConvertFunc func(text string) (out interface{}, err error)
+
+// RegisterTypeConverter register converter for type, the converter is used to set the fields of the type
+// (or the pointer and slice of the type) before the builtin implicit type conversion.
+func (p *Pagser) RegisterTypeConverter(t reflect.Type, fn ConvertFunc) {
+	p.mapConverters.Store(t, fn)
+	// the compiled plans are dropped, because they skip the converters of the field types which are not registered
+	p.mapPlans.Range(func(key, value interface{}) bool {
+		p.mapPlans.Delete(key)
+		return true
+	})
+}
+
+func (p *Pagser) findTypeConverter(t reflect.Type) (ConvertFunc, bool) {
+	fn, ok := p.mapConverters.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return fn.(ConvertFunc), true
+}
+
+func (p *Pagser) hasTypeConverter(t reflect.Type) bool {
+	_, ok := p.mapConverters.Load(t)
+	return ok
+}
+
+func convertValue(convert ConvertFunc, value interface{}) (interface{}, error) {
+	text, err := cast.ToStringE(value)
+	if err != nil {
+		return nil, err
+	}
+	return convert(text)
+}
@@ -0,0 +1,61 @@
+package pagser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testDecimal struct {
+	Units int64
+	Cents int64
+}
+
+func parseTestDecimal(text string) (interface{}, error) {
+	var d testDecimal
+	units, cents, _ := strings.Cut(strings.TrimSpace(text), ".")
+	if units == "" {
+		return nil, errors.New("invalid decimal")
+	}
+	for _, ch := range units {
+		d.Units = d.Units*10 + int64(ch-'0')
+	}
+	for _, ch := range cents {
+		d.Cents = d.Cents*10 + int64(ch-'0')
+	}
+	return d, nil
+}
+
+func TestPagser_RegisterTypeConverter(t *testing.T) {
+	type ConverterData struct {
+		Price     testDecimal    `pagser:".item[name='float']->attr(value)"`
+		PricePtr  *testDecimal   `pagser:".item[name='float']:first-child"`
+		Prices    []testDecimal  `pagser:".item[name='float']->eachAttr(value)"`
+		PricePtrs []*testDecimal `pagser:".item[name='float']"`
+		NoMatch   testDecimal    `pagser:".missing"`
+	}
+
+	p := New()
+	p.RegisterTypeConverter(reflect.TypeOf(testDecimal{}), parseTestDecimal)
+
+	var data ConverterData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, testDecimal{123, 45}, data.Price)
+	require.Equal(t, testDecimal{123, 45}, *data.PricePtr)
+	require.Equal(t, []testDecimal{{123, 45}, {678, 90}}, data.Prices)
+	require.Len(t, data.PricePtrs, 2)
+	require.Equal(t, testDecimal{678, 90}, *data.PricePtrs[1])
+	require.Equal(t, testDecimal{}, data.NoMatch)
+
+	cfg := DefaultConfig()
+	cfg.CastError = true
+	p, err = NewWithConfig(cfg)
+	require.NoError(t, err)
+	p.RegisterTypeConverter(reflect.TypeOf(testDecimal{}), parseTestDecimal)
+	err = p.Parse(&data, rawParseHtml)
+	require.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package pagser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// timeNow returns the current time for relative dates, it is replaced in tests
+var timeNow = time.Now
+
+// rxRelativeDate matches the relative date like `2 days ago`, `an hour ago`, `in 3 weeks`, `5m ago`
+var rxRelativeDate = regexp.MustCompile(`^(in\s+)?(\d+|an?)\s*([a-z]+?)\.?\s*(ago)?$`)
+
+// rxOrdinalDay matches the ordinal suffix of day, eg: `3rd`, `21st`
+var rxOrdinalDay = regexp.MustCompile(`\b(\d{1,2})(st|nd|rd|th)\b`)
+
+// fuzzyLayouts the layouts of human formatted dates tried in order, the layouts without year are in the current year
+var fuzzyLayouts = []string{
+	"Jan 2, 2006 3:04 PM",
+	"Jan 2, 2006 15:04",
+	"Jan 2, 2006",
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006 15:04",
+	"January 2, 2006",
+	"Mon, Jan 2, 2006",
+	"Monday, January 2, 2006",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+	"2 January 2006 15:04",
+	"2 January 2006",
+	"Jan 2006",
+	"January 2006",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006.01.02",
+	"2006-01-02 15:04",
+	"01/02/2006 15:04",
+	"01/02/2006",
+	"02.01.2006",
+	"Jan 2 15:04",
+	"Jan 2",
+	"January 2",
+	"2 Jan",
+	"2 January",
+	"Mon, Jan 2",
+}
+
+// parseFuzzyTime parses the human formatted date relative to now, such as `2 days ago`, `yesterday`, `Jan 3`,
+// and the layouts supported by cast.ToTimeE.
+func parseFuzzyTime(text string, now time.Time) (time.Time, error) {
+	value := strings.TrimSpace(text)
+	lower := strings.ToLower(value)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch lower {
+	case "now", "just now", "right now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "last week":
+		return now.AddDate(0, 0, -7), nil
+	case "last month":
+		return now.AddDate(0, -1, 0), nil
+	case "last year":
+		return now.AddDate(-1, 0, 0), nil
+	}
+	if t, ok := parseRelativeTime(lower, now); ok {
+		return t, nil
+	}
+	normalized := rxOrdinalDay.ReplaceAllString(value, "$1")
+	if t, err := cast.ToTimeE(normalized); err == nil {
+		return t, nil
+	}
+	for _, layout := range fuzzyLayouts {
+		t, err := time.ParseInLocation(layout, normalized, now.Location())
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			t = t.AddDate(now.Year(), 0, 0)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("unable to parse date `%v`", text)
+}
+
+// parseRelativeTime parses the relative date like `2 days ago`, `in 3 weeks`, false if it is not a relative date
+func parseRelativeTime(text string, now time.Time) (time.Time, bool) {
+	matches := rxRelativeDate.FindStringSubmatch(text)
+	if matches == nil {
+		return time.Time{}, false
+	}
+	future := matches[1] != ""
+	past := matches[4] != ""
+	if future == past {
+		return time.Time{}, false
+	}
+	n := 1
+	if matches[2] != "a" && matches[2] != "an" {
+		n, _ = strconv.Atoi(matches[2])
+	}
+	if past {
+		n = -n
+	}
+	switch strings.TrimSuffix(matches[3], "s") {
+	case "", "sec", "second":
+		return now.Add(time.Duration(n) * time.Second), true
+	case "m", "min", "minute":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "h", "hr", "hour":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "d", "day":
+		return now.AddDate(0, 0, n), true
+	case "w", "wk", "week":
+		return now.AddDate(0, 0, 7*n), true
+	case "mo", "mon", "month":
+		return now.AddDate(0, n, 0), true
+	case "y", "yr", "year":
+		return now.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
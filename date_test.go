@@ -0,0 +1,52 @@
+package pagser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFuzzyTime(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{"just now", now},
+		{"Today", today},
+		{"yesterday", today.AddDate(0, 0, -1)},
+		{"tomorrow", today.AddDate(0, 0, 1)},
+		{"last week", now.AddDate(0, 0, -7)},
+		{"2 days ago", now.AddDate(0, 0, -2)},
+		{"1 day ago", now.AddDate(0, 0, -1)},
+		{"an hour ago", now.Add(-time.Hour)},
+		{"a minute ago", now.Add(-time.Minute)},
+		{"30 secs ago", now.Add(-30 * time.Second)},
+		{"5m ago", now.Add(-5 * time.Minute)},
+		{"3h ago", now.Add(-3 * time.Hour)},
+		{"in 3 weeks", now.AddDate(0, 0, 21)},
+		{"2 months ago", now.AddDate(0, -2, 0)},
+		{"1 yr ago", now.AddDate(-1, 0, 0)},
+		{"Jan 3", time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"3 January", time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"March 5th, 2023", time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"Jan 2, 2006 3:04 PM", time.Date(2006, 1, 2, 15, 4, 0, 0, time.UTC)},
+		{"2023/12/31", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"2023-12-31T08:00:00Z", time.Date(2023, 12, 31, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseFuzzyTime(tt.text, now)
+		if err != nil {
+			t.Fatalf("parse `%v` error: %v", tt.text, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("parse `%v` want %v, but got %v", tt.text, tt.want, got)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "2 days", "in 2 days ago", "3 parsecs ago"} {
+		if _, err := parseFuzzyTime(text, now); err == nil {
+			t.Fatalf("parse `%v` want an error", text)
+		}
+	}
+}
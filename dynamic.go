@@ -0,0 +1,213 @@
+package pagser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaDefinition the definition of dynamic schema, it is loaded from JSON or YAML, eg:
+//
+//	fields:
+//	  - name: title
+//	    selector: h1
+//	  - name: links
+//	    selector: a
+//	    func: eachAttr(href)
+//	    type: "[]string"
+//	  - name: items
+//	    selector: .item
+//	    type: "[]object"
+//	    fields:
+//	      - name: price
+//	        selector: .price
+//	        func: trim('$')->number()
+//	        type: float64
+type SchemaDefinition struct {
+	Fields []FieldDefinition `json:"fields" yaml:"fields"`
+}
+
+// FieldDefinition the field of SchemaDefinition
+type FieldDefinition struct {
+	Name     string            `json:"name" yaml:"name"`                             // key of the parsed map
+	Selector string            `json:"selector,omitempty" yaml:"selector,omitempty"` // selector of tag, empty for the selection itself
+	Func     string            `json:"func,omitempty" yaml:"func,omitempty"`         // function pipeline of tag, eg: `attr(href)->trim()`
+	Type     string            `json:"type,omitempty" yaml:"type,omitempty"`         // see dynamicTypes, default is `string`
+	Required bool              `json:"required,omitempty" yaml:"required,omitempty"`
+	Default  *string           `json:"default,omitempty" yaml:"default,omitempty"`
+	Fields   []FieldDefinition `json:"fields,omitempty" yaml:"fields,omitempty"` // fields of `object` and `[]object` type
+}
+
+// dynamicTypes the types of FieldDefinition, the slice types are prefixed with `[]`
+var dynamicTypes = map[string]reflect.Type{
+	"":        reflect.TypeOf(""),
+	"string":  reflect.TypeOf(""),
+	"int":     reflect.TypeOf(0),
+	"int64":   reflect.TypeOf(int64(0)),
+	"float64": reflect.TypeOf(float64(0)),
+	"bool":    reflect.TypeOf(false),
+}
+
+// dynamicObjectType the type of FieldDefinition with the nested fields
+const dynamicObjectType = "object"
+
+// DynamicSchema the schema loaded at runtime which parses html to map[string]interface{},
+// it is immutable and reusable by multiple goroutines like Schema
+//
+//	schema, err := p.LoadSchemaFile("rules/example.yaml")
+//	data, err := schema.Parse(html)
+type DynamicSchema struct {
+	schema *Schema
+	fields []dynamicField
+}
+
+// dynamicField the field of struct type built by DynamicSchema
+type dynamicField struct {
+	name   string
+	fields []dynamicField // fields of object and object slice
+}
+
+// LoadSchema loads the JSON or YAML schema definition to DynamicSchema
+func (p *Pagser) LoadSchema(definition []byte) (*DynamicSchema, error) {
+	var def SchemaDefinition
+	var err error
+	if trimmed := bytes.TrimSpace(definition); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(trimmed, &def)
+	} else {
+		err = yaml.Unmarshal(definition, &def)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schema definition error: %w", err)
+	}
+	return p.NewDynamicSchema(def)
+}
+
+// LoadSchemaFile loads the JSON or YAML schema definition file to DynamicSchema
+func (p *Pagser) LoadSchemaFile(filename string) (*DynamicSchema, error) {
+	definition, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return p.LoadSchema(definition)
+}
+
+// NewDynamicSchema compiles the schema definition to DynamicSchema, the functions must be registered functions
+func (p *Pagser) NewDynamicSchema(def SchemaDefinition) (*DynamicSchema, error) {
+	t, fields, err := p.dynamicStructType(def.Fields, "")
+	if err != nil {
+		return nil, err
+	}
+	schema, err := p.Compile(t)
+	if err != nil {
+		return nil, err
+	}
+	return &DynamicSchema{schema: schema, fields: fields}, nil
+}
+
+// Parse parse html to map by the schema
+func (s *DynamicSchema) Parse(document string) (map[string]interface{}, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+	return s.ParseSelectionContext(context.Background(), doc.Selection)
+}
+
+// ParseDocument parse document to map by the schema
+func (s *DynamicSchema) ParseDocument(document *goquery.Document) (map[string]interface{}, error) {
+	return s.ParseSelectionContext(context.Background(), document.Selection)
+}
+
+// ParseSelectionContext parse selection to map by the schema, parse will be aborted when the ctx is done
+func (s *DynamicSchema) ParseSelectionContext(ctx context.Context, selection *goquery.Selection) (map[string]interface{}, error) {
+	v := reflect.New(s.schema.typ)
+	if err := s.schema.ParseSelectionContext(ctx, v.Interface(), selection); err != nil {
+		return nil, err
+	}
+	return dynamicMap(v.Elem(), s.fields), nil
+}
+
+// dynamicStructType builds the struct type of field definitions, the Go field names are generated,
+// the names of fields are kept by dynamicField in the same order
+func (p *Pagser) dynamicStructType(defs []FieldDefinition, parent string) (reflect.Type, []dynamicField, error) {
+	structFields := make([]reflect.StructField, 0, len(defs))
+	fields := make([]dynamicField, 0, len(defs))
+	names := make(map[string]bool)
+	for i, def := range defs {
+		path := joinFieldPath(parent, def.Name)
+		if def.Name == "" {
+			return nil, nil, fmt.Errorf("field %v has no name", joinFieldPath(parent, fmt.Sprintf("#%d", i)))
+		}
+		if names[def.Name] {
+			return nil, nil, fmt.Errorf("field %v is duplicated", path)
+		}
+		names[def.Name] = true
+
+		field := dynamicField{name: def.Name}
+		var fieldType reflect.Type
+		typeName := strings.TrimPrefix(def.Type, "[]")
+		if typeName == dynamicObjectType {
+			if len(def.Fields) == 0 {
+				return nil, nil, fmt.Errorf("field %v of type %v has no fields", path, def.Type)
+			}
+			objectType, objectFields, err := p.dynamicStructType(def.Fields, path)
+			if err != nil {
+				return nil, nil, err
+			}
+			fieldType, field.fields = objectType, objectFields
+		} else if t, ok := dynamicTypes[typeName]; ok && len(def.Fields) == 0 && def.Type != "[]" {
+			fieldType = t
+		} else {
+			return nil, nil, fmt.Errorf("field %v type %v is not supported", path, def.Type)
+		}
+		if typeName != def.Type {
+			fieldType = reflect.SliceOf(fieldType)
+		}
+
+		tagValue := def.Selector
+		if def.Func != "" {
+			tagValue += p.Config.FuncSymbol + def.Func
+		}
+		if def.Required {
+			tagValue += "," + modifierRequired
+		}
+		if def.Default != nil {
+			tagValue += "," + modifierDefault + "'" + strings.ReplaceAll(*def.Default, "'", "\\'") + "'"
+		}
+		structFields = append(structFields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: fieldType,
+			Tag:  reflect.StructTag(fmt.Sprintf("%v:%q", p.Config.TagName, tagValue)),
+		})
+		fields = append(fields, field)
+	}
+	return reflect.StructOf(structFields), fields, nil
+}
+
+// dynamicMap converts the struct value built by dynamicStructType to map
+func dynamicMap(val reflect.Value, fields []dynamicField) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		fieldValue := val.Field(i)
+		switch {
+		case field.fields == nil:
+			out[field.name] = fieldValue.Interface()
+		case fieldValue.Kind() == reflect.Slice:
+			list := make([]map[string]interface{}, fieldValue.Len())
+			for j := range list {
+				list[j] = dynamicMap(fieldValue.Index(j), field.fields)
+			}
+			out[field.name] = list
+		default:
+			out[field.name] = dynamicMap(fieldValue, field.fields)
+		}
+	}
+	return out
+}
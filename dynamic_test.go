@@ -0,0 +1,104 @@
+package pagser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const dynamicHtml = `<h1>Title</h1>
+<a href="/a">A</a><a href="/b">B</a>
+<div class="item"><span class="name">Apple</span><span class="price">$1.50</span><i>10</i></div>
+<div class="item"><span class="name">Pear</span><span class="price">$2</span></div>`
+
+func TestDynamicSchema_Parse(t *testing.T) {
+	p := New()
+
+	definition := `
+fields:
+  - name: title
+    selector: h1
+  - name: links
+    selector: a
+    func: eachAttr(href)
+    type: "[]string"
+  - name: count
+    selector: .item->size()
+    type: int
+  - name: missing
+    selector: .missing
+    default: it's none
+  - name: items
+    selector: .item
+    type: "[]object"
+    fields:
+      - name: name
+        selector: .name
+      - name: price
+        selector: .price
+        func: trim('$')->number()
+        type: float64
+      - name: stock
+        selector: i
+        type: int
+  - name: first
+    selector: .item->eq(0)
+    type: object
+    fields:
+      - name: name
+        selector: .name
+`
+	schema, err := p.LoadSchema([]byte(definition))
+	require.NoError(t, err)
+	data, err := schema.Parse(dynamicHtml)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"title":   "Title",
+		"links":   []string{"/a", "/b"},
+		"count":   2,
+		"missing": "it's none",
+		"items": []map[string]interface{}{
+			{"name": "Apple", "price": 1.5, "stock": 10},
+			{"name": "Pear", "price": 2.0, "stock": 0},
+		},
+		"first": map[string]interface{}{"name": "Apple"},
+	}, data)
+
+	file := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{
+	"fields": [
+		{"name": "title", "selector": "h1", "required": true},
+		{"name": "links", "selector": "a", "func": "eachAttr(href)", "type": "[]string"}
+	]
+}`), 0644))
+	schema, err = p.LoadSchemaFile(file)
+	require.NoError(t, err)
+	data, err = schema.Parse(dynamicHtml)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"title": "Title", "links": []string{"/a", "/b"}}, data)
+	_, err = schema.Parse(`<p></p>`)
+	require.Error(t, err)
+}
+
+func TestDynamicSchema_Load(t *testing.T) {
+	p := New()
+	tests := []string{
+		`fields: [{selector: h1}]`,
+		`fields: [{name: a, selector: h1}, {name: a, selector: h2}]`,
+		`fields: [{name: a, selector: h1, type: time}]`,
+		`fields: [{name: a, selector: h1, type: object}]`,
+		`fields: [{name: a, selector: h1, type: "[]"}]`,
+		`fields: [{name: a, selector: h1, fields: [{name: b}]}]`,
+		`fields: [{name: a, selector: h1, func: unknown()}]`,
+		`fields: [{name: a, selector: "h1["}]`,
+		`{"fields": [}`,
+	}
+	for _, definition := range tests {
+		_, err := p.LoadSchema([]byte(definition))
+		require.Error(t, err, definition)
+	}
+	_, err := p.LoadSchemaFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
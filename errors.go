@@ -0,0 +1,148 @@
+package pagser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// ErrDocumentTooLarge the error of the document which exceeds Config.MaxDocumentBytes, it can be checked by errors.Is
+var ErrDocumentTooLarge = errors.New("document is too large")
+
+// ErrParseTimeout the error of the parsing which exceeds Config.ParseTimeout, it can be checked by errors.Is
+var ErrParseTimeout = errors.New("parse timed out")
+
+// ParseError the error of parsing the field, it can be got by errors.As from the error of parse
+//	var parseErr *pagser.ParseError
+//	if errors.As(err, &parseErr) {
+//		fmt.Println(parseErr.Path, parseErr.Selector, parseErr.Func)
+//	}
+type ParseError struct {
+	Path     string // field path from the root struct, eg: `NavList[2].Link.Url`
+	Tag      string // struct tag value of field
+	Selector string // selector of tag, empty if the tag has no selector
+	Func     string // name of the function which returns the error, empty if it is not a function error
+	Err      error  // the cause
+}
+
+// Error returns the error message with the tag and field path
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("tag=`%v` field %v %v", e.Tag, e.Path, e.Err)
+}
+
+// Unwrap returns the cause
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError returns the *ParseError of current field with the cause, the *ParseError of nested field and
+// the error of ctx are returned as is, so the path of the deepest field is kept.
+func newParseError(ctx context.Context, tag *tagTokenizer, tagValue string, funcName string, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) || isContextError(err) {
+		return err
+	}
+	return &ParseError{
+		Path:     fieldPath(ctx),
+		Tag:      tagValue,
+		Selector: tag.Selector,
+		Func:     funcName,
+		Err:      err,
+	}
+}
+
+// isContextError reports whether err is caused by the ctx is done
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// withParseTimeout calls parse with the ctx of Config.ParseTimeout, the error of the timeout wraps ErrParseTimeout.
+// The parse is called in a goroutine which is abandoned when the ctx is done, so a slow selector or function
+// does not block the caller. The goroutine parses into a new value of target which is set to target only when
+// the parse completes without the timeout, so the abandoned parse never writes the value of the caller.
+// The panic of parse is raised in the caller with the stack of the goroutine if it is not abandoned.
+func (p *Pagser) withParseTimeout(ctx context.Context, target reflect.Value, parse func(ctx context.Context, target reflect.Value) error) error {
+	if p.Config.ParseTimeout <= 0 {
+		return parse(ctx, target)
+	}
+	parseCtx, cancel := context.WithTimeout(ctx, p.Config.ParseTimeout)
+	defer cancel()
+	type result struct {
+		err      error
+		panicked interface{}
+		stack    []byte
+	}
+	fresh := newParseTarget(target)
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{panicked: r, stack: debug.Stack()}
+			}
+		}()
+		done <- result{err: parse(parseCtx, fresh)}
+	}()
+	var res result
+	completed := true
+	select {
+	case res = <-done:
+	case <-parseCtx.Done():
+		// the parse finished at the same time is not abandoned
+		select {
+		case res = <-done:
+		default:
+			res.err = parseCtx.Err()
+			completed = false
+		}
+	}
+	if res.panicked != nil {
+		panic(fmt.Sprintf("%v\n\ngoroutine stack of parse:\n%s", res.panicked, res.stack))
+	}
+	err := res.err
+	// the errors of the parent ctx are returned as is
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %v: %w", ErrParseTimeout, p.Config.ParseTimeout, err)
+	}
+	if completed {
+		setParseTarget(target, fresh)
+	}
+	return err
+}
+
+// newParseTarget returns the new value to parse instead of target, it is the zero value of the struct pointed by
+// target, or the copy of the map of tags
+func newParseTarget(target reflect.Value) reflect.Value {
+	if target.Kind() == reflect.Map {
+		fresh := reflect.MakeMapWithSize(target.Type(), target.Len())
+		iter := target.MapRange()
+		for iter.Next() {
+			fresh.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return fresh
+	}
+	fresh := reflect.New(target.Type().Elem())
+	// the interface keeps the type of the struct it holds
+	if elem := target.Elem(); elem.Kind() == reflect.Interface && !elem.IsNil() {
+		fresh.Elem().Set(reflect.Zero(elem.Elem().Type()))
+	}
+	return fresh
+}
+
+// setParseTarget sets the value parsed by newParseTarget to target
+func setParseTarget(target reflect.Value, fresh reflect.Value) {
+	if target.Kind() == reflect.Map {
+		iter := fresh.MapRange()
+		for iter.Next() {
+			target.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return
+	}
+	target.Elem().Set(fresh.Elem())
+}
+
+// canCollect reports whether the parsing can continue past err and collect it, see Config.CollectErrors
+func (p *Pagser) canCollect(err error) bool {
+	return p.Config.CollectErrors && !isContextError(err)
+}
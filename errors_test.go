@@ -0,0 +1,198 @@
+package pagser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	type Link struct {
+		Url string `pagser:"a->attr()"`
+	}
+	type NavItem struct {
+		Name string `pagser:"span"`
+		Link Link   `pagser:".link"`
+	}
+	type NavData struct {
+		Title   string    `pagser:"h1,required"`
+		NavList []NavItem `pagser:".nav li"`
+		Total   int       `pagser:"=Title * 2"`
+	}
+
+	p := New()
+
+	var data NavData
+	err := p.Parse(&data, `<h1>Nav</h1><ul class="nav"><li><span>A</span></li><li><span>B</span><div class="link"><a href="/b">B</a></div></li></ul>`)
+	require.Error(t, err)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "NavList[0].Link.Url", parseErr.Path)
+	require.Equal(t, "a", parseErr.Selector)
+	require.Equal(t, "attr", parseErr.Func)
+	require.Equal(t, "a->attr()", parseErr.Tag)
+	require.NotNil(t, errors.Unwrap(parseErr))
+	require.Contains(t, err.Error(), "field NavList[0].Link.Url")
+
+	err = p.Parse(&data, `<h2>Nav</h2>`)
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "Title", parseErr.Path)
+	require.Equal(t, "", parseErr.Func)
+	require.Contains(t, err.Error(), "Title is required")
+
+	err = p.Parse(&data, `<h1>Nav</h1>`)
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "Total", parseErr.Path)
+	require.Equal(t, "=Title * 2", parseErr.Tag)
+}
+
+func TestCollectErrors(t *testing.T) {
+	type NavItem struct {
+		Name string `pagser:"span"`
+		Id   int    `pagser:"->attrInt(id, 0, extra)"`
+	}
+	type NavData struct {
+		Title   string    `pagser:"h1"`
+		Missing string    `pagser:"h2,required"`
+		NavList []NavItem `pagser:".nav li"`
+		Total   int       `pagser:"=Title * 2"`
+		Count   int       `pagser:".nav li->size()"`
+	}
+	doc := `<h1>Nav</h1><ul class="nav"><li id="1"><span>A</span></li><li id="2"><span>B</span></li></ul>`
+
+	cfg := DefaultConfig()
+	cfg.CollectErrors = true
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+
+	var data NavData
+	err = p.Parse(&data, doc)
+	require.Error(t, err)
+	require.Equal(t, "Nav", data.Title)
+	require.Equal(t, 2, data.Count)
+	require.Len(t, data.NavList, 2)
+	require.Equal(t, "B", data.NavList[1].Name)
+
+	var paths []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var parseErr *ParseError
+		if errors.As(e, &parseErr) {
+			paths = append(paths, parseErr.Path)
+		}
+	}
+	require.Equal(t, []string{"Missing", "NavList[0].Id", "Total"}, paths)
+	require.Contains(t, err.Error(), "NavList[1].Id")
+
+	// aborts at the first error by default
+	var first NavData
+	err = New().Parse(&first, doc)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "Missing", parseErr.Path)
+	require.Nil(t, first.NavList)
+}
+
+func TestParseTimeout(t *testing.T) {
+	type item struct {
+		Name string `pagser:"->slow()"`
+	}
+	type listData struct {
+		Items []item `pagser:"li"`
+	}
+	doc := "<ul>" + strings.Repeat("<li>a</li>", 100) + "</ul>"
+
+	cfg := DefaultConfig()
+	cfg.ParseTimeout = 20 * time.Millisecond
+	cfg.CollectErrors = true
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+	slow := func(node *goquery.Selection, args ...string) (interface{}, error) {
+		time.Sleep(5 * time.Millisecond)
+		return node.Text(), nil
+	}
+	p.RegisterFunc("slow", slow)
+
+	data := listData{Items: []item{{Name: "kept"}}}
+	started := time.Now()
+	err = p.Parse(&data, doc)
+	require.True(t, errors.Is(err, ErrParseTimeout), "%v", err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+	require.True(t, time.Since(started) < time.Second, "%v", time.Since(started))
+	require.Equal(t, []item{{Name: "kept"}}, data.Items)
+
+	// the error of the parent ctx is not the timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var canceled listData
+	err = p.ParseContext(ctx, &canceled, doc)
+	require.True(t, errors.Is(err, context.Canceled), "%v", err)
+	require.False(t, errors.Is(err, ErrParseTimeout), "%v", err)
+
+	cfg.ParseTimeout = 0
+	p, err = NewWithConfig(cfg)
+	require.NoError(t, err)
+	p.RegisterFunc("slow", slow)
+	var unlimited listData
+	require.NoError(t, p.Parse(&unlimited, "<ul><li>a</li><li>b</li></ul>"))
+	require.Len(t, unlimited.Items, 2)
+}
+
+func TestParseTimeout_BlockingField(t *testing.T) {
+	type blockingData struct {
+		Name string `pagser:"h1->block()"`
+	}
+
+	cfg := DefaultConfig()
+	cfg.ParseTimeout = 20 * time.Millisecond
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+	release := make(chan struct{})
+	defer close(release)
+	p.RegisterFunc("block", func(node *goquery.Selection, args ...string) (interface{}, error) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		return node.Text(), nil
+	})
+
+	data := blockingData{Name: "kept"}
+	started := time.Now()
+	err = p.Parse(&data, "<h1>a</h1>")
+	require.True(t, errors.Is(err, ErrParseTimeout), "%v", err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+	require.True(t, time.Since(started) < time.Second, "%v", time.Since(started))
+	// the abandoned parse does not set the value after it is released
+	release <- struct{}{}
+	require.Equal(t, "kept", data.Name)
+
+	// the value and the map of tags are set if the parse completes in time
+	p.RegisterFunc("block", func(node *goquery.Selection, args ...string) (interface{}, error) {
+		return node.Text(), nil
+	})
+	require.NoError(t, p.Parse(&data, "<h1>a</h1>"))
+	require.Equal(t, "a", data.Name)
+	tags := map[string]string{"name": "h1->block()"}
+	require.NoError(t, p.Parse(&tags, "<h1>a</h1>"))
+	require.Equal(t, map[string]string{"name": "a"}, tags)
+
+	// the panic of parse is raised in the caller with the stack of parse
+	p.RegisterFunc("block", func(node *goquery.Selection, args ...string) (interface{}, error) {
+		panic("block panic")
+	})
+	var panicked interface{}
+	func() {
+		defer func() {
+			panicked = recover()
+		}()
+		_ = p.Parse(&blockingData{}, "<h1>a</h1>")
+	}()
+	require.IsType(t, "", panicked)
+	require.True(t, strings.HasPrefix(panicked.(string), "block panic\n"), "%v", panicked)
+	require.Contains(t, panicked.(string), "errors_test.go")
+}
@@ -0,0 +1,464 @@
+package pagser
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// computedSymbol prefix of the computed field expression, eg: `pagser:"=Price * Quantity"`
+const computedSymbol = "="
+
+// fieldExpr the expression of computed field, it is evaluated after the other fields of struct are parsed.
+// The expression supports the sibling field names like `Price` or `Seller.Name`, the number, 'string',
+// true and false literals, the operators `+ - * / %`, `== != < <= > >=`, `&& || !` and parentheses,
+// `+` concatenates the strings if any operand is a string.
+type fieldExpr struct {
+	Source string
+	Fields []string // the top level field names referenced by expression
+	root   exprNode
+}
+
+// exprNode the node of expression syntax tree
+type exprNode interface {
+	eval(val reflect.Value) (interface{}, error)
+}
+
+type exprLiteral struct {
+	value interface{}
+}
+
+type exprField struct {
+	path []string
+}
+
+type exprUnary struct {
+	op      string
+	operand exprNode
+}
+
+type exprBinary struct {
+	op          string
+	left, right exprNode
+}
+
+// exprToken the token of expression, kind is `num`, `str`, `ident`, `op` or `eof`
+type exprToken struct {
+	kind  string
+	value string
+	pos   int
+}
+
+// exprOperators the operators of expression, the longer operators are matched first
+var exprOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "(", ")"}
+
+// parseFieldExpr parses the expression of computed field
+func parseFieldExpr(source string) (*fieldExpr, error) {
+	tokens, err := tokenizeExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	parser := &exprParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.kind != "eof" {
+		return nil, fmt.Errorf("unexpected `%v` at position %v", token.value, token.pos)
+	}
+	return &fieldExpr{Source: source, Fields: parser.fields, root: root}, nil
+}
+
+// eval evaluates the expression with the field values of struct
+func (expr *fieldExpr) eval(val reflect.Value) (interface{}, error) {
+	return expr.root.eval(val)
+}
+
+// tokenizeExpr splits the expression to tokens
+func tokenizeExpr(source string) ([]exprToken, error) {
+	tokens := make([]exprToken, 0)
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "num", value: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: "ident", value: string(runes[start:i]), pos: start})
+		case r == '\'' || r == '"':
+			start := i
+			var sb strings.Builder
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %v", start)
+			}
+			i++
+			tokens = append(tokens, exprToken{kind: "str", value: sb.String(), pos: start})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, exprToken{kind: "op", value: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected `%v` at position %v", string(r), i)
+			}
+		}
+	}
+	return append(tokens, exprToken{kind: "eof", pos: len(runes)}), nil
+}
+
+// exprParser the recursive descent parser of expression
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	fields []string
+}
+
+func (ep *exprParser) peek() exprToken {
+	return ep.tokens[ep.pos]
+}
+
+func (ep *exprParser) next() exprToken {
+	token := ep.tokens[ep.pos]
+	if token.kind != "eof" {
+		ep.pos++
+	}
+	return token
+}
+
+// acceptOp consumes the next token if it is one of the operators
+func (ep *exprParser) acceptOp(ops ...string) (string, bool) {
+	token := ep.peek()
+	if token.kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if token.value == op {
+			ep.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// parseBinary parses the left associative binary operators
+func (ep *exprParser) parseBinary(operand func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := ep.acceptOp(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (ep *exprParser) parseOr() (exprNode, error) {
+	return ep.parseBinary(ep.parseAnd, "||")
+}
+
+func (ep *exprParser) parseAnd() (exprNode, error) {
+	return ep.parseBinary(ep.parseCompare, "&&")
+}
+
+func (ep *exprParser) parseCompare() (exprNode, error) {
+	return ep.parseBinary(ep.parseAdd, "==", "!=", "<=", ">=", "<", ">")
+}
+
+func (ep *exprParser) parseAdd() (exprNode, error) {
+	return ep.parseBinary(ep.parseMul, "+", "-")
+}
+
+func (ep *exprParser) parseMul() (exprNode, error) {
+	return ep.parseBinary(ep.parseUnary, "*", "/", "%")
+}
+
+func (ep *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := ep.acceptOp("-", "!"); ok {
+		operand, err := ep.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprUnary{op: op, operand: operand}, nil
+	}
+	return ep.parsePrimary()
+}
+
+func (ep *exprParser) parsePrimary() (exprNode, error) {
+	token := ep.next()
+	switch token.kind {
+	case "num":
+		number, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number `%v` at position %v", token.value, token.pos)
+		}
+		return &exprLiteral{value: number}, nil
+	case "str":
+		return &exprLiteral{value: token.value}, nil
+	case "ident":
+		switch token.value {
+		case "true":
+			return &exprLiteral{value: true}, nil
+		case "false":
+			return &exprLiteral{value: false}, nil
+		}
+		path := strings.Split(token.value, ".")
+		for _, name := range path {
+			if name == "" {
+				return nil, fmt.Errorf("invalid field `%v` at position %v", token.value, token.pos)
+			}
+		}
+		ep.fields = append(ep.fields, path[0])
+		return &exprField{path: path}, nil
+	case "op":
+		if token.value == "(" {
+			node, err := ep.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := ep.acceptOp(")"); !ok {
+				return nil, fmt.Errorf("missing `)` at position %v", ep.peek().pos)
+			}
+			return node, nil
+		}
+	case "eof":
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected `%v` at position %v", token.value, token.pos)
+}
+
+func (e *exprLiteral) eval(val reflect.Value) (interface{}, error) {
+	return e.value, nil
+}
+
+func (e *exprField) eval(val reflect.Value) (interface{}, error) {
+	current := val
+	for _, name := range e.path {
+		for current.Kind() == reflect.Pointer || current.Kind() == reflect.Interface {
+			if current.IsNil() {
+				return nil, nil
+			}
+			current = current.Elem()
+		}
+		if current.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("field `%v` is not found in %v", strings.Join(e.path, "."), current.Type())
+		}
+		field, ok := current.Type().FieldByName(name)
+		if !ok || !field.IsExported() {
+			return nil, fmt.Errorf("field `%v` is not found in %v", strings.Join(e.path, "."), current.Type())
+		}
+		current = current.FieldByIndex(field.Index)
+	}
+	return exprValue(current), nil
+}
+
+func (e *exprUnary) eval(val reflect.Value) (interface{}, error) {
+	value, err := e.operand.eval(val)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == "!" {
+		return !exprTruthy(value), nil
+	}
+	number, err := exprNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	return -number, nil
+}
+
+func (e *exprBinary) eval(val reflect.Value) (interface{}, error) {
+	left, err := e.left.eval(val)
+	if err != nil {
+		return nil, err
+	}
+	// short-circuit the logical operators
+	switch e.op {
+	case "&&":
+		if !exprTruthy(left) {
+			return false, nil
+		}
+	case "||":
+		if exprTruthy(left) {
+			return true, nil
+		}
+	}
+	right, err := e.right.eval(val)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "&&", "||":
+		return exprTruthy(right), nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		return exprCompare(e.op, left, right)
+	}
+	_, leftString := left.(string)
+	_, rightString := right.(string)
+	if e.op == "+" && (leftString || rightString) {
+		return exprString(left) + exprString(right), nil
+	}
+	x, err := exprNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	y, err := exprNumber(right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return x / y, nil
+	default:
+		if y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(x, y), nil
+	}
+}
+
+// exprValue converts the field value to the value of expression, the numbers are float64
+func exprValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	}
+	return v.Interface()
+}
+
+// exprNumber converts the value to number, the string is parsed and nil is zero
+func exprNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("`%v` is not a number", v)
+		}
+		return number, nil
+	}
+	return 0, fmt.Errorf("%#v of type %T is not a number", value, value)
+}
+
+// exprString converts the value to string, the number is formatted without trailing zeros and nil is empty
+func exprString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// exprTruthy returns false for nil, false, zero, empty string, and empty slice or map
+func exprTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return !isEmptyValue(value)
+}
+
+// exprCompare compares the numbers, or the strings if any operand is not a number
+func exprCompare(op string, left, right interface{}) (bool, error) {
+	leftBool, lok := left.(bool)
+	rightBool, rok := right.(bool)
+	if lok || rok {
+		if !lok || !rok || (op != "==" && op != "!=") {
+			return false, fmt.Errorf("invalid comparison %#v %v %#v", left, op, right)
+		}
+		return (leftBool == rightBool) == (op == "=="), nil
+	}
+	var cmp int
+	x, xerr := exprNumber(left)
+	y, yerr := exprNumber(right)
+	if xerr == nil && yerr == nil {
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(exprString(left), exprString(right))
+	}
+	switch op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
@@ -0,0 +1,68 @@
+package pagser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldExpr(t *testing.T) {
+	type Seller struct {
+		Name string
+	}
+	type Item struct {
+		Name     string
+		Price    float64
+		Quantity int
+		InStock  bool
+		Seller   *Seller
+		Owner    *Seller
+	}
+	item := Item{Name: "Pen", Price: 1.5, Quantity: 4, InStock: true, Seller: &Seller{Name: "Shop"}}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"Price * Quantity", 6.0},
+		{"Price * Quantity - 1 / 2", 5.5},
+		{"(Price + 0.5) * Quantity % 5", 3.0},
+		{"-Price", -1.5},
+		{"Name + ' x' + Quantity", "Pen x4"},
+		{`Name + " (" + Seller.Name + ")"`, "Pen (Shop)"},
+		{"Owner.Name + ''", ""},
+		{"Quantity > 3 && InStock", true},
+		{"Quantity >= 5 || !InStock", false},
+		{"Name == 'Pen'", true},
+		{"Price != 1.5", false},
+		{"'10' < 9", false},
+	}
+	val := reflect.ValueOf(item)
+	for _, tt := range tests {
+		expr, err := parseFieldExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("parse `%v` error: %v", tt.expr, err)
+		}
+		got, err := expr.eval(val)
+		if err != nil {
+			t.Fatalf("eval `%v` error: %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Fatalf("eval `%v` want %#v, but got %#v", tt.expr, tt.want, got)
+		}
+	}
+
+	for _, source := range []string{"", "Price *", "(Price", "Price $ 2", "'abc", "1.2.3", "Price Quantity"} {
+		if _, err := parseFieldExpr(source); err == nil {
+			t.Fatalf("parse `%v` want an error", source)
+		}
+	}
+	for _, source := range []string{"Unknown + 1", "Price / 0", "Name * 2", "InStock < true", "Name.First"} {
+		expr, err := parseFieldExpr(source)
+		if err != nil {
+			t.Fatalf("parse `%v` error: %v", source, err)
+		}
+		if _, err := expr.eval(val); err == nil {
+			t.Fatalf("eval `%v` want an error", source)
+		}
+	}
+}
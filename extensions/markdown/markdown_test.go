@@ -1 +1,25 @@
 package markdown
+
+import (
+	"testing"
+
+	"github.com/foolin/pagser"
+)
+
+func TestMarkdown(t *testing.T) {
+	type PageData struct {
+		Body string `pagser:"article->Markdown()"`
+	}
+
+	p := pagser.New()
+	Register(p)
+
+	var data PageData
+	err := p.Parse(&data, `<article><h2>Title</h2><p><i>Hello</i></p></article>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Body != "## Title\n\n_Hello_" {
+		t.Fatalf("want markdown %q, but got %q", "## Title\n\n_Hello_", data.Body)
+	}
+}
@@ -0,0 +1,248 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// errNotModified the error of transport when the response is not modified since the value parsed by ParseURL
+var errNotModified = errors.New("not modified")
+
+// Cache stores the responses with the validators for the conditional requests, see WithCache.
+// The implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached response of key, false if it is not cached
+	Get(key string) (*CachedResponse, bool)
+	// Set caches the response of key
+	Set(key string, res *CachedResponse)
+}
+
+// CachedResponse the cached response with the `ETag` or `Last-Modified` validator
+type CachedResponse struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// validator returns the validators of the response, the value parsed from it is reused while it is the same
+func (c *CachedResponse) validator() string {
+	return c.Header.Get("ETag") + "\n" + c.Header.Get("Last-Modified")
+}
+
+// response returns the `200 OK` response of the cached response for the request
+func (c *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// MemoryCache the in-memory Cache
+type MemoryCache struct {
+	mu        sync.RWMutex
+	responses map[string]*CachedResponse
+}
+
+// NewMemoryCache returns the empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{responses: make(map[string]*CachedResponse)}
+}
+
+// Get implements Cache
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res, ok := c.responses[key]
+	return res, ok
+}
+
+// Set implements Cache
+func (c *MemoryCache) Set(key string, res *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[key] = res
+}
+
+// DiskCache the on-disk Cache, each response is stored as a JSON file in the directory, so the cache is kept
+// across the runs. The files which can not be read are treated as not cached, and the errors of writing are ignored.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns the cache which stores the responses in dir, the dir is created if it does not exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path returns the file path of key
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements Cache
+func (c *DiskCache) Get(key string) (*CachedResponse, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	res := &CachedResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, false
+	}
+	return res, true
+}
+
+// Set implements Cache, the file is replaced atomically
+func (c *DiskCache) Set(key string, res *CachedResponse) {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	file, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+}
+
+// revalidationKey the context key of revalidation
+type revalidationKey struct{}
+
+// revalidation the validators of the value parsed by ParseURL, the transport returns errNotModified
+// if the response is not modified since it
+type revalidation struct {
+	parsed    string // the validator of the previously parsed value, empty if there is no value
+	validator string // the validator of the response, empty if the response is not cached
+}
+
+// parsedKey the key of the values parsed by ParseURL
+type parsedKey struct {
+	url string
+	typ reflect.Type
+}
+
+// parsedValue the value parsed by ParseURL from the response of validator
+type parsedValue struct {
+	validator string
+	value     reflect.Value
+}
+
+// cachedRoundTrip sends the conditional request with the validators of the cached response,
+// the cached response is returned if the server responds `304 Not Modified`
+func (t *transport) cachedRoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.fetcher
+	state, _ := req.Context().Value(revalidationKey{}).(*revalidation)
+	key := req.URL.String()
+	cached, ok := f.cache.Get(key)
+	if ok {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	res, err := t.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && res.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+		res.Body.Close()
+		// the headers of 304 response update the cached response
+		header := cached.Header.Clone()
+		for name, values := range res.Header {
+			header[name] = values
+		}
+		cached = &CachedResponse{Header: header, Body: cached.Body}
+		f.cache.Set(key, cached)
+		if state != nil {
+			if state.parsed != "" && state.parsed == cached.validator() {
+				return nil, errNotModified
+			}
+			state.validator = cached.validator()
+		}
+		return cached.response(req), nil
+	}
+	if !cacheable(res) {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	cached = &CachedResponse{Header: res.Header.Clone(), Body: body}
+	f.cache.Set(key, cached)
+	if state != nil {
+		state.validator = cached.validator()
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res, nil
+}
+
+// cacheable reports whether the response can be cached, it must be `200 OK` with the `ETag` or `Last-Modified`
+// validator and can be stored
+func cacheable(res *http.Response) bool {
+	if res.StatusCode != http.StatusOK {
+		return false
+	}
+	if res.Header.Get("ETag") == "" && res.Header.Get("Last-Modified") == "" {
+		return false
+	}
+	return !strings.Contains(strings.ToLower(res.Header.Get("Cache-Control")), "no-store")
+}
+
+// hasConditionalHeader reports whether the request has its own conditional headers, it is sent without the cache
+func hasConditionalHeader(req *http.Request) bool {
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "If-Range"} {
+		if req.Header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// withRevalidation returns the context which makes transport return errNotModified if the response of the url of key
+// is not modified since the value of its type parsed before, and the previously parsed value
+func (f *Fetcher) withRevalidation(ctx context.Context, key parsedKey) (context.Context, *revalidation, *parsedValue) {
+	state := &revalidation{}
+	var parsed *parsedValue
+	if cache, ok := f.parsed.Load(key); ok {
+		parsed = cache.(*parsedValue)
+		state.parsed = parsed.validator
+	}
+	return context.WithValue(ctx, revalidationKey{}, state), state, parsed
+}
@@ -0,0 +1,145 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/foolin/pagser"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheData struct {
+	Title string   `pagser:"h1"`
+	Items []string `pagser:"li"`
+}
+
+func newCacheServer(etag *atomic.Value, full, notModified *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := etag.Load().(string)
+		if r.Header.Get("If-None-Match") == current {
+			atomic.AddInt32(notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(full, 1)
+		w.Header().Set("ETag", current)
+		_, _ = w.Write([]byte(`<h1>` + current + `</h1><ul><li>a</li><li>b</li></ul>`))
+	}))
+}
+
+func TestFetcher_Cache(t *testing.T) {
+	var etag atomic.Value
+	etag.Store(`"v1"`)
+	var full, notModified int32
+	server := newCacheServer(&etag, &full, &notModified)
+	defer server.Close()
+
+	fetcher := NewFetcher(WithCache(NewMemoryCache()))
+	for i := 0; i < 3; i++ {
+		res, err := fetcher.Get(context.Background(), server.URL)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, `<h1>"v1"</h1><ul><li>a</li><li>b</li></ul>`, string(body))
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&full))
+	require.Equal(t, int32(2), atomic.LoadInt32(&notModified))
+
+	// the own conditional headers are sent without the cache
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", `"v1"`)
+	res, err := fetcher.Client().Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNotModified, res.StatusCode)
+}
+
+func TestFetcher_CacheParseURL(t *testing.T) {
+	var etag atomic.Value
+	etag.Store(`"v1"`)
+	var full, notModified int32
+	server := newCacheServer(&etag, &full, &notModified)
+	defer server.Close()
+
+	p := pagser.New()
+	fetcher := NewFetcher(WithCache(NewMemoryCache()))
+	var data cacheData
+	require.NoError(t, fetcher.ParseURL(p, &data, server.URL))
+	require.Equal(t, cacheData{Title: `"v1"`, Items: []string{"a", "b"}}, data)
+
+	// the previously parsed value is returned on 304
+	var cached cacheData
+	require.NoError(t, fetcher.ParseURL(p, &cached, server.URL))
+	require.Equal(t, data, cached)
+	require.Equal(t, int32(1), atomic.LoadInt32(&full))
+	require.Equal(t, int32(1), atomic.LoadInt32(&notModified))
+
+	// the other type is parsed from the cached response
+	var title struct {
+		Title string `pagser:"h1"`
+	}
+	require.NoError(t, fetcher.ParseURL(p, &title, server.URL))
+	require.Equal(t, `"v1"`, title.Title)
+	require.Equal(t, int32(2), atomic.LoadInt32(&notModified))
+
+	// the modified page is parsed again
+	etag.Store(`"v2"`)
+	require.NoError(t, fetcher.ParseURL(p, &data, server.URL))
+	require.Equal(t, `"v2"`, data.Title)
+	require.Equal(t, int32(2), atomic.LoadInt32(&full))
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	var etag atomic.Value
+	etag.Store(`"v1"`)
+	var full, notModified int32
+	server := newCacheServer(&etag, &full, &notModified)
+	defer server.Close()
+
+	p := pagser.New()
+	for i := 0; i < 2; i++ {
+		// the cache is kept across the fetchers
+		cache, err := NewDiskCache(dir)
+		require.NoError(t, err)
+		var data cacheData
+		require.NoError(t, NewFetcher(WithCache(cache)).ParseURL(p, &data, server.URL))
+		require.Equal(t, cacheData{Title: `"v1"`, Items: []string{"a", "b"}}, data)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&full))
+	require.Equal(t, int32(1), atomic.LoadInt32(&notModified))
+
+	cache, err := NewDiskCache(dir)
+	require.NoError(t, err)
+	_, ok := cache.Get("missing")
+	require.False(t, ok)
+	cache.Set("key", &CachedResponse{Header: http.Header{"Etag": {`"x"`}}, Body: []byte("body")})
+	res, ok := cache.Get("key")
+	require.True(t, ok)
+	require.Equal(t, `"x"`, res.Header.Get("ETag"))
+	require.Equal(t, "body", string(res.Body))
+}
+
+func TestCacheable(t *testing.T) {
+	tests := []struct {
+		status int
+		header http.Header
+		ok     bool
+	}{
+		{http.StatusOK, http.Header{"Etag": {`"a"`}}, true},
+		{http.StatusOK, http.Header{"Last-Modified": {"Wed, 21 Oct 2015 07:28:00 GMT"}}, true},
+		{http.StatusOK, http.Header{}, false},
+		{http.StatusOK, http.Header{"Etag": {`"a"`}, "Cache-Control": {"private, no-store"}}, false},
+		{http.StatusNotFound, http.Header{"Etag": {`"a"`}}, false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.ok, cacheable(&http.Response{StatusCode: tt.status, Header: tt.header}), "%v", tt.header)
+	}
+}
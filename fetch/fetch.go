@@ -0,0 +1,141 @@
+// Package fetch fetches the pages for pagser with retries, exponential backoff, per-host rate limiting and
+// politeness controls, eg:
+//
+//	fetcher := fetch.NewFetcher(fetch.WithRetries(3), fetch.WithRateLimit(2), fetch.WithHostConcurrency(1))
+//	var data PageData
+//	err := fetcher.ParseURL(p, &data, "https://example.com")
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/foolin/pagser"
+)
+
+// Defaults of Fetcher
+const (
+	DefaultRetries    = 2
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Fetcher fetches the pages with retries and per-host rate limiting and politeness controls,
+// it is safe for concurrent use
+type Fetcher struct {
+	client      *http.Client // the client with the transport of fetcher
+	retries     int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	rate        float64       // requests per second of each host, 0 is unlimited
+	concurrency int           // max concurrent requests of each host, 0 is unlimited
+	delay       time.Duration // min delay between the requests of each host
+	randomDelay time.Duration // max random delay added to delay
+	header      http.Header
+	proxy       ProxyFunc
+	jar         http.CookieJar
+	hosts       sync.Map // host => *hostLimiter
+
+	checkRobotsTxt bool
+	robotsWarning  func(u *url.URL)
+	robots         sync.Map // scheme://host => *robotsEntry
+
+	cache  Cache
+	parsed sync.Map // parsedKey => *parsedValue
+}
+
+// NewFetcher returns the Fetcher with the options
+func NewFetcher(opts ...FetcherOption) *Fetcher {
+	f := &Fetcher{
+		client:     http.DefaultClient,
+		retries:    DefaultRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		header:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	// the copy of client sends the requests by the transport of fetcher
+	client := *f.client
+	if f.jar != nil {
+		client.Jar = f.jar
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if httpTransport, ok := base.(*http.Transport); ok && f.proxy != nil {
+		httpTransport = httpTransport.Clone()
+		httpTransport.Proxy = f.proxy
+		base = httpTransport
+	}
+	client.Transport = &transport{fetcher: f, base: base}
+	f.client = &client
+	return f
+}
+
+// Client returns the http client which sends the requests with the retries and rate limiting of fetcher
+func (f *Fetcher) Client() *http.Client {
+	return f.client
+}
+
+// Get fetches the url, the response with the status which is retried is returned after all retries fail
+func (f *Fetcher) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.client.Do(req)
+}
+
+// PostForm posts the form data to the url, eg: login to keep the session in the cookie jar of WithCookieJar,
+// the request is retried only if it is not sent, eg: the host can not be connected, so the form is not posted twice
+func (f *Fetcher) PostForm(ctx context.Context, url string, data url.Values) (*http.Response, error) {
+	body := data.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return f.client.Do(req)
+}
+
+// ParseURL fetches the page of url and parses it to v by the Pagser, see pagser.ParseURL
+func (f *Fetcher) ParseURL(p *pagser.Pagser, v interface{}, url string) error {
+	return f.ParseURLContext(context.Background(), p, v, url)
+}
+
+// ParseURLContext fetches the page of url and parses it to v by the Pagser, the fetching and retries are aborted when
+// the ctx is done. With WithCache, the value previously parsed to the same type is copied to v without parsing
+// if the server responds `304 Not Modified`, the copy is shallow, so the slices, maps and pointers are shared.
+func (f *Fetcher) ParseURLContext(ctx context.Context, p *pagser.Pagser, v interface{}, url string) error {
+	rv := reflect.ValueOf(v)
+	if f.cache == nil || rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return p.ParseURL(v, url, pagser.WithContext(ctx), pagser.WithHttpClient(f.client))
+	}
+
+	key := parsedKey{url: url, typ: rv.Type()}
+	ctx, state, parsed := f.withRevalidation(ctx, key)
+	err := p.ParseURL(v, url, pagser.WithContext(ctx), pagser.WithHttpClient(f.client))
+	if errors.Is(err, errNotModified) && parsed != nil {
+		rv.Elem().Set(parsed.value)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if state.validator != "" {
+		value := reflect.New(rv.Type().Elem()).Elem()
+		value.Set(rv.Elem())
+		f.parsed.Store(key, &parsedValue{validator: state.validator, value: value})
+	}
+	return nil
+}
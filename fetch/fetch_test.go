@@ -0,0 +1,151 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/foolin/pagser"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string `pagser:"h1"`
+	Link  string `pagser:"a->absHref()"`
+}
+
+func TestFetcher_ParseURL(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "pagser-test", r.Header.Get("User-Agent"))
+		require.Equal(t, "yes", r.Header.Get("X-Test"))
+		if atomic.AddInt32(&count, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`<h1>Title</h1><a href="/next">Next</a>`))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithRetries(2), WithBackoff(time.Millisecond, 10*time.Millisecond),
+		WithUserAgent("pagser-test"), WithHeader("X-Test", "yes"))
+	var data pageData
+	require.NoError(t, fetcher.ParseURL(pagser.New(), &data, server.URL))
+	require.Equal(t, "Title", data.Title)
+	require.Equal(t, server.URL+"/next", data.Link)
+	require.Equal(t, int32(3), atomic.LoadInt32(&count))
+}
+
+func TestFetcher_Retries(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithRetries(3), WithBackoff(time.Hour, time.Hour))
+	res, err := fetcher.Get(context.Background(), server.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	require.Equal(t, int32(4), atomic.LoadInt32(&count))
+
+	// the client errors are not retried
+	atomic.StoreInt32(&count, 0)
+	var data pageData
+	err = fetcher.ParseURL(pagser.New(), &data, server.URL+"/missing")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+	require.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestFetcher_RetriesPost(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// the form is posted once
+	fetcher := NewFetcher(WithRetries(3), WithBackoff(time.Millisecond, time.Millisecond))
+	res, err := fetcher.PostForm(context.Background(), server.URL, url.Values{"user": {"pagser"}})
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(&count))
+
+	// the request with the `Idempotency-Key` header is retried
+	atomic.StoreInt32(&count, 0)
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("user=pagser"))
+	require.NoError(t, err)
+	req.Header.Set("Idempotency-Key", "1")
+	res, err = fetcher.Client().Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, int32(4), atomic.LoadInt32(&count))
+
+	// the request is not sent if the host can not be connected
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+	_, err = http.Post("http://"+addr, "text/plain", strings.NewReader("a"))
+	require.Error(t, err)
+	require.False(t, isSent(err), "%v", err)
+	require.True(t, isSent(errors.New("EOF")))
+}
+
+func TestFetcher_Context(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithRetries(5), WithBackoff(time.Hour, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var data pageData
+	err := fetcher.ParseURLContext(ctx, pagser.New(), &data, server.URL)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestFetcher_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<h1>Title</h1>`))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithRateLimit(50))
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		res, err := fetcher.Get(context.Background(), server.URL)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+	// the first request is sent immediately, the next 4 requests wait 20ms each
+	require.True(t, time.Since(start) >= 80*time.Millisecond, "elapsed %v", time.Since(start))
+}
+
+func TestFetcher_Backoff(t *testing.T) {
+	f := NewFetcher(WithBackoff(100*time.Millisecond, time.Second))
+	require.Equal(t, 100*time.Millisecond, f.backoff(0, nil))
+	require.Equal(t, 400*time.Millisecond, f.backoff(2, nil))
+	require.Equal(t, time.Second, f.backoff(10, nil))
+	require.Equal(t, time.Second, f.backoff(100, nil))
+	res := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	require.Equal(t, time.Second, f.backoff(0, res))
+}
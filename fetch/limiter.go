@@ -0,0 +1,99 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// limiter returns the limiter of host
+func (f *Fetcher) limiter(host string) *hostLimiter {
+	if cache, ok := f.hosts.Load(host); ok {
+		return cache.(*hostLimiter)
+	}
+	l := &hostLimiter{rate: f.rate, delay: f.delay, randomDelay: f.randomDelay}
+	if f.concurrency > 0 {
+		l.slots = make(chan struct{}, f.concurrency)
+	}
+	cache, _ := f.hosts.LoadOrStore(host, l)
+	return cache.(*hostLimiter)
+}
+
+// hostLimiter limits the requests of host by the rate, concurrency and delay
+type hostLimiter struct {
+	rate        float64       // requests per second, 0 is unlimited
+	delay       time.Duration // min delay between the end of request and the start of next request
+	randomDelay time.Duration // max random delay added to delay
+	slots       chan struct{} // slots of concurrent requests, nil is unlimited
+
+	mu   sync.Mutex
+	next time.Time // the earliest time of next request by rate
+	free time.Time // the earliest time of next request by delay
+}
+
+// acquire waits until the request can be sent, release must be called when the request completes
+func (l *hostLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	var once sync.Once
+	release = func() {
+		once.Do(l.release)
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := now
+	if l.free.After(at) {
+		at = l.free
+	}
+	if l.rate > 0 {
+		if l.next.After(at) {
+			at = l.next
+		}
+		l.next = at.Add(time.Duration(float64(time.Second) / l.rate))
+	}
+	l.mu.Unlock()
+	if err := sleep(ctx, at.Sub(now)); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// release releases the slot of request and starts the delay of next request
+func (l *hostLimiter) release() {
+	if l.delay > 0 || l.randomDelay > 0 {
+		delay := l.delay
+		if l.randomDelay > 0 {
+			delay += time.Duration(rand.Int63n(int64(l.randomDelay)))
+		}
+		l.mu.Lock()
+		if free := time.Now().Add(delay); free.After(l.free) {
+			l.free = free
+		}
+		l.mu.Unlock()
+	}
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// releaseBody releases the host limiter when the body of response is closed
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and releases the host limiter
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
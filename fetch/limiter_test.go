@@ -0,0 +1,100 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcher_HostConcurrency(t *testing.T) {
+	var active, maxActive int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithHostConcurrency(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := fetcher.Get(context.Background(), server.URL)
+			require.NoError(t, err)
+			_, _ = io.ReadAll(res.Body)
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(2), atomic.LoadInt32(&maxActive))
+}
+
+func TestFetcher_Delay(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithDelay(30*time.Millisecond), WithRandomDelay(time.Millisecond))
+	for i := 0; i < 3; i++ {
+		res, err := fetcher.Get(context.Background(), server.URL)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+	for i := 1; i < len(starts); i++ {
+		require.True(t, starts[i].Sub(starts[i-1]) >= 30*time.Millisecond, "delay %v", starts[i].Sub(starts[i-1]))
+	}
+
+	// the limiter is aborted when the ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := fetcher.Get(ctx, server.URL)
+	require.Error(t, err)
+}
+
+func TestFetcher_RetryBody(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.Equal(t, "name=pagser", string(body))
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithBackoff(time.Millisecond, time.Millisecond))
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("name=pagser"))
+	require.NoError(t, err)
+	// the POST request is retried only with the `Idempotency-Key` header
+	req.Header.Set("Idempotency-Key", "1")
+	res, err := fetcher.Client().Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(&count))
+}
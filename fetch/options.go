@@ -0,0 +1,130 @@
+package fetch
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// FetcherOption configure the Fetcher
+type FetcherOption func(f *Fetcher)
+
+// WithClient set the http client used to send the requests, default is `http.DefaultClient`,
+// its transport is wrapped by the fetcher
+func WithClient(client *http.Client) FetcherOption {
+	return func(f *Fetcher) {
+		f.client = client
+	}
+}
+
+// WithRetries set the count of retries after the first request fails, default is DefaultRetries,
+// the network errors, `429 Too Many Requests` and the `5xx` statuses are retried, the non-idempotent requests,
+// eg: POST without the `Idempotency-Key` header, are retried only if they are not sent
+func WithRetries(retries int) FetcherOption {
+	return func(f *Fetcher) {
+		f.retries = retries
+	}
+}
+
+// WithBackoff set the exponential backoff between retries, the delay starts at min and doubles until max,
+// the `Retry-After` header of response takes precedence up to max, default is DefaultMinBackoff and DefaultMaxBackoff
+func WithBackoff(min, max time.Duration) FetcherOption {
+	return func(f *Fetcher) {
+		f.minBackoff = min
+		f.maxBackoff = max
+	}
+}
+
+// WithRateLimit set the max requests per second of each host, the retries are limited too, default is unlimited
+func WithRateLimit(requestsPerSecond float64) FetcherOption {
+	return func(f *Fetcher) {
+		f.rate = requestsPerSecond
+	}
+}
+
+// WithHostConcurrency set the max concurrent requests of each host, the request holds the host until the body of
+// response is closed, default is unlimited
+func WithHostConcurrency(concurrency int) FetcherOption {
+	return func(f *Fetcher) {
+		f.concurrency = concurrency
+	}
+}
+
+// WithDelay set the min delay between the end of request and the start of next request of each host, default is 0
+func WithDelay(delay time.Duration) FetcherOption {
+	return func(f *Fetcher) {
+		f.delay = delay
+	}
+}
+
+// WithRandomDelay set the max random delay added to the delay of WithDelay, default is 0
+func WithRandomDelay(randomDelay time.Duration) FetcherOption {
+	return func(f *Fetcher) {
+		f.randomDelay = randomDelay
+	}
+}
+
+// WithProxy set the proxy of each request, eg: RoundRobinProxy, the transport of client must be nil or *http.Transport
+// which is cloned with the proxy, default is the proxy of transport
+func WithProxy(proxy ProxyFunc) FetcherOption {
+	return func(f *Fetcher) {
+		f.proxy = proxy
+	}
+}
+
+// WithCookieJar set the cookie jar of the client, the session is kept across the requests, eg: login once and then
+// parse many pages, the jar can be persistent, default is the jar of client
+//
+//	jar, _ := cookiejar.New(nil)
+//	fetcher := fetch.NewFetcher(fetch.WithCookieJar(jar))
+func WithCookieJar(jar http.CookieJar) FetcherOption {
+	return func(f *Fetcher) {
+		f.jar = jar
+	}
+}
+
+// WithRobots refuses the requests which are disallowed by the robots.txt of host with ErrDisallowedByRobots,
+// the robots.txt is fetched once and cached for each host, the rules of the group matching the `User-Agent` header
+// are used, default is not checked
+func WithRobots() FetcherOption {
+	return func(f *Fetcher) {
+		f.checkRobotsTxt = true
+	}
+}
+
+// WithRobotsWarning checks the robots.txt like WithRobots, but calls warn and sends the disallowed requests,
+// the requests are warned and sent too if the robots.txt is unavailable
+//
+//	fetch.WithRobotsWarning(func(u *url.URL) { log.Printf("%v is disallowed by robots.txt", u) })
+func WithRobotsWarning(warn func(u *url.URL)) FetcherOption {
+	return func(f *Fetcher) {
+		f.checkRobotsTxt = true
+		f.robotsWarning = warn
+	}
+}
+
+// WithCache set the cache of the `GET` responses with the `ETag` or `Last-Modified` validator, eg: NewMemoryCache or
+// NewDiskCache, the cached requests are sent with `If-None-Match` and `If-Modified-Since` headers, and the cached
+// response is returned if the server responds `304 Not Modified`, default is not cached
+//
+//	cache, _ := fetch.NewDiskCache("cache")
+//	fetcher := fetch.NewFetcher(fetch.WithCache(cache))
+func WithCache(cache Cache) FetcherOption {
+	return func(f *Fetcher) {
+		f.cache = cache
+	}
+}
+
+// WithHeader add a header to each request
+func WithHeader(key, value string) FetcherOption {
+	return func(f *Fetcher) {
+		f.header.Add(key, value)
+	}
+}
+
+// WithUserAgent set the `User-Agent` header of each request
+func WithUserAgent(userAgent string) FetcherOption {
+	return func(f *Fetcher) {
+		f.header.Set("User-Agent", userAgent)
+	}
+}
@@ -0,0 +1,37 @@
+package fetch
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// ProxyFunc returns the proxy url of request like http.Transport.Proxy, nil url is no proxy
+type ProxyFunc func(req *http.Request) (*url.URL, error)
+
+// RoundRobinProxy returns the ProxyFunc which rotates over the proxy urls for each request, eg:
+//
+//	proxy, err := fetch.RoundRobinProxy("http://127.0.0.1:8080", "socks5://127.0.0.1:1080")
+//	fetcher := fetch.NewFetcher(fetch.WithProxy(proxy))
+func RoundRobinProxy(proxyURLs ...string) (ProxyFunc, error) {
+	if len(proxyURLs) == 0 {
+		return nil, errors.New("proxy urls are empty")
+	}
+	urls := make([]*url.URL, len(proxyURLs))
+	for i, proxyURL := range proxyURLs {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, errors.New("proxy url " + proxyURL + " has no scheme or host")
+		}
+		urls[i] = u
+	}
+	var index uint32
+	return func(req *http.Request) (*url.URL, error) {
+		i := atomic.AddUint32(&index, 1) - 1
+		return urls[int(i%uint32(len(urls)))], nil
+	}, nil
+}
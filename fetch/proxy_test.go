@@ -0,0 +1,81 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/foolin/pagser"
+	"github.com/stretchr/testify/require"
+)
+
+type titleData struct {
+	Title string `pagser:"h1"`
+}
+
+func TestFetcher_Proxy(t *testing.T) {
+	newProxy := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the proxy receives the absolute url of target
+			require.Equal(t, "http://pagser.test/page", r.URL.String())
+			_, _ = w.Write([]byte("<h1>" + name + "</h1>"))
+		}))
+	}
+	proxyA, proxyB := newProxy("A"), newProxy("B")
+	defer proxyA.Close()
+	defer proxyB.Close()
+
+	proxy, err := RoundRobinProxy(proxyA.URL, proxyB.URL)
+	require.NoError(t, err)
+	fetcher := NewFetcher(WithProxy(proxy))
+	p := pagser.New()
+	var names []string
+	for i := 0; i < 4; i++ {
+		var data titleData
+		require.NoError(t, fetcher.ParseURL(p, &data, "http://pagser.test/page"))
+		names = append(names, data.Title)
+	}
+	require.Equal(t, []string{"A", "B", "A", "B"}, names)
+
+	for _, urls := range [][]string{{}, {"127.0.0.1:8080"}, {"http://[::1"}} {
+		_, err := RoundRobinProxy(urls...)
+		require.Error(t, err, urls)
+	}
+}
+
+func TestFetcher_CookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "pagser", r.PostForm.Get("user"))
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret", Path: "/"})
+		default:
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("<h1>Private</h1>"))
+		}
+	}))
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	fetcher := NewFetcher(WithCookieJar(jar))
+	p := pagser.New()
+
+	var data titleData
+	require.Error(t, fetcher.ParseURL(p, &data, server.URL+"/private"))
+
+	res, err := fetcher.PostForm(context.Background(), server.URL+"/login", url.Values{"user": {"pagser"}})
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	require.NoError(t, fetcher.ParseURL(p, &data, server.URL+"/private"))
+	require.Equal(t, "Private", data.Title)
+}
@@ -0,0 +1,208 @@
+package fetch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrDisallowedByRobots the error of request which is disallowed by robots.txt, see WithRobots
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// robotsPath the path of robots.txt, the request of it is never checked
+const robotsPath = "/robots.txt"
+
+// maxRobotsSize the max size of robots.txt which is parsed, the rest is ignored like RFC 9309
+const maxRobotsSize = 500 << 10
+
+// robotsRule the Allow or Disallow rule of robots.txt
+type robotsRule struct {
+	allow   bool
+	pattern string // path pattern, `*` matches any characters and the trailing `$` matches the end
+}
+
+// robotsGroup the rules of the user agents of robots.txt
+type robotsGroup struct {
+	agents []string // lower case product tokens, `*` for any agent
+	rules  []robotsRule
+}
+
+// robotsTxt the parsed robots.txt
+type robotsTxt struct {
+	groups []*robotsGroup
+}
+
+// robotsEntry the cached robots.txt of host
+type robotsEntry struct {
+	once   sync.Once
+	robots *robotsTxt
+	err    error
+}
+
+// checkRobots returns ErrDisallowedByRobots if the request is disallowed by robots.txt of its host, or calls the
+// warning function of WithRobotsWarning and returns nil, the unavailable robots.txt is warned the same way
+func (f *Fetcher) checkRobots(req *http.Request) error {
+	if req.URL.Path == robotsPath {
+		return nil
+	}
+	robots, err := f.loadRobots(req.Context(), req.URL)
+	if err == nil && robots.allowed(req.Header.Get("User-Agent"), req.URL) {
+		return nil
+	}
+	if f.robotsWarning != nil {
+		f.robotsWarning(req.URL)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("request %v error: %w", req.URL, ErrDisallowedByRobots)
+}
+
+// loadRobots returns the cached robots.txt of the host of u, or fetches and caches it. The robots.txt which is not
+// found allows all, the server errors disallow all and are not cached, so the robots.txt is fetched again later.
+func (f *Fetcher) loadRobots(ctx context.Context, u *url.URL) (*robotsTxt, error) {
+	key := u.Scheme + "://" + u.Host
+	cache, _ := f.robots.LoadOrStore(key, &robotsEntry{})
+	entry := cache.(*robotsEntry)
+	entry.once.Do(func() {
+		entry.robots, entry.err = f.fetchRobots(ctx, key+robotsPath)
+		if entry.err != nil {
+			f.robots.Delete(key)
+		}
+	})
+	if entry.err != nil {
+		return nil, fmt.Errorf("request %v error: robots.txt is unavailable: %w", u, entry.err)
+	}
+	return entry.robots, nil
+}
+
+// fetchRobots fetches and parses robots.txt
+func (f *Fetcher) fetchRobots(ctx context.Context, robotsURL string) (*robotsTxt, error) {
+	res, err := f.Get(ctx, robotsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode <= 299:
+		return parseRobots(io.LimitReader(res.Body, maxRobotsSize)), nil
+	case res.StatusCode >= 400 && res.StatusCode <= 499:
+		return &robotsTxt{}, nil
+	default:
+		return nil, fmt.Errorf("unexpected status %v", res.Status)
+	}
+}
+
+// parseRobots parses the groups of robots.txt, the unknown lines are ignored
+func parseRobots(reader io.Reader) *robotsTxt {
+	robots := &robotsTxt{}
+	var group *robotsGroup
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if pos := strings.IndexByte(line, '#'); pos >= 0 {
+			line = line[:pos]
+		}
+		pos := strings.IndexByte(line, ':')
+		if pos < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:pos]))
+		value := strings.TrimSpace(line[pos+1:])
+		switch key {
+		case "user-agent":
+			// the consecutive user agents share the group
+			if group == nil || len(group.rules) > 0 {
+				group = &robotsGroup{}
+				robots.groups = append(robots.groups, group)
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			// the empty disallow allows all
+			if group == nil || value == "" {
+				continue
+			}
+			group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+		}
+	}
+	return robots
+}
+
+// allowed reports whether the url is allowed for the user agent, the rules of the group of the longest matched
+// agent are used, or the group of `*`, and the longest matched rule wins, allow wins if they are the same length
+func (r *robotsTxt) allowed(userAgent string, u *url.URL) bool {
+	// the product token of user agent, eg: `Googlebot/2.1 (+http://www.google.com/bot.html)` -> `googlebot`
+	agent := strings.ToLower(userAgent)
+	if pos := strings.IndexAny(agent, "/ "); pos >= 0 {
+		agent = agent[:pos]
+	}
+	var matched *robotsGroup
+	matchedLen := -1
+	for _, group := range r.groups {
+		for _, name := range group.agents {
+			if name == "*" && matchedLen < 0 {
+				matched, matchedLen = group, 0
+			} else if name != "*" && name != "" && strings.HasPrefix(agent, name) && len(name) > matchedLen {
+				matched, matchedLen = group, len(name)
+			}
+		}
+	}
+	if matched == nil {
+		return true
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	allow, ruleLen := true, -1
+	for _, rule := range matched.rules {
+		if len(rule.pattern) < ruleLen || !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > ruleLen || rule.allow {
+			allow, ruleLen = rule.allow, len(rule.pattern)
+		}
+	}
+	return allow
+}
+
+// matchRobotsPattern reports whether the path matches the pattern from the start, `*` matches any characters and
+// the trailing `$` matches the end of path
+func matchRobotsPattern(pattern, path string) bool {
+	if strings.HasSuffix(pattern, "$") {
+		return matchRobotsWildcard(pattern[:len(pattern)-1], path, true)
+	}
+	return matchRobotsWildcard(pattern, path, false)
+}
+
+// matchRobotsWildcard matches the pattern with `*` wildcards, the whole path must be matched if end is true
+func matchRobotsWildcard(pattern, path string, end bool) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	path = path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// the last part of anchored pattern must match the end of path
+		if end && i == len(parts)-2 {
+			return strings.HasSuffix(path, part)
+		}
+		pos := strings.Index(path, part)
+		if pos < 0 {
+			return false
+		}
+		path = path[pos+len(part):]
+	}
+	return !end || path == ""
+}
@@ -0,0 +1,136 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRobotsTxt = `# robots
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow:
+
+User-agent: pagserbot
+User-agent: otherbot
+Disallow: /
+Allow: /open
+`
+
+func TestRobotsTxt_Allowed(t *testing.T) {
+	robots := parseRobots(strings.NewReader(testRobotsTxt))
+	tests := []struct {
+		agent   string
+		path    string
+		allowed bool
+	}{
+		{"Mozilla/5.0", "/", true},
+		{"Mozilla/5.0", "/private", false},
+		{"Mozilla/5.0", "/private/page?a=1", false},
+		{"Mozilla/5.0", "/private/public/page", true},
+		{"Mozilla/5.0", "/files/a.pdf", false},
+		{"Mozilla/5.0", "/files/a.pdf?download", true},
+		{"PagserBot/1.0", "/page", false},
+		{"PagserBot/1.0", "/open/page", true},
+		{"otherbot", "/private/public", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse("https://example.com" + tt.path)
+		require.NoError(t, err)
+		require.Equal(t, tt.allowed, robots.allowed(tt.agent, u), "%v %v", tt.agent, tt.path)
+	}
+
+	require.True(t, (&robotsTxt{}).allowed("pagser", &url.URL{Path: "/private"}))
+}
+
+func TestMatchRobotsPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		matched bool
+	}{
+		{"/", "/a", true},
+		{"/a", "/ab", true},
+		{"/a$", "/ab", false},
+		{"/a$", "/a", true},
+		{"/*/b", "/a/b/c", true},
+		{"/*.php$", "/a/index.php", true},
+		{"/*.php$", "/a/index.php5", false},
+		{"/a*", "/a", true},
+		{"/a*$", "/abc", true},
+		{"/*x*y", "/axbxc", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.matched, matchRobotsPattern(tt.pattern, tt.path), "%v %v", tt.pattern, tt.path)
+	}
+}
+
+func TestFetcher_Robots(t *testing.T) {
+	var robotsCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == robotsPath {
+			atomic.AddInt32(&robotsCount, 1)
+			_, _ = w.Write([]byte(testRobotsTxt))
+			return
+		}
+		_, _ = w.Write([]byte("<h1>ok</h1>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(WithRobots(), WithUserAgent("Mozilla/5.0"))
+	for i := 0; i < 2; i++ {
+		res, err := fetcher.Get(context.Background(), server.URL+"/page")
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+	_, err := fetcher.Get(context.Background(), server.URL+"/private")
+	require.True(t, errors.Is(err, ErrDisallowedByRobots), "%v", err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&robotsCount))
+
+	var warned []string
+	fetcher = NewFetcher(WithRobotsWarning(func(u *url.URL) {
+		warned = append(warned, u.Path)
+	}))
+	res, err := fetcher.Get(context.Background(), server.URL+"/private")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, []string{"/private"}, warned)
+}
+
+func TestFetcher_RobotsStatus(t *testing.T) {
+	var status int32 = http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == robotsPath {
+			w.WriteHeader(int(atomic.LoadInt32(&status)))
+			return
+		}
+		_, _ = w.Write([]byte("<h1>ok</h1>"))
+	}))
+	defer server.Close()
+
+	// robots.txt not found allows all
+	fetcher := NewFetcher(WithRobots(), WithRetries(0))
+	res, err := fetcher.Get(context.Background(), server.URL+"/private")
+	require.NoError(t, err)
+	res.Body.Close()
+
+	// the server error of robots.txt disallows all and is fetched again
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	fetcher = NewFetcher(WithRobots(), WithRetries(0))
+	_, err = fetcher.Get(context.Background(), server.URL+"/page")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "robots.txt is unavailable")
+	atomic.StoreInt32(&status, http.StatusOK)
+	res, err = fetcher.Get(context.Background(), server.URL+"/page")
+	require.NoError(t, err)
+	res.Body.Close()
+}
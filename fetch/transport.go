@@ -0,0 +1,165 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// transport the http.RoundTripper of Fetcher, it sends the request by the base transport with the headers,
+// robots.txt checking, cache, rate limiting and retries of fetcher
+type transport struct {
+	fetcher *Fetcher
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.fetcher
+	ctx := req.Context()
+	req = req.Clone(ctx)
+	for key, values := range f.header {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+
+	if f.checkRobotsTxt {
+		if err := f.checkRobots(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.cache != nil && req.Method == http.MethodGet && !hasConditionalHeader(req) {
+		return t.cachedRoundTrip(req)
+	}
+	return t.roundTrip(req)
+}
+
+// roundTrip sends the request with the rate limiting and retries of fetcher
+func (t *transport) roundTrip(req *http.Request) (*http.Response, error) {
+	f := t.fetcher
+	ctx := req.Context()
+	limiter := f.limiter(req.URL.Host)
+	for attempt := 0; ; attempt++ {
+		release, err := limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := t.base.RoundTrip(req)
+		if attempt >= f.retries || !shouldRetry(ctx, req, res, err) || !rewindBody(req) {
+			// the host is released when the body is closed
+			if err != nil || res.Body == nil {
+				release()
+			} else {
+				res.Body = &releaseBody{ReadCloser: res.Body, release: release}
+			}
+			return res, err
+		}
+
+		delay := f.backoff(attempt, res)
+		if res != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+			res.Body.Close()
+		}
+		release()
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewindBody resets the body of request to retry, the request with body can be retried only if it can be read again
+func rewindBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+// shouldRetry reports whether the request should be retried, the network errors, `429 Too Many Requests` and
+// the `5xx` statuses except `501 Not Implemented` are retried, the request is not retried if the ctx is done.
+// The non-idempotent request, eg: POST, is retried only if it is not sent, see isIdempotent.
+func shouldRetry(ctx context.Context, req *http.Request, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if !isIdempotent(req) {
+		return err != nil && !isSent(err)
+	}
+	if err != nil {
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented)
+}
+
+// isIdempotent reports whether the request can be sent again, the methods GET, HEAD, OPTIONS, TRACE, PUT and DELETE
+// are idempotent, the other methods are idempotent if the request has the `Idempotency-Key` or `X-Idempotency-Key`
+// header like http.Transport
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+// isSent reports whether the request may have been sent to the server before err,
+// the request is not sent if the host can not be resolved or connected
+func isSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return false
+	}
+	var opErr *net.OpError
+	return !errors.As(err, &opErr) || opErr.Op != "dial"
+}
+
+// backoff returns the delay before the retry of attempt, the `Retry-After` seconds of response take precedence
+func (f *Fetcher) backoff(attempt int, res *http.Response) time.Duration {
+	delay := f.maxBackoff
+	if attempt < 32 {
+		delay = f.minBackoff << uint(attempt)
+	}
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+	}
+	if delay > f.maxBackoff || delay < 0 {
+		delay = f.maxBackoff
+	}
+	return delay
+}
+
+// sleep sleeps for the duration, the error of ctx is returned if it is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+package pagser
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var formDataType = reflect.TypeOf(FormData{})
+
+// FormData the form extracted by form() function, includes the action, method and all inputs, selects and textareas.
+//	struct {
+//		Login pagser.FormData `pagser:"form#login->form()"`
+//	}
+type FormData struct {
+	Action  string      // action attribute of form
+	Method  string      // method attribute of form in upper case, default is `GET`
+	Enctype string      // enctype attribute of form
+	Fields  []FormField // the named inputs, selects and textareas in document order
+}
+
+// FormField the input, select or textarea of form
+type FormField struct {
+	Name     string
+	Type     string // type attribute of input, default is `text`, or `select`, `textarea`
+	Value    string // current value, the selected option value of select
+	Checked  bool   // checked state of checkbox and radio
+	Disabled bool
+	Multiple bool         // multiple select
+	Options  []FormOption // options of select
+}
+
+// FormOption the option of select
+type FormOption struct {
+	Value    string
+	Text     string
+	Selected bool
+}
+
+// Field returns the first field of the name, nil if not found
+func (fd *FormData) Field(name string) *FormField {
+	for i := range fd.Fields {
+		if fd.Fields[i].Name == name {
+			return &fd.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Values returns the values the browser would submit: the disabled fields, unchecked checkboxes and radios,
+// buttons and file inputs are excluded, and all selected options of multiple select are included.
+func (fd *FormData) Values() url.Values {
+	values := make(url.Values)
+	for _, field := range fd.Fields {
+		if field.Disabled {
+			continue
+		}
+		switch field.Type {
+		case "submit", "button", "reset", "image", "file":
+			continue
+		case "checkbox", "radio":
+			if !field.Checked {
+				continue
+			}
+		case "select":
+			if field.Multiple {
+				for _, option := range field.Options {
+					if option.Selected {
+						values.Add(field.Name, option.Value)
+					}
+				}
+				continue
+			}
+		}
+		values.Add(field.Name, field.Value)
+	}
+	return values
+}
+
+// Submit send the form values with the overrides to the action url by the form method and returns the response,
+// `http.DefaultClient` is used if client is nil, the action must be absolute url, the values are encoded in query
+// for `GET` method and in `application/x-www-form-urlencoded` body for other methods.
+//	resp, err := data.SearchForm.Submit(nil, url.Values{"q": {"golang"}})
+func (fd *FormData) Submit(client *http.Client, overrides url.Values) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	action, err := url.Parse(fd.Action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid form action: %v error: %v", fd.Action, err)
+	}
+	if !action.IsAbs() {
+		return nil, fmt.Errorf("form action `%v` is not absolute url", fd.Action)
+	}
+	values := fd.Values()
+	for name, value := range overrides {
+		values[name] = value
+	}
+	method := fd.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method == http.MethodGet {
+		action.RawQuery = values.Encode()
+		req, err := http.NewRequest(method, action.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		return client.Do(req)
+	}
+	req, err := http.NewRequest(method, action.String(), strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return client.Do(req)
+}
+
+// formValues returns the value of each field name for the fields of custom struct, the checkbox and radio are included if checked
+func (fd *FormData) formValues() map[string]string {
+	values := make(map[string]string)
+	for _, field := range fd.Fields {
+		if (field.Type == "checkbox" || field.Type == "radio") && !field.Checked {
+			continue
+		}
+		if _, ok := values[field.Name]; !ok {
+			values[field.Name] = field.Value
+		}
+	}
+	return values
+}
+
+// newFormData extracts the first form element of node, or the first form in the descendants of node
+func newFormData(node *goquery.Selection) *FormData {
+	form := node.First()
+	if !form.Is("form") {
+		form = form.Find("form").First()
+	}
+	method := strings.ToUpper(strings.TrimSpace(form.AttrOr("method", "")))
+	if method == "" {
+		method = "GET"
+	}
+	fd := &FormData{
+		Action:  strings.TrimSpace(form.AttrOr("action", "")),
+		Method:  method,
+		Enctype: strings.TrimSpace(form.AttrOr("enctype", "")),
+		Fields:  make([]FormField, 0),
+	}
+	form.Find("input[name], select[name], textarea[name]").Each(func(i int, item *goquery.Selection) {
+		field := FormField{
+			Name:     item.AttrOr("name", ""),
+			Disabled: hasAttr(item, "disabled"),
+		}
+		switch goquery.NodeName(item) {
+		case "select":
+			field.Type = "select"
+			field.Multiple = hasAttr(item, "multiple")
+			field.Options = make([]FormOption, 0)
+			item.Find("option").Each(func(j int, option *goquery.Selection) {
+				text := strings.TrimSpace(option.Text())
+				field.Options = append(field.Options, FormOption{
+					Value:    option.AttrOr("value", text),
+					Text:     text,
+					Selected: hasAttr(option, "selected"),
+				})
+			})
+			for _, option := range field.Options {
+				if option.Selected {
+					field.Value = option.Value
+					break
+				}
+			}
+			if field.Value == "" && !field.Multiple && len(field.Options) > 0 && !hasSelectedOption(field.Options) {
+				// the first option is selected by default
+				field.Value = field.Options[0].Value
+			}
+		case "textarea":
+			field.Type = "textarea"
+			field.Value = item.Text()
+		default:
+			field.Type = strings.ToLower(strings.TrimSpace(item.AttrOr("type", "text")))
+			field.Value = item.AttrOr("value", "")
+			if field.Type == "checkbox" || field.Type == "radio" {
+				if field.Value == "" {
+					field.Value = "on"
+				}
+				field.Checked = hasAttr(item, "checked")
+			}
+		}
+		fd.Fields = append(fd.Fields, field)
+	})
+	return fd
+}
+
+// hasAttr returns true if the first element has the attribute, eg: boolean attributes `checked`, `disabled`
+func hasAttr(node *goquery.Selection, name string) bool {
+	_, ok := node.Attr(name)
+	return ok
+}
+
+// hasSelectedOption returns true if any option is selected
+func hasSelectedOption(options []FormOption) bool {
+	for _, option := range options {
+		if option.Selected {
+			return true
+		}
+	}
+	return false
+}
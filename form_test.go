@@ -0,0 +1,84 @@
+package pagser
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rawFormHtml = `
+<form action="/search" method="post">
+	<input name="q" value="pagser">
+	<input type="hidden" name="token" value="abc">
+	<input type="hidden" name="disabled" value="x" disabled>
+	<input type="checkbox" name="exact" value="1" checked>
+	<input type="checkbox" name="safe" value="1">
+	<select name="tags" multiple><option selected>a</option><option>b</option><option selected>c</option></select>
+	<select name="size"><option>10</option><option>20</option></select>
+	<input type="submit" name="go" value="Go">
+</form>
+`
+
+func TestFormData_Values(t *testing.T) {
+	var data struct {
+		Form FormData `pagser:"form->form()"`
+	}
+	err := New().Parse(&data, rawFormHtml)
+	require.NoError(t, err)
+	require.Equal(t, url.Values{
+		"q":     {"pagser"},
+		"token": {"abc"},
+		"exact": {"1"},
+		"tags":  {"a", "c"},
+		"size":  {"10"},
+	}, data.Form.Values())
+}
+
+func TestFormData_Field(t *testing.T) {
+	var data struct {
+		Form FormData `pagser:"form->form()"`
+	}
+	err := New().Parse(&data, rawFormHtml)
+	require.NoError(t, err)
+	require.Equal(t, "abc", data.Form.Field("token").Value)
+	require.True(t, data.Form.Field("disabled").Disabled)
+	require.True(t, data.Form.Field("tags").Multiple)
+	require.Nil(t, data.Form.Field("missing"))
+}
+
+func TestFormData_Submit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		_, _ = io.WriteString(w, r.Method+" "+r.URL.Path+" "+r.Form.Encode())
+	}))
+	defer server.Close()
+
+	var data struct {
+		Form FormData `pagser:"form->form()"`
+	}
+	err := New().Parse(&data, rawFormHtml)
+	require.NoError(t, err)
+
+	_, err = data.Form.Submit(nil, nil)
+	require.Error(t, err)
+
+	data.Form.Action = server.URL + data.Form.Action
+	resp, err := data.Form.Submit(server.Client(), url.Values{"q": {"golang"}, "page": {"2"}})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "POST /search exact=1&page=2&q=golang&size=10&tags=a&tags=c&token=abc", string(body))
+
+	data.Form.Method = http.MethodGet
+	resp, err = data.Form.Submit(server.Client(), nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "GET /search exact=1&q=pagser&size=10&tags=a&tags=c&token=abc", string(body))
+}
@@ -0,0 +1,131 @@
+package pagser
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Tag the tokenized struct tag, used by the tools such as the code generator cmd/pagsergen
+type Tag struct {
+	Selectors  []string  // alternative selectors, the first one matches any node is used, empty for the selection itself
+	Funcs      []TagFunc // function pipeline, the output of each function is the input of next function
+	Required   bool      // returns an error if the selector matches nothing or the value is empty
+	Strict     bool      // returns an error if the selector matches nothing
+	OmitEmpty  bool      // leaves the field unset if the selector matches nothing
+	HasDefault bool      // whether Default is set
+	Default    string    // default value if the selector matches nothing
+	Computed   string    // expression of computed field without `=`, eg: `Price * Quantity`
+}
+
+// TagFunc the function of Tag
+type TagFunc struct {
+	Name   string
+	Params []string
+}
+
+// ParseTag tokenizes the struct tag value like the parser, the function syntax is checked strictly
+func (p *Pagser) ParseTag(tagValue string) (*Tag, error) {
+	tag, err := p.newTag(tagValue)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Computed != nil {
+		return &Tag{Computed: tag.Computed.Source}, nil
+	}
+	if err := p.checkFuncSyntax(tagValue); err != nil {
+		return nil, err
+	}
+	out := &Tag{
+		Selectors:  tag.Selectors,
+		Required:   tag.Required,
+		Strict:     tag.Strict,
+		OmitEmpty:  tag.OmitEmpty,
+		HasDefault: tag.HasDefault,
+		Default:    tag.Default,
+	}
+	for _, fn := range tag.Funcs {
+		out.Funcs = append(out.Funcs, TagFunc{Name: fn.Name, Params: fn.Params})
+	}
+	return out, nil
+}
+
+// BoundFunc the function of pipeline bound with the arguments of tag
+type BoundFunc func(node *goquery.Selection) (interface{}, error)
+
+// BindFunc binds the arguments of tag to the function, used by the generated code of cmd/pagsergen
+//
+//	pagser.BindFunc(pagser.BuiltinFunctions{}.Attr, "href")
+func BindFunc(fn CallFunc, args ...string) BoundFunc {
+	return func(node *goquery.Selection) (interface{}, error) {
+		return fn(node, args...)
+	}
+}
+
+// FindSelection gets the descendants of selection by the alternative selectors like the tag `a || b`,
+// the selection itself is returned if there is no selector, the invalid selector matches nothing like
+// goquery Find, used by the generated code of cmd/pagsergen
+func FindSelection(selection *goquery.Selection, selectors ...string) *goquery.Selection {
+	tag := &tagTokenizer{Selector: strings.Join(selectors, selectorFallbackSymbol), Selectors: selectors}
+	for _, selector := range selectors {
+		matcher, err := compileSelectorMatcher(selector)
+		if err != nil {
+			return selection.FindNodes()
+		}
+		tag.matchers = append(tag.matchers, matcher)
+	}
+	return tag.find(selection)
+}
+
+// CallPipeline calls the functions with the pipeline of tag, the selection output is set to the returned node,
+// other output is the returned value, the value selection of the last function is converted to the text,
+// or the texts of each item if slice is true, hasValue is false if the last function returns a selection.
+// It is used by the generated code of cmd/pagsergen.
+func CallPipeline(node *goquery.Selection, slice bool, funcs ...BoundFunc) (outNode *goquery.Selection, value interface{}, hasValue bool, err error) {
+	for _, fn := range funcs {
+		fnNode := node
+		if hasValue {
+			if fnNode, err = valueSelection(value); err != nil {
+				return node, nil, false, err
+			}
+		}
+		value, err = fn(fnNode)
+		if err != nil {
+			return node, nil, false, err
+		}
+		if subNode, ok := value.(*goquery.Selection); ok && !isValueSelection(subNode) {
+			node = subNode
+			value, hasValue = nil, false
+		} else {
+			hasValue = true
+		}
+	}
+	if valueNode, ok := value.(*goquery.Selection); ok && hasValue {
+		value = selectionText(valueNode, slice)
+	}
+	return node, value, hasValue, nil
+}
+
+// NodeText returns the trimmed text of node like the string field without function,
+// used by the generated code of cmd/pagsergen
+func NodeText(node *goquery.Selection) string {
+	return strings.TrimSpace(node.Text())
+}
+
+// NodeTexts returns the trimmed text of each element like the slice field without function,
+// used by the generated code of cmd/pagsergen
+func NodeTexts(node *goquery.Selection) []string {
+	return selectionText(node, true).([]string)
+}
+
+// selectionText returns the text of selection, or the texts of each element if slice is true
+func selectionText(node *goquery.Selection, slice bool) interface{} {
+	if !slice {
+		return strings.TrimSpace(node.Text())
+	}
+	list := make([]string, 0)
+	node.Each(func(i int, selection *goquery.Selection) {
+		list = append(list, strings.TrimSpace(selection.Text()))
+	})
+	return list
+}
@@ -0,0 +1,58 @@
+package pagser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTag(t *testing.T) {
+	p := New()
+
+	tag, err := p.ParseTag(`h1 || h2->attr(title)->trimPrefix('a, b'),default=x,required`)
+	require.NoError(t, err)
+	require.Equal(t, &Tag{
+		Selectors:  []string{"h1", "h2"},
+		Funcs:      []TagFunc{{Name: "attr", Params: []string{"title"}}, {Name: "trimPrefix", Params: []string{"a, b"}}},
+		Required:   true,
+		HasDefault: true,
+		Default:    "x",
+	}, tag)
+
+	tag, err = p.ParseTag(`=Price * 2`)
+	require.NoError(t, err)
+	require.Equal(t, "Price * 2", tag.Computed)
+
+	_, err = p.ParseTag(`h1->text(`)
+	require.Error(t, err)
+}
+
+func TestCallPipeline(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<ul><li class="a"> A </li><li> B </li></ul>`))
+	require.NoError(t, err)
+
+	node := FindSelection(doc.Selection, ".missing", "li")
+	require.Equal(t, 2, node.Size())
+	require.Equal(t, "A", NodeText(node.First()))
+	require.Equal(t, []string{"A", "B"}, NodeTexts(node))
+
+	outNode, value, hasValue, err := CallPipeline(node, false, BindFunc(BuiltinSelections{}.Eq, "1"))
+	require.NoError(t, err)
+	require.False(t, hasValue)
+	require.Nil(t, value)
+	require.Equal(t, "B", NodeText(outNode))
+
+	_, value, hasValue, err = CallPipeline(node, true, BindFunc(BuiltinFunctions{}.EachText), BindFunc(BuiltinSelections{}.Eq, "0"))
+	require.NoError(t, err)
+	require.True(t, hasValue)
+	require.Equal(t, []string{"A"}, value)
+
+	_, value, _, err = CallPipeline(node.First(), false, BindFunc(BuiltinFunctions{}.Text), BindFunc(BuiltinFunctions{}.Lower))
+	require.NoError(t, err)
+	require.Equal(t, "a", value)
+
+	_, _, _, err = CallPipeline(node, false, BindFunc(BuiltinFunctions{}.Attr))
+	require.Error(t, err)
+}
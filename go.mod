@@ -6,18 +6,21 @@ toolchain go1.21.1
 
 require (
 	github.com/PuerkitoBio/goquery v1.8.1
+	github.com/andybalholm/brotli v1.1.1
+	github.com/andybalholm/cascadia v1.3.2
 	github.com/mattn/godown v0.0.1
 	github.com/microcosm-cc/bluemonday v1.0.26
 	github.com/spf13/cast v1.5.1
 	github.com/stretchr/testify v1.2.2
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.19.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/andybalholm/cascadia v1.3.2 // indirect
 	github.com/aymerick/douceur v0.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gorilla/css v1.0.0 // indirect
 	github.com/mattn/go-runewidth v0.0.8 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/net v0.17.0 // indirect
 )
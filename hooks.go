@@ -0,0 +1,77 @@
+package pagser
+
+import (
+	"reflect"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	unmarshalerType  = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	beforeParserType = reflect.TypeOf((*BeforeParser)(nil)).Elem()
+	afterParserType  = reflect.TypeOf((*AfterParser)(nil)).Elem()
+)
+
+// Unmarshaler is the interface implemented by types that can parse the selection themselves,
+// it is called instead of the tag-driven parsing at any nesting level, like json.Unmarshaler.
+//	type Price struct {
+//		Amount   float64
+//		Currency string
+//	}
+//
+//	func (pr *Price) UnmarshalPagser(sel *goquery.Selection, p *pagser.Pagser) error {
+//		pr.Currency = sel.AttrOr("data-currency", "USD")
+//		pr.Amount = cast.ToFloat64(sel.Find(".amount").Text())
+//		return nil
+//	}
+type Unmarshaler interface {
+	UnmarshalPagser(sel *goquery.Selection, p *Pagser) error
+}
+
+// BeforeParser is the interface implemented by structs to be called before the fields are parsed,
+// eg: initialize the default values of fields.
+//	func (d *PageData) BeforeParse(sel *goquery.Selection) error {
+//		d.Source = sel.AttrOr("data-source", "")
+//		return nil
+//	}
+type BeforeParser interface {
+	BeforeParse(sel *goquery.Selection) error
+}
+
+// AfterParser is the interface implemented by structs to be called after the fields are parsed,
+// eg: normalize, validate or compute the fields.
+//	func (d *PageData) AfterParse() error {
+//		if d.Title == "" {
+//			return errors.New("title is empty")
+//		}
+//		d.Title = strings.ToUpper(d.Title)
+//		return nil
+//	}
+type AfterParser interface {
+	AfterParse() error
+}
+
+// findUnmarshaler returns the Unmarshaler of value or its pointer, false if the value does not implement it
+func findUnmarshaler(val reflect.Value) (Unmarshaler, bool) {
+	hook, ok := findHook(val, unmarshalerType)
+	if !ok {
+		return nil, false
+	}
+	return hook.(Unmarshaler), true
+}
+
+// findHook returns the value or its pointer which implements the hook interface, false if not implemented
+func findHook(val reflect.Value, hookType reflect.Type) (interface{}, bool) {
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		// the pointer is allocated and the underlying value is checked
+		return nil, false
+	}
+	if val.CanAddr() && val.Addr().Type().Implements(hookType) {
+		return val.Addr().Interface(), true
+	}
+	if val.Type().Implements(hookType) && val.CanInterface() {
+		return val.Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,99 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// implementation the concrete type of interface for the nodes which match the selector
+type implementation struct {
+	selector string
+	matcher  goquery.Matcher
+	typ      reflect.Type // the struct or pointer to struct type which implements the interface
+}
+
+// RegisterImplementation register the concrete type of the interface type iface for the nodes which match the selector,
+// the nil interface field or slice item is set to a new value of the first registered concrete type whose selector
+// matches its node, and the value is parsed by the tags of concrete type. The concrete type is a struct or a pointer to
+// struct which implements iface, the `*` selector matches any node, so it is the fallback of the implementations
+// registered before it.
+//	type Block interface{ Kind() string }
+//	type Article struct {
+//		Blocks []Block `pagser:".body > *"`
+//	}
+//	blockType := reflect.TypeOf((*Block)(nil)).Elem()
+//	err := p.RegisterImplementation(blockType, "figure", reflect.TypeOf(ImageBlock{}))
+//	err = p.RegisterImplementation(blockType, "p", reflect.TypeOf(&TextBlock{}))
+func (p *Pagser) RegisterImplementation(iface reflect.Type, selector string, concrete reflect.Type) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("%v is not an interface", iface)
+	}
+	if concrete == nil || indirectPtrType(concrete).Kind() != reflect.Struct {
+		return fmt.Errorf("implementation %v is not a struct or a pointer to struct", concrete)
+	}
+	if !concrete.Implements(iface) {
+		return fmt.Errorf("implementation %v does not implement %v", concrete, iface)
+	}
+	matcher, err := cascadia.Compile(selector)
+	if err != nil {
+		return fmt.Errorf("implementation %v selector `%v` is invalid: %w", concrete, selector, err)
+	}
+
+	p.implsMu.Lock()
+	defer p.implsMu.Unlock()
+	impls := p.implementations(iface)
+	// the registered slice is not modified, so it is read without lock
+	next := make([]*implementation, len(impls), len(impls)+1)
+	copy(next, impls)
+	next = append(next, &implementation{selector: selector, matcher: matcher, typ: concrete})
+	p.mapImpls.Store(iface, next)
+	return nil
+}
+
+// implementations returns the registered implementations of interface type in order
+func (p *Pagser) implementations(iface reflect.Type) []*implementation {
+	impls, ok := p.mapImpls.Load(iface)
+	if !ok {
+		return nil
+	}
+	return impls.([]*implementation)
+}
+
+// implementationStructs returns the struct types of the registered implementations of interface type
+func (p *Pagser) implementationStructs(iface reflect.Type) []reflect.Type {
+	impls := p.implementations(iface)
+	types := make([]reflect.Type, 0, len(impls))
+	for _, impl := range impls {
+		types = append(types, indirectPtrType(impl.typ))
+	}
+	return types
+}
+
+// doParseImplementation set the nil interface value to the new value of the first implementation which matches
+// the selection and parse it, returns false if the interface type has no registered implementation
+func (p *Pagser) doParseImplementation(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) (bool, error) {
+	impls := p.implementations(val.Type())
+	if len(impls) == 0 {
+		return false, nil
+	}
+	for _, impl := range impls {
+		if !selection.IsMatcher(impl.matcher) {
+			continue
+		}
+		newPtr := reflect.New(indirectPtrType(impl.typ))
+		if err := p.doParse(ctx, newPtr.Elem(), stackValues, selection); err != nil {
+			return true, err
+		}
+		if impl.typ.Kind() == reflect.Ptr {
+			val.Set(newPtr)
+		} else {
+			val.Set(newPtr.Elem())
+		}
+		return true, nil
+	}
+	return true, fmt.Errorf("no implementation of %v matches the node <%v>", val.Type(), goquery.NodeName(selection))
+}
@@ -0,0 +1,112 @@
+package pagser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+type contentBlock interface {
+	Kind() string
+}
+
+type imageBlock struct {
+	Src     string `pagser:"img->attr(src)"`
+	Caption string `pagser:"figcaption"`
+}
+
+func (b imageBlock) Kind() string { return "image" }
+
+type textBlock struct {
+	Text string `pagser:"->text()"`
+}
+
+func (b *textBlock) Kind() string { return "text" }
+
+type otherBlock struct {
+	Name string `pagser:"->NodeName()"`
+}
+
+func (b otherBlock) Kind() string { return "other" }
+
+func (b otherBlock) NodeName(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return goquery.NodeName(node), nil
+}
+
+type blockArticle struct {
+	Title  string         `pagser:"h1"`
+	Lead   contentBlock   `pagser:".body > :first-child"`
+	Blocks []contentBlock `pagser:".body > *"`
+}
+
+const blockHtml = `<h1>Title</h1><div class="body">
+<p>First</p>
+<figure><img src="a.png"><figcaption>Image A</figcaption></figure>
+<video src="v.mp4"></video>
+</div>`
+
+var contentBlockType = reflect.TypeOf((*contentBlock)(nil)).Elem()
+
+func newBlockPagser(t *testing.T, fallback bool) *Pagser {
+	p := New()
+	require.NoError(t, p.RegisterImplementation(contentBlockType, "figure", reflect.TypeOf(imageBlock{})))
+	require.NoError(t, p.RegisterImplementation(contentBlockType, "p", reflect.TypeOf(&textBlock{})))
+	if fallback {
+		require.NoError(t, p.RegisterImplementation(contentBlockType, "*", reflect.TypeOf(otherBlock{})))
+	}
+	return p
+}
+
+func TestPagser_RegisterImplementation(t *testing.T) {
+	want := blockArticle{
+		Title: "Title",
+		Lead:  &textBlock{Text: "First"},
+		Blocks: []contentBlock{
+			&textBlock{Text: "First"},
+			imageBlock{Src: "a.png", Caption: "Image A"},
+			otherBlock{Name: "video"},
+		},
+	}
+
+	p := newBlockPagser(t, true)
+	var data blockArticle
+	require.NoError(t, p.Parse(&data, blockHtml))
+	require.Equal(t, want, data)
+
+	schema, err := p.Compile(reflect.TypeOf(blockArticle{}))
+	require.NoError(t, err)
+	var compiled blockArticle
+	require.NoError(t, schema.Parse(&compiled, blockHtml))
+	require.Equal(t, want, compiled)
+
+	// the node which matches no implementation is an error
+	var missing blockArticle
+	err = newBlockPagser(t, false).Parse(&missing, blockHtml)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no implementation of pagser.contentBlock matches the node <video>")
+}
+
+func TestPagser_RegisterImplementationError(t *testing.T) {
+	p := New()
+	err := p.RegisterImplementation(reflect.TypeOf(imageBlock{}), "p", reflect.TypeOf(imageBlock{}))
+	require.EqualError(t, err, "pagser.imageBlock is not an interface")
+	err = p.RegisterImplementation(contentBlockType, "p", reflect.TypeOf(textBlock{}))
+	require.EqualError(t, err, "implementation pagser.textBlock does not implement pagser.contentBlock")
+	err = p.RegisterImplementation(contentBlockType, "p", reflect.TypeOf(""))
+	require.EqualError(t, err, "implementation string is not a struct or a pointer to struct")
+	err = p.RegisterImplementation(contentBlockType, "p[", reflect.TypeOf(imageBlock{}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "selector `p[` is invalid")
+
+	// the tags of implementations are validated
+	type badBlock struct {
+		imageBlock
+		Bad string `pagser:"p->Missing()"`
+	}
+	require.NoError(t, p.RegisterImplementation(contentBlockType, "p", reflect.TypeOf(badBlock{})))
+	err = p.Validate(&blockArticle{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "method not found: Missing")
+}
@@ -0,0 +1,138 @@
+package pagser
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// innerTextBlocks the block elements separated by a line break in innerText, value is the count of line breaks
+var innerTextBlocks = map[string]int{
+	"p":       2,
+	"address": 1, "article": 1, "aside": 1, "blockquote": 1, "caption": 1, "dd": 1, "details": 1, "dialog": 1,
+	"div": 1, "dl": 1, "dt": 1, "fieldset": 1, "figcaption": 1, "figure": 1, "footer": 1, "form": 1,
+	"h1": 1, "h2": 1, "h3": 1, "h4": 1, "h5": 1, "h6": 1, "header": 1, "hr": 1, "li": 1, "main": 1, "nav": 1,
+	"ol": 1, "pre": 1, "section": 1, "summary": 1, "table": 1, "tr": 1, "ul": 1,
+}
+
+// innerTextIgnores the elements not rendered in innerText
+var innerTextIgnores = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true, "iframe": true,
+}
+
+// innerTextBuilder builds the text like browser `innerText`, the whitespaces are collapsed except in `<pre>`,
+// the line breaks required by blocks are collapsed to the maximum count and trimmed at the start and end.
+type innerTextBuilder struct {
+	sb     strings.Builder
+	breaks int  // the line breaks required before the next text
+	space  bool // the space required before the next text
+}
+
+// innerText returns the text of elements like browser `innerText`, the block elements such as `<p>`, `<div>`, `<li>`
+// are separated by line breaks, `<br>` is a line break and the cells of table row are separated by tab.
+func innerText(node *goquery.Selection) string {
+	builder := &innerTextBuilder{}
+	for _, n := range node.Nodes {
+		builder.walk(n, false)
+	}
+	return builder.sb.String()
+}
+
+// walk writes the text of node and its descendants
+func (b *innerTextBuilder) walk(n *html.Node, pre bool) {
+	switch n.Type {
+	case html.TextNode:
+		if pre {
+			b.write(n.Data)
+		} else {
+			b.writeCollapsed(n.Data)
+		}
+		return
+	case html.ElementNode:
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			b.walk(c, pre)
+		}
+		return
+	}
+	if innerTextIgnores[n.Data] {
+		return
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == "hidden" {
+			return
+		}
+	}
+	switch n.Data {
+	case "br":
+		b.write("\n")
+		return
+	case "td", "th":
+		if isFollowingCell(n) {
+			b.write("\t")
+		}
+	case "pre":
+		pre = true
+	}
+	breaks := innerTextBlocks[n.Data]
+	b.requireBreaks(breaks)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.walk(c, pre)
+	}
+	b.requireBreaks(breaks)
+}
+
+// writeCollapsed writes the text with the runs of whitespaces collapsed to single space
+func (b *innerTextBuilder) writeCollapsed(text string) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		if text != "" {
+			b.space = true
+		}
+		return
+	}
+	if strings.TrimLeft(text, " \t\r\n\f") != text {
+		b.space = true
+	}
+	for i, word := range words {
+		if i > 0 {
+			b.space = true
+		}
+		b.write(word)
+	}
+	if strings.TrimRight(text, " \t\r\n\f") != text {
+		b.space = true
+	}
+}
+
+// write writes the text after the required line breaks or space, they are dropped at the start of text
+func (b *innerTextBuilder) write(text string) {
+	if b.sb.Len() > 0 {
+		if b.breaks > 0 {
+			b.sb.WriteString(strings.Repeat("\n", b.breaks))
+		} else if b.space && !strings.HasSuffix(b.sb.String(), "\n") && !strings.HasPrefix(text, "\n") {
+			b.sb.WriteByte(' ')
+		}
+	}
+	b.breaks = 0
+	b.space = false
+	b.sb.WriteString(text)
+}
+
+// requireBreaks requires the count of line breaks before the next text
+func (b *innerTextBuilder) requireBreaks(count int) {
+	if count > b.breaks {
+		b.breaks = count
+	}
+}
+
+// isFollowingCell returns true if there is a cell before the cell in the row
+func isFollowingCell(n *html.Node) bool {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode && (s.Data == "td" || s.Data == "th") {
+			return true
+		}
+	}
+	return false
+}
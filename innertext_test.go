@@ -0,0 +1,36 @@
+package pagser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestInnerText(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{`<div>  Hello   <b>World</b> !</div>`, "Hello World !"},
+		{`<div><p>First
+			paragraph</p><p>Second</p></div>`, "First paragraph\n\nSecond"},
+		{`<div>Line 1<br>Line 2<br/> Line 3</div>`, "Line 1\nLine 2\nLine 3"},
+		{`<ul><li>A</li> <li>B</li><li> C </li></ul>`, "A\nB\nC"},
+		{`<div><h1>Title</h1><div><div>Nested</div></div>Tail</div>`, "Title\nNested\nTail"},
+		{`<div><pre>a  b
+  c</pre>after</div>`, "a  b\n  c\nafter"},
+		{`<table><tr><th>Name</th><th>Age</th></tr><tr><td>Tom</td><td>12</td></tr></table>`, "Name\tAge\nTom\t12"},
+		{`<div>Visible<script>var a;</script><style>p{}</style><span hidden>Hidden</span></div>`, "Visible"},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := innerText(doc.Find("body").Children().First())
+		if got != tt.want {
+			t.Fatalf("innerText `%v` want %q, but got %q", tt.html, tt.want, got)
+		}
+	}
+}
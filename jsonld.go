@@ -0,0 +1,95 @@
+package pagser
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jsonLdSelector the selector of JSON-LD structured data scripts
+const jsonLdSelector = `script[type="application/ld+json"]`
+
+// jsonLdItems returns the JSON-LD items of all `<script type="application/ld+json">` in the elements,
+// the top level arrays and `@graph` are flattened, and the invalid json scripts are skipped.
+func jsonLdItems(node *goquery.Selection) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0)
+	node.Filter(jsonLdSelector).AddSelection(node.Find(jsonLdSelector)).Each(func(i int, script *goquery.Selection) {
+		var data interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(script.Text())), &data); err != nil {
+			return
+		}
+		items = appendJsonLdItems(items, data)
+	})
+	return items
+}
+
+// appendJsonLdItems append the objects of data to items, the arrays and `@graph` of data are flattened
+func appendJsonLdItems(items []map[string]interface{}, data interface{}) []map[string]interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			items = appendJsonLdItems(items, item)
+		}
+	case map[string]interface{}:
+		if graph, ok := v["@graph"]; ok {
+			return appendJsonLdItems(items, graph)
+		}
+		items = append(items, v)
+	}
+	return items
+}
+
+// findJsonLdType finds the first JSON-LD or microdata object which `@type` is itemType in the data and its nested values depth-first,
+// eg: `Product` matches `Product`, `["Product", "Thing"]` and `https://schema.org/Product`.
+func findJsonLdType(data interface{}, itemType string) map[string]interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if found := findJsonLdType(item, itemType); found != nil {
+				return found
+			}
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			if found := findJsonLdType(item, itemType); found != nil {
+				return found
+			}
+		}
+	case map[string]interface{}:
+		if isJsonLdType(v["@type"], itemType) {
+			return v
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if found := findJsonLdType(v[key], itemType); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// isJsonLdType returns true if the `@type` value matches the type name
+func isJsonLdType(value interface{}, itemType string) bool {
+	switch v := value.(type) {
+	case string:
+		name := v
+		if pos := strings.LastIndexAny(name, "/:#"); pos >= 0 {
+			name = name[pos+1:]
+		}
+		return strings.EqualFold(name, itemType) || strings.EqualFold(v, itemType)
+	case []interface{}:
+		for _, item := range v {
+			if isJsonLdType(item, itemType) {
+				return true
+			}
+		}
+	}
+	return false
+}
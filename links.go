@@ -0,0 +1,115 @@
+package pagser
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PageLinks the `<link rel=...>` relations of page extracted by links() function,
+// the hrefs are resolved against the base url of document.
+//	type PageData struct {
+//		Links pagser.PageLinks `pagser:"head->links()"`
+//	}
+type PageLinks struct {
+	Canonical  string     // rel=canonical
+	Next       string     // rel=next
+	Prev       string     // rel=prev or rel=previous
+	AmpHtml    string     // rel=amphtml
+	Manifest   string     // rel=manifest
+	Alternates []PageLink // rel=alternate, including the hreflang alternates and feeds
+	Icons      []PageLink // rel=icon, shortcut icon, apple-touch-icon and mask-icon
+	All        []PageLink // all links in document order
+}
+
+// PageLink the `<link>` element
+type PageLink struct {
+	Rel      string
+	Href     string
+	HrefLang string
+	Type     string
+	Sizes    string
+	Title    string
+	Media    string
+}
+
+// HrefLang returns the href of alternate link for the language, eg: `en`, `x-default`, empty if not found
+func (pl *PageLinks) HrefLang(lang string) string {
+	for _, link := range pl.Alternates {
+		if strings.EqualFold(link.HrefLang, lang) {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// links links() get the `<link rel=...>` relations in the elements, return PageLinks.
+//	struct {
+//		Links pagser.PageLinks `pagser:"head->links()"`
+//	}
+func links(parse *parseBase, node *goquery.Selection, args ...string) (out interface{}, err error) {
+	base, err := parse.get()
+	if err != nil {
+		return PageLinks{}, err
+	}
+	links := PageLinks{
+		Alternates: make([]PageLink, 0),
+		Icons:      make([]PageLink, 0),
+		All:        make([]PageLink, 0),
+	}
+	node.Filter("link[rel]").AddSelection(node.Find("link[rel]")).Each(func(i int, item *goquery.Selection) {
+		link := PageLink{
+			Rel:      strings.TrimSpace(item.AttrOr("rel", "")),
+			Href:     resolveHref(base, item.AttrOr("href", "")),
+			HrefLang: strings.TrimSpace(item.AttrOr("hreflang", "")),
+			Type:     strings.TrimSpace(item.AttrOr("type", "")),
+			Sizes:    strings.TrimSpace(item.AttrOr("sizes", "")),
+			Title:    strings.TrimSpace(item.AttrOr("title", "")),
+			Media:    strings.TrimSpace(item.AttrOr("media", "")),
+		}
+		links.All = append(links.All, link)
+		for _, rel := range strings.Fields(strings.ToLower(link.Rel)) {
+			switch rel {
+			case "canonical":
+				setFirstHref(&links.Canonical, link.Href)
+			case "next":
+				setFirstHref(&links.Next, link.Href)
+			case "prev", "previous":
+				setFirstHref(&links.Prev, link.Href)
+			case "amphtml":
+				setFirstHref(&links.AmpHtml, link.Href)
+			case "manifest":
+				setFirstHref(&links.Manifest, link.Href)
+			case "alternate":
+				links.Alternates = append(links.Alternates, link)
+			case "icon", "apple-touch-icon", "apple-touch-icon-precomposed", "mask-icon":
+				links.Icons = append(links.Icons, link)
+			default:
+				continue
+			}
+			break
+		}
+	})
+	return links, nil
+}
+
+// setFirstHref set the href if the value is empty
+func setFirstHref(value *string, href string) {
+	if *value == "" {
+		*value = href
+	}
+}
+
+// resolveHref resolves the href against the base url, the href is returned as is if it is invalid or base is nil
+func resolveHref(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if base == nil || href == "" {
+		return href
+	}
+	hrefUrl, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(hrefUrl).String()
+}
@@ -0,0 +1,22 @@
+package pagser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageLinks_HrefLang(t *testing.T) {
+	var data struct {
+		Links PageLinks `pagser:"head->links()"`
+	}
+	err := New().Parse(&data, `<html><head>
+<link rel="alternate" hreflang="en" href="/en/post-1">
+<link rel="alternate" hreflang="x-default" href="/post-1">
+<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+</head><body></body></html>`)
+	require.NoError(t, err)
+	require.Equal(t, "/en/post-1", data.Links.HrefLang("EN"))
+	require.Equal(t, "/post-1", data.Links.HrefLang("x-default"))
+	require.Equal(t, "", data.Links.HrefLang("fr"))
+}
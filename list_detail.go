@@ -0,0 +1,132 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// DefaultDetailConcurrency the default max concurrent requests of the detail pages of ParseListDetail
+const DefaultDetailConcurrency = 4
+
+// ListDetail the item of the listing page and its detail page parsed by ParseListDetail
+type ListDetail[S any, D any] struct {
+	Summary S      // the item parsed from the listing page
+	URL     string // the absolute url of detail page
+	Detail  D      // the value parsed from the detail page
+	Err     error  // the error of parsing the item or fetching and parsing the detail page
+}
+
+// WithDetailConcurrency set the max concurrent requests of the detail pages of ParseListDetail,
+// default is DefaultDetailConcurrency
+func WithDetailConcurrency(concurrency int) RequestOption {
+	return func(opts *requestOptions) {
+		opts.detailConcurrency = concurrency
+	}
+}
+
+// ParseListDetail fetch the listing page of listURL and parse each element matched by itemSelector to the summary S,
+// then fetch and parse the detail page of the url returned by detailURL to the detail D concurrently, the relative url
+// is resolved against the url of listing page. The results are in the order of items, the errors of items and detail
+// pages are set to the Err of results, only the error of listing page is returned.
+//	results, err := pagser.ParseListDetail[Summary, Detail](p, "https://example.com/list", "div.item",
+//		func(item Summary) string { return item.Link }, pagser.WithDetailConcurrency(8))
+func ParseListDetail[S any, D any](p *Pagser, listURL string, itemSelector string, detailURL func(item S) string,
+	opts ...RequestOption) ([]ListDetail[S, D], error) {
+	matcher, err := cascadia.Compile(itemSelector)
+	if err != nil {
+		return nil, fmt.Errorf("item selector `%v` is invalid: %w", itemSelector, err)
+	}
+	ro := newRequestOptions(opts...)
+
+	listDoc, err := p.fetchPage(ro, listURL)
+	if err != nil {
+		return nil, err
+	}
+	results := parseListItems[S, D](ro.ctx, p, listDoc, listDoc.FindMatcher(matcher), detailURL)
+
+	concurrency := ro.detailConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDetailConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		result := &results[i]
+		if result.Err != nil {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			if err := ro.ctx.Err(); err != nil {
+				result.Err = err
+				return
+			}
+			doc, err := p.fetchPage(ro, result.URL)
+			if err == nil {
+				result.Detail, err = parseAs[D](func(v interface{}) error {
+					return p.ParseDocumentContext(ro.ctx, v, doc)
+				})
+			}
+			if err != nil {
+				result.Err = fmt.Errorf("detail page %v error: %w", result.URL, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// parseListItems parse the items of listing page to the summaries and resolve the urls of their detail pages
+func parseListItems[S any, D any](ctx context.Context, p *Pagser, listDoc *goquery.Document, items *goquery.Selection,
+	detailURL func(item S) string) []ListDetail[S, D] {
+	// the base url of listing page is resolved once for all items
+	ctx = p.withParseBase(withDocumentUrl(ctx, listDoc), listDoc.Selection)
+	results := make([]ListDetail[S, D], items.Length())
+	items.Each(func(i int, item *goquery.Selection) {
+		result := &results[i]
+		result.Summary, result.Err = parseAs[S](func(v interface{}) error {
+			return p.ParseSelectionContext(ctx, v, item)
+		})
+		if result.Err != nil {
+			result.Err = fmt.Errorf("item %v error: %w", i, result.Err)
+			return
+		}
+		rawURL := strings.TrimSpace(detailURL(result.Summary))
+		if rawURL == "" {
+			result.Err = fmt.Errorf("item %v has no detail url", i)
+			return
+		}
+		ref, err := url.Parse(rawURL)
+		if err != nil {
+			result.Err = fmt.Errorf("item %v detail url %v is invalid: %w", i, rawURL, err)
+			return
+		}
+		if listDoc.Url != nil {
+			ref = listDoc.Url.ResolveReference(ref)
+		}
+		result.URL = ref.String()
+	})
+	return results
+}
+
+// fetchPage fetch the page of url with the timeout of ro
+func (p *Pagser) fetchPage(ro *requestOptions, pageURL string) (*goquery.Document, error) {
+	ctx := ro.ctx
+	if ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+	}
+	return p.fetchDocument(ctx, ro, pageURL)
+}
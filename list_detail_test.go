@@ -0,0 +1,101 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type listSummary struct {
+	Name string `pagser:"a"`
+	Link string `pagser:"a->attr(href)"`
+}
+
+type listDetail struct {
+	Title string  `pagser:"h1"`
+	Price float64 `pagser:".price"`
+}
+
+func TestParseListDetail(t *testing.T) {
+	var running, maxRunning int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/list" {
+			_, _ = w.Write([]byte(`<ul>
+<li><a href="detail/1">One</a></li>
+<li><a href="/detail/2">Two</a></li>
+<li><a href="/missing">Missing</a></li>
+<li><a>No link</a></li>
+<li><a href="/detail/5">Five</a></li>
+</ul>`))
+			return
+		}
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		if !strings.HasPrefix(r.URL.Path, "/detail/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/detail/")
+		_, _ = w.Write([]byte(fmt.Sprintf(`<h1>Detail %v</h1><span class="price">%v.5</span>`, id, id)))
+	}))
+	defer server.Close()
+
+	p := New()
+	results, err := ParseListDetail[listSummary, listDetail](p, server.URL+"/list", "li",
+		func(item listSummary) string { return item.Link }, WithDetailConcurrency(2))
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	require.True(t, atomic.LoadInt32(&maxRunning) <= 2, "max running %v", atomic.LoadInt32(&maxRunning))
+
+	require.NoError(t, results[0].Err)
+	require.Equal(t, listSummary{Name: "One", Link: "detail/1"}, results[0].Summary)
+	require.Equal(t, server.URL+"/detail/1", results[0].URL)
+	require.Equal(t, listDetail{Title: "Detail 1", Price: 1.5}, results[0].Detail)
+	require.NoError(t, results[1].Err)
+	require.Equal(t, listDetail{Title: "Detail 2", Price: 2.5}, results[1].Detail)
+	require.Error(t, results[2].Err)
+	require.Contains(t, results[2].Err.Error(), "detail page "+server.URL+"/missing error")
+	require.EqualError(t, results[3].Err, "item 3 has no detail url")
+	require.NoError(t, results[4].Err)
+	require.Equal(t, "Detail 5", results[4].Detail.Title)
+}
+
+func TestParseListDetail_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/list" {
+			_, _ = w.Write([]byte(`<ul><li><a href="/detail">One</a></li></ul>`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := New()
+	linkOf := func(item listSummary) string { return item.Link }
+	_, err := ParseListDetail[listSummary, listDetail](p, server.URL+"/missing", "li", linkOf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+
+	_, err = ParseListDetail[listSummary, listDetail](p, server.URL+"/list", "li[", linkOf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "item selector `li[` is invalid")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ParseListDetail[listSummary, listDetail](p, server.URL+"/list", "li", linkOf, WithContext(ctx))
+	require.Error(t, err)
+}
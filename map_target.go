@@ -0,0 +1,103 @@
+package pagser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// isMapTarget reports whether the type is map[string]string or map[string]interface{} which can be parsed by the tags
+// of its values
+func isMapTarget(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+	elemType := t.Elem()
+	return elemType.Kind() == reflect.String || (elemType.Kind() == reflect.Interface && elemType.NumMethod() == 0)
+}
+
+// doParseMapTarget parses the selection to the map, the values of map are the struct tags which are replaced by
+// the parsed values, eg: map[string]string{"title": "h1", "link": "a->attr(href)"}, the values of
+// map[string]interface{} are the outputs of functions, or the text if the tag has no function.
+func (p *Pagser) doParseMapTarget(ctx context.Context, m reflect.Value, selection *goquery.Selection) error {
+	keys := make([]string, 0, m.Len())
+	tagValues := make(map[string]string, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		value := iter.Value()
+		if value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+		if !value.IsValid() || value.Kind() != reflect.String {
+			return fmt.Errorf("the value of key %v is %v, not the tag string", key, iter.Value().Interface())
+		}
+		tagValue := value.String()
+		keys = append(keys, key)
+		tagValues[key] = tagValue
+	}
+	sort.Strings(keys)
+
+	// the struct type with the fields of keys in order, structs of the same keys and tags share the compiled plan
+	structFields := make([]reflect.StructField, len(keys))
+	names := make(map[string]string, len(keys))
+	for i, key := range keys {
+		tag, err := p.loadTag(tagValues[key])
+		if err != nil {
+			return fmt.Errorf("key %v %w", key, err)
+		}
+		if tag.Computed != nil {
+			return fmt.Errorf("key %v tag=`%v` computed field is not supported by map", key, tagValues[key])
+		}
+		fieldType := m.Type().Elem()
+		if len(tag.Funcs) == 0 {
+			fieldType = reflect.TypeOf("")
+		}
+		name := fmt.Sprintf("F%d", i)
+		names[name] = key
+		structFields[i] = reflect.StructField{
+			Name: name,
+			Type: fieldType,
+			Tag:  reflect.StructTag(fmt.Sprintf("%v:%q", p.Config.TagName, tagValues[key])),
+		}
+	}
+	val := reflect.New(reflect.StructOf(structFields))
+	if err := p.doParse(ctx, val, nil, selection); err != nil {
+		return renameFieldPath(err, names)
+	}
+	for i, key := range keys {
+		m.SetMapIndex(reflect.ValueOf(key).Convert(m.Type().Key()), val.Elem().Field(i).Convert(m.Type().Elem()))
+	}
+	return nil
+}
+
+// renameFieldPath returns the error with the first name of field paths of ParseError renamed, eg: `F0` -> `title`
+func renameFieldPath(err error, names map[string]string) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		renamed := make([]error, len(errs))
+		for i, e := range errs {
+			renamed[i] = renameFieldPath(e, names)
+		}
+		return errors.Join(renamed...)
+	}
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		return err
+	}
+	name, rest := parseErr.Path, ""
+	if pos := strings.IndexAny(name, ".["); pos >= 0 {
+		name, rest = name[:pos], name[pos:]
+	}
+	if key, ok := names[name]; ok {
+		renamed := *parseErr
+		renamed.Path = key + rest
+		return &renamed
+	}
+	return err
+}
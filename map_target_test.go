@@ -0,0 +1,47 @@
+package pagser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_MapTarget(t *testing.T) {
+	const html = `<h1>Title</h1><a href="/a">A</a><a href="/b">B</a>`
+	p := New()
+
+	texts := map[string]string{"title": "h1", "link": "a->attr(href)", "missing": ".missing,default=none"}
+	require.NoError(t, p.Parse(&texts, html))
+	require.Equal(t, map[string]string{"title": "Title", "link": "/a", "missing": "none"}, texts)
+
+	values := map[string]interface{}{"title": "h1", "links": "a->eachAttr(href)", "count": "a->size()"}
+	require.NoError(t, p.Parse(&values, html))
+	require.Equal(t, map[string]interface{}{"title": "Title", "links": []string{"/a", "/b"}, "count": 2}, values)
+
+	// the map in interface
+	var v interface{} = map[string]string{"title": "h1"}
+	require.NoError(t, p.Parse(&v, html))
+	require.Equal(t, map[string]string{"title": "Title"}, v)
+
+	required := map[string]string{"title": "h1", "price": ".price,required"}
+	err := p.Parse(&required, html)
+	require.Error(t, err)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "price", parseErr.Path)
+
+	collect, err := NewWithConfig(Config{TagName: "pagser", FuncSymbol: "->", CollectErrors: true})
+	require.NoError(t, err)
+	required = map[string]string{"price": ".price,required", "stock": ".stock,required"}
+	err = collect.Parse(&required, html)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "field price")
+	require.Contains(t, err.Error(), "field stock")
+
+	var nilMap map[string]string
+	require.Error(t, p.Parse(&nilMap, html))
+	require.Error(t, p.Parse(&map[string]interface{}{"title": 1}, html))
+	require.Error(t, p.Parse(&map[string]string{"total": "=Price * 2"}, html))
+	require.Error(t, p.Parse(&map[string]int{"title": 1}, html))
+}
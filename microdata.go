@@ -0,0 +1,96 @@
+package pagser
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// microdataItems returns the top level microdata items of `itemscope` elements in the elements, each item is a map of
+// `@type` (`itemtype`), `@id` (`itemid`) and the `itemprop` properties, the value of property is string or nested item,
+// and the multiple values of same property are in an array.
+func microdataItems(node *goquery.Selection) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0)
+	node.Filter("[itemscope]").AddSelection(node.Find("[itemscope]")).Each(func(i int, scope *goquery.Selection) {
+		if _, ok := scope.Attr("itemprop"); ok {
+			return
+		}
+		items = append(items, microdataItem(scope))
+	})
+	return items
+}
+
+// microdataItem returns the item of the `itemscope` element
+func microdataItem(scope *goquery.Selection) map[string]interface{} {
+	item := make(map[string]interface{})
+	if types := strings.Fields(scope.AttrOr("itemtype", "")); len(types) == 1 {
+		item["@type"] = types[0]
+	} else if len(types) > 1 {
+		values := make([]interface{}, len(types))
+		for i, t := range types {
+			values[i] = t
+		}
+		item["@type"] = values
+	}
+	if id, ok := scope.Attr("itemid"); ok {
+		item["@id"] = strings.TrimSpace(id)
+	}
+	scopeNode := scope.Get(0)
+	scope.Find("[itemprop]").Each(func(i int, prop *goquery.Selection) {
+		if microdataScope(prop.Get(0)) != scopeNode {
+			return
+		}
+		var value interface{}
+		if _, ok := prop.Attr("itemscope"); ok {
+			value = microdataItem(prop)
+		} else {
+			value = microdataValue(prop)
+		}
+		for _, name := range strings.Fields(prop.AttrOr("itemprop", "")) {
+			switch exists := item[name].(type) {
+			case nil:
+				item[name] = value
+			case []interface{}:
+				item[name] = append(exists, value)
+			default:
+				item[name] = []interface{}{exists, value}
+			}
+		}
+	})
+	return item
+}
+
+// microdataScope returns the nearest `itemscope` ancestor of the property element
+func microdataScope(n *html.Node) *html.Node {
+	for parent := n.Parent; parent != nil; parent = parent.Parent {
+		for _, attr := range parent.Attr {
+			if attr.Key == "itemscope" {
+				return parent
+			}
+		}
+	}
+	return nil
+}
+
+// microdataValue returns the property value of element by the element name, eg: `content` of meta, `src` of img
+func microdataValue(prop *goquery.Selection) string {
+	var value string
+	switch goquery.NodeName(prop) {
+	case "meta":
+		value = prop.AttrOr("content", "")
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		value = prop.AttrOr("src", "")
+	case "a", "area", "link":
+		value = prop.AttrOr("href", "")
+	case "object":
+		value = prop.AttrOr("data", "")
+	case "data", "meter":
+		value = prop.AttrOr("value", "")
+	case "time":
+		value = prop.AttrOr("datetime", prop.Text())
+	default:
+		value = prop.Text()
+	}
+	return strings.TrimSpace(value)
+}
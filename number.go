@@ -0,0 +1,136 @@
+package pagser
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rxNumber matches the first number in text with the group and decimal separators, eg: `-1,234.56`, `1 234,56`
+var rxNumber = regexp.MustCompile(`[-+]?\d(?:[\d.,'\x{00a0}\x{202f} ]*\d)?`)
+
+// rxNumberSuffix matches the abbreviation suffix after number, eg: `k` of `1.2k`, `mil` of `3 mil`
+var rxNumberSuffix = regexp.MustCompile(`^\s*([a-zA-Z]+\b|[千万萬亿億])`)
+
+// numberSuffixes the multipliers of abbreviation suffixes in lower case
+var numberSuffixes = map[string]float64{
+	"k": 1e3, "thousand": 1e3, "千": 1e3,
+	"m": 1e6, "mm": 1e6, "mn": 1e6, "mil": 1e6, "mio": 1e6, "million": 1e6, "millions": 1e6,
+	"b": 1e9, "bn": 1e9, "bil": 1e9, "billion": 1e9, "billions": 1e9,
+	"t": 1e12, "tn": 1e12, "trillion": 1e12, "trillions": 1e12,
+	"万": 1e4, "萬": 1e4, "亿": 1e8, "億": 1e8,
+}
+
+// decimalCommaLanguages the languages use comma as decimal separator
+var decimalCommaLanguages = map[string]bool{
+	"af": true, "az": true, "be": true, "bg": true, "bs": true, "ca": true, "cs": true, "da": true, "de": true,
+	"el": true, "es": true, "et": true, "eu": true, "fi": true, "fr": true, "gl": true, "hr": true, "hu": true,
+	"hy": true, "id": true, "is": true, "it": true, "ka": true, "kk": true, "lt": true, "lv": true, "mk": true,
+	"nb": true, "nl": true, "nn": true, "no": true, "pl": true, "pt": true, "ro": true, "ru": true, "sk": true,
+	"sl": true, "sq": true, "sr": true, "sv": true, "tr": true, "uk": true, "uz": true, "vi": true,
+}
+
+// decimalDotLocales the locales use dot as decimal separator though the languages use comma
+var decimalDotLocales = map[string]bool{
+	"de-ch": true, "de-li": true, "it-ch": true, "es-mx": true, "es-us": true, "es-pr": true,
+}
+
+// decimalSeparator returns the decimal separator of locale, eg: `en-US` -> `.`, `de_DE` -> `,`,
+// the locale can also be the separator itself, it returns 0 for the empty or `auto` locale to detect it from text.
+func decimalSeparator(locale string) (byte, error) {
+	locale = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+	switch locale {
+	case "", "auto":
+		return 0, nil
+	case ".", ",":
+		return locale[0], nil
+	}
+	lang, _, _ := strings.Cut(locale, "-")
+	if len(lang) < 2 || len(lang) > 3 {
+		return 0, fmt.Errorf("invalid locale `%v`", locale)
+	}
+	if decimalCommaLanguages[lang] && !decimalDotLocales[locale] {
+		return ',', nil
+	}
+	return '.', nil
+}
+
+// parseLocaleNumber parses the first number in text by the decimal separator of locale, the group separators such as
+// commas, dots, spaces and apostrophes are removed, eg: `1.234,56` (de), `1,234.56` (en), `1 234,56` (fr).
+func parseLocaleNumber(text string, locale string) (float64, error) {
+	decimal, err := decimalSeparator(locale)
+	if err != nil {
+		return 0, err
+	}
+	value := rxNumber.FindString(text)
+	if value == "" {
+		return 0, fmt.Errorf("no number found in `%v`", text)
+	}
+	value = strings.NewReplacer(" ", "", "'", "", "\u00a0", "", "\u202f", "").Replace(value)
+	if decimal == 0 {
+		decimal = detectDecimalSeparator(value)
+	}
+	group := ","
+	if decimal == ',' {
+		group = "."
+	}
+	value = strings.ReplaceAll(value, group, "")
+	value = strings.Replace(value, string(decimal), ".", 1)
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number `%v`", text)
+	}
+	return number, nil
+}
+
+// detectDecimalSeparator detects the decimal separator of number, the last one is decimal if there are both commas and
+// dots, the repeated separator or the single separator followed by three digits is group separator.
+func detectDecimalSeparator(value string) byte {
+	lastDot := strings.LastIndex(value, ".")
+	lastComma := strings.LastIndex(value, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			return ','
+		}
+		return '.'
+	case lastComma >= 0:
+		if strings.Count(value, ",") == 1 && !isGroupedThousands(value, lastComma) {
+			return ','
+		}
+		return '.'
+	default:
+		if strings.Count(value, ".") > 1 || (lastDot >= 0 && isGroupedThousands(value, lastDot)) {
+			return ','
+		}
+		return '.'
+	}
+}
+
+// isGroupedThousands returns true if the single separator looks like a group separator, eg: `1,234`, but not `0.125`
+func isGroupedThousands(value string, pos int) bool {
+	integer := strings.TrimLeft(value[:pos], "-+")
+	return len(value)-pos-1 == 3 && integer != "0" && integer != ""
+}
+
+// parseHumanNumber parses the first number in text with the abbreviation suffix, such as `1.2k`, `3.4M`, `5 mil`,
+// `2.5 billion`, `1,2万`, the number without suffix is parsed as is, and the decimal separator is detected by locale.
+func parseHumanNumber(text string, locale string) (float64, error) {
+	loc := rxNumber.FindStringIndex(text)
+	if loc == nil {
+		return 0, fmt.Errorf("no number found in `%v`", text)
+	}
+	number, err := parseLocaleNumber(text[loc[0]:loc[1]], locale)
+	if err != nil {
+		return 0, err
+	}
+	if matches := rxNumberSuffix.FindStringSubmatch(text[loc[1]:]); matches != nil {
+		if multiplier, ok := numberSuffixes[strings.ToLower(matches[1])]; ok {
+			// round the float error of multiplication, such as 3.4 * 1e6
+			return math.Round(number * multiplier), nil
+		}
+	}
+	return number, nil
+}
@@ -0,0 +1,71 @@
+package pagser
+
+import (
+	"testing"
+)
+
+func TestParseLocaleNumber(t *testing.T) {
+	tests := []struct {
+		text   string
+		locale string
+		want   float64
+	}{
+		{"1,234.56", "en", 1234.56},
+		{"1.234,56", "de", 1234.56},
+		{"1 234,56", "fr", 1234.56},
+		{"1 234,56 €", "fr-FR", 1234.56},
+		{"1'234.56", "de_CH", 1234.56},
+		{"-12,5", ",", -12.5},
+		{"Price: $1,299", "en-US", 1299},
+		{"1.234", "de", 1234},
+		{"1.234,56", "", 1234.56},
+		{"1,234.56", "auto", 1234.56},
+		{"1,234", "", 1234},
+		{"1,5", "", 1.5},
+		{"0.125", "", 0.125},
+		{"1.234.567", "", 1234567},
+		{"42", "", 42},
+	}
+	for _, tt := range tests {
+		got, err := parseLocaleNumber(tt.text, tt.locale)
+		if err != nil {
+			t.Fatalf("parse `%v` (%v) error: %v", tt.text, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parse `%v` (%v) want %v, but got %v", tt.text, tt.locale, tt.want, got)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "1.2.3,4,5"} {
+		if _, err := parseLocaleNumber(text, ""); err == nil {
+			t.Fatalf("parse `%v` want an error", text)
+		}
+	}
+}
+
+func TestParseHumanNumber(t *testing.T) {
+	tests := []struct {
+		text   string
+		locale string
+		want   float64
+	}{
+		{"1.2k", "", 1200},
+		{"3.4M views", "", 3400000},
+		{"5 mil", "", 5000000},
+		{"2.5 billion", "", 2500000000},
+		{"1,2 Mio.", "de", 1200000},
+		{"1.5万", "", 15000},
+		{"1,234 votes", "", 1234},
+		{"12 months", "", 12},
+		{"0.5", "", 0.5},
+	}
+	for _, tt := range tests {
+		got, err := parseHumanNumber(tt.text, tt.locale)
+		if err != nil {
+			t.Fatalf("parse `%v` (%v) error: %v", tt.text, tt.locale, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parse `%v` (%v) want %v, but got %v", tt.text, tt.locale, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package pagser
+
+// OpenGraph the OpenGraph and Twitter Card metadata of page, parse it from `head` or the whole document.
+// The preset fields use the default `pagser` tag name and `->` function symbol.
+//	type PageData struct {
+//		OG pagser.OpenGraph `pagser:"head"`
+//	}
+type OpenGraph struct {
+	Title       string `pagser:"->og(og:title)"`
+	Type        string `pagser:"->og(og:type)"`
+	URL         string `pagser:"->og(og:url)"`
+	Image       string `pagser:"->og(og:image)"`
+	ImageAlt    string `pagser:"->og(og:image:alt)"`
+	Description string `pagser:"->og(og:description)"`
+	SiteName    string `pagser:"->og(og:site_name)"`
+	Locale      string `pagser:"->og(og:locale)"`
+	Video       string `pagser:"->og(og:video)"`
+	Audio       string `pagser:"->og(og:audio)"`
+
+	TwitterCard        string `pagser:"->og(twitter:card)"`
+	TwitterSite        string `pagser:"->og(twitter:site)"`
+	TwitterCreator     string `pagser:"->og(twitter:creator)"`
+	TwitterTitle       string `pagser:"->og(twitter:title)"`
+	TwitterDescription string `pagser:"->og(twitter:description)"`
+	TwitterImage       string `pagser:"->og(twitter:image)"`
+}
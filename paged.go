@@ -0,0 +1,123 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// NextPageFunc returns the url of the next page of page document, empty if it is the last page,
+// the relative url is resolved against the url of page, see ParsePaged
+type NextPageFunc func(page *goquery.Document) (string, error)
+
+// NextRel returns the NextPageFunc of the `rel=next` link of page, `<link rel="next">` or `<a rel="next">`
+func NextRel() NextPageFunc {
+	return NextSelector(`link[rel~="next"][href], a[rel~="next"][href]`)
+}
+
+// NextSelector returns the NextPageFunc of the `href` attribute of the first element matched by the selector,
+// eg: `.pagination a.next`
+func NextSelector(selector string) NextPageFunc {
+	matcher, err := cascadia.Compile(selector)
+	return func(page *goquery.Document) (string, error) {
+		if err != nil {
+			return "", fmt.Errorf("next page selector `%v` is invalid: %w", selector, err)
+		}
+		return strings.TrimSpace(page.FindMatcher(matcher).First().AttrOr("href", "")), nil
+	}
+}
+
+// WithMaxPages set the max pages fetched by ParsePaged, default is 0 that is unlimited
+func WithMaxPages(maxPages int) RequestOption {
+	return func(opts *requestOptions) {
+		opts.maxPages = maxPages
+	}
+}
+
+// ParsePaged fetch the pages from startURL and parse them to struct, the url of next page is got by next until it
+// returns empty, the page is visited before or the max pages of WithMaxPages is reached. The slice fields of struct
+// are accumulated across the pages, the other fields are parsed from the first page. The timeout of WithTimeout is
+// applied to each page.
+//	var data ListData
+//	err := p.ParsePaged(&data, "https://example.com/list", pagser.NextRel(), pagser.WithMaxPages(10))
+func (p *Pagser) ParsePaged(v interface{}, startURL string, next NextPageFunc, opts ...RequestOption) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a pointer to struct", reflect.TypeOf(v))
+	}
+	ro := newRequestOptions(opts...)
+
+	visited := make(map[string]bool)
+	pageURL := startURL
+	for page := 0; pageURL != "" && (ro.maxPages <= 0 || page < ro.maxPages); page++ {
+		if visited[pageURL] {
+			break
+		}
+		visited[pageURL] = true
+
+		nextURL, err := p.parsePage(val, page, pageURL, next, ro)
+		if err != nil {
+			return err
+		}
+		pageURL = nextURL
+	}
+	return nil
+}
+
+// parsePage fetch and parse the page of url to val, the slice fields of the page except the first page are appended
+// to val, return the absolute url of next page
+func (p *Pagser) parsePage(val reflect.Value, page int, pageURL string, next NextPageFunc,
+	ro *requestOptions) (string, error) {
+	ctx := ro.ctx
+	if ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+	}
+
+	doc, err := p.fetchDocument(ctx, ro, pageURL)
+	if err != nil {
+		return "", err
+	}
+	if page == 0 {
+		err = p.ParseDocumentContext(ctx, val.Interface(), doc)
+	} else {
+		pageVal := reflect.New(val.Elem().Type())
+		if err = p.ParseDocumentContext(ctx, pageVal.Interface(), doc); err == nil {
+			appendSliceFields(val.Elem(), pageVal.Elem())
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("page %v error: %w", pageURL, err)
+	}
+
+	nextURL, err := next(doc)
+	if err != nil || nextURL == "" {
+		return "", err
+	}
+	nextRef, err := url.Parse(nextURL)
+	if err != nil {
+		return "", fmt.Errorf("next page url %v of page %v is invalid: %w", nextURL, pageURL, err)
+	}
+	if doc.Url != nil {
+		nextRef = doc.Url.ResolveReference(nextRef)
+	}
+	// the fragment refers to the same page
+	nextRef.Fragment = ""
+	return nextRef.String(), nil
+}
+
+// appendSliceFields appends the exported slice fields of src struct to the fields of dst struct
+func appendSliceFields(dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		if !dst.Type().Field(i).IsExported() || dst.Field(i).Kind() != reflect.Slice {
+			continue
+		}
+		dst.Field(i).Set(reflect.AppendSlice(dst.Field(i), src.Field(i)))
+	}
+}
@@ -0,0 +1,92 @@
+package pagser
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pagedData struct {
+	Title string   `pagser:"h1"`
+	Items []string `pagser:"li"`
+	Links []string `pagser:"a.item->eachAttr(href)"`
+	Page  int      `pagser:"h1->attr(data-page)"`
+}
+
+func newPagedServer(t *testing.T, pages int, next func(page int) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+		html := fmt.Sprintf(`<h1 data-page="%v">Page %v</h1><ul><li>item %v.1</li><li>item %v.2</li></ul>`, page, page, page, page)
+		if page < pages {
+			html += next(page)
+		}
+		_, _ = w.Write([]byte(html))
+	}))
+}
+
+func TestPagser_ParsePaged(t *testing.T) {
+	server := newPagedServer(t, 3, func(page int) string {
+		return fmt.Sprintf(`<link rel="next" href="/list?page=%v">`, page+1)
+	})
+	defer server.Close()
+
+	p := New()
+	var data pagedData
+	require.NoError(t, p.ParsePaged(&data, server.URL+"/list?page=1", NextRel()))
+	require.Equal(t, "Page 1", data.Title)
+	require.Equal(t, 1, data.Page)
+	require.Equal(t, []string{"item 1.1", "item 1.2", "item 2.1", "item 2.2", "item 3.1", "item 3.2"}, data.Items)
+	require.Empty(t, data.Links)
+
+	// the max pages
+	data = pagedData{}
+	require.NoError(t, p.ParsePaged(&data, server.URL+"/list?page=1", NextRel(), WithMaxPages(2)))
+	require.Equal(t, []string{"item 1.1", "item 1.2", "item 2.1", "item 2.2"}, data.Items)
+}
+
+func TestPagser_ParsePaged_Selector(t *testing.T) {
+	server := newPagedServer(t, 100, func(page int) string {
+		// the pages link to the first page after page 2
+		if page >= 2 {
+			return `<div class="pager"><a class="next" href="list?page=1#top">Next</a></div>`
+		}
+		return fmt.Sprintf(`<div class="pager"><a class="next" href="list?page=%v">Next</a></div>`, page+1)
+	})
+	defer server.Close()
+
+	p := New()
+	var data pagedData
+	require.NoError(t, p.ParsePaged(&data, server.URL+"/list?page=1", NextSelector(".pager a.next")))
+	require.Equal(t, []string{"item 1.1", "item 1.2", "item 2.1", "item 2.2"}, data.Items)
+
+	err := p.ParsePaged(&data, server.URL+"/list?page=1", NextSelector("a["))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "next page selector `a[` is invalid")
+}
+
+func TestPagser_ParsePaged_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`<h1>Title</h1><a rel="next" href="/missing">Next</a>`))
+	}))
+	defer server.Close()
+
+	p := New()
+	var data pagedData
+	err := p.ParsePaged(&data, server.URL, NextRel())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+	require.Equal(t, "Title", data.Title)
+
+	var items []string
+	err = p.ParsePaged(&items, server.URL, NextRel())
+	require.EqualError(t, err, "*[]string is not a pointer to struct")
+}
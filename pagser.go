@@ -10,6 +10,8 @@ package pagser
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"sync"
 )
 
@@ -18,8 +20,17 @@ type Pagser struct {
 	Config Config
 	//mapTags  map[string]*tagTokenizer // tag value => tagTokenizer
 	mapTags sync.Map //map[string]*tagTokenizer
-	//mapFuncs map[string]CallFunc      // name => func
+	//mapFuncs map[string]CallFunc      // name => func, or baseUrlFunc of the builtin functions resolving urls
 	mapFuncs sync.Map //map[string]CallFunc
+	//mapConverters map[reflect.Type]ConvertFunc // type => converter
+	mapConverters sync.Map //map[reflect.Type]ConvertFunc
+	//mapOrders map[reflect.Type][]int // struct type => field indexes in parse order
+	mapOrders sync.Map //map[reflect.Type][]int
+	//mapPlans map[reflect.Type]*structPlan // struct type => compiled fields
+	mapPlans sync.Map //map[reflect.Type]*structPlan
+	//mapImpls map[reflect.Type][]*implementation // interface type => registered implementations
+	mapImpls sync.Map //map[reflect.Type][]*implementation
+	implsMu  sync.Mutex
 }
 
 // New create pagser client
@@ -36,6 +47,11 @@ func NewWithConfig(cfg Config) (*Pagser, error) {
 	if cfg.FuncSymbol == "" {
 		return nil, errors.New("FuncSymbol must not empty")
 	}
+	if cfg.BaseURL != "" {
+		if _, err := url.Parse(cfg.BaseURL); err != nil {
+			return nil, fmt.Errorf("BaseURL is invalid: %v", err)
+		}
+	}
 	p := Pagser{
 		Config: cfg,
 		//mapTags:  make(map[string]*tagTokenizer, 0),
@@ -44,5 +60,8 @@ func NewWithConfig(cfg Config) (*Pagser, error) {
 	for k, v := range builtinFuncs {
 		p.mapFuncs.Store(k, v)
 	}
+	// builtin functions resolve urls against the base url of the parse
+	p.mapFuncs.Store("absHref", baseUrlFunc(absHref))
+	p.mapFuncs.Store("links", baseUrlFunc(links))
 	return &p, nil
 }
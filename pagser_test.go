@@ -109,3 +109,14 @@ func TestNewWithConfigFuncSymbolError(t *testing.T) {
 		t.Fatal("Result must return error")
 	}
 }
+
+func TestNewWithConfigBaseURLError(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BaseURL = "http://a b.com/"
+	_, err := NewWithConfig(cfg)
+	if err != nil {
+		t.Log(err)
+	} else {
+		t.Fatal("Result must return error")
+	}
+}
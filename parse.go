@@ -1,40 +1,85 @@
 package pagser
 
 import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/spf13/cast"
+	"golang.org/x/net/html"
 )
 
-// Parse parse html to struct
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
+	selectionType       = reflect.TypeOf((*goquery.Selection)(nil))
+	htmlNodeType        = reflect.TypeOf((*html.Node)(nil))
+	htmlNodeSliceType   = reflect.TypeOf([]*html.Node{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Parse parse html to struct, or to the map[string]string or map[string]interface{} of tags, eg:
+//	m := map[string]interface{}{"title": "h1", "links": "a->eachAttr(href)"}
+//	err := p.Parse(&m, html)
 func (p *Pagser) Parse(v interface{}, document string) error {
+	return p.ParseContext(context.Background(), v, document)
+}
+
+// ParseContext parse html to struct, parse will be aborted when the ctx is done
+func (p *Pagser) ParseContext(ctx context.Context, v interface{}, document string) error {
 	reader, err := goquery.NewDocumentFromReader(strings.NewReader(document))
 	if err != nil {
 		return err
 	}
-	return p.ParseDocument(v, reader)
+	return p.ParseDocumentContext(ctx, v, reader)
 }
 
 // ParseReader parse html to struct
 func (p *Pagser) ParseReader(v interface{}, reader io.Reader) error {
-	doc, err := goquery.NewDocumentFromReader(reader)
+	return p.ParseReaderContext(context.Background(), v, reader)
+}
+
+// ParseReaderContext parse html to struct, parse will be aborted when the ctx is done.
+// The html is decoded to UTF-8 by the BOM or `<meta charset>`, eg: the GBK, Shift-JIS or Windows-1251 pages.
+func (p *Pagser) ParseReaderContext(ctx context.Context, v interface{}, reader io.Reader) error {
+	doc, err := p.newDocumentFromReader(reader, "")
 	if err != nil {
 		return err
 	}
-	return p.ParseDocument(v, doc)
+	return p.ParseDocumentContext(ctx, v, doc)
 }
 
 // ParseDocument parse document to struct
 func (p *Pagser) ParseDocument(v interface{}, document *goquery.Document) error {
-	return p.ParseSelection(v, document.Selection)
+	return p.ParseDocumentContext(context.Background(), v, document)
+}
+
+// ParseDocumentContext parse document to struct, parse will be aborted when the ctx is done.
+// The relative urls of url.URL fields, absHref() and links() are resolved against the `Url` of document if it is set.
+func (p *Pagser) ParseDocumentContext(ctx context.Context, v interface{}, document *goquery.Document) error {
+	return p.ParseSelectionContext(withDocumentUrl(ctx, document), v, document.Selection)
 }
 
 // ParseSelection parse selection to struct
 func (p *Pagser) ParseSelection(v interface{}, selection *goquery.Selection) error {
+	return p.ParseSelectionContext(context.Background(), v, selection)
+}
+
+// ParseSelectionContext parse selection to struct, parse will be aborted when the ctx is done.
+// The ctx is checked between struct fields and slice items, the error of ctx is returned if it is done.
+func (p *Pagser) ParseSelectionContext(ctx context.Context, v interface{}, selection *goquery.Selection) error {
 	val := reflect.ValueOf(v)
 
 	// Check value is a pointer
@@ -47,30 +92,75 @@ func (p *Pagser) ParseSelection(v interface{}, selection *goquery.Selection) err
 		return fmt.Errorf("%v is nil", val.Type())
 	}
 
-	// Check underlying type is a struct
+	// Check underlying type is a struct, or a map of tags
 	elem := val.Elem()
 	if elem.Kind() == reflect.Interface {
 		elem = elem.Elem()
 	}
+	// The base url is resolved once for all fields and items of the parse
+	ctx = p.withParseBase(ctx, selection)
+	if isMapTarget(elem.Type()) {
+		if elem.IsNil() {
+			return fmt.Errorf("%v is nil", elem.Type())
+		}
+		return p.withParseTimeout(ctx, elem, func(ctx context.Context, target reflect.Value) error {
+			return p.doParseMapTarget(ctx, target, selection)
+		})
+	}
 	if elem.Kind() != reflect.Struct {
 		return fmt.Errorf("%v is not a struct", elem.Type())
 	}
 
 	// Parse into pointer value
-	return p.doParse(val, nil, selection)
+	return p.withParseTimeout(ctx, val, func(ctx context.Context, target reflect.Value) error {
+		return p.doParse(ctx, target, nil, selection)
+	})
 }
 
 // ParseSelection parse selection to struct
-func (p *Pagser) doParse(val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+func (p *Pagser) doParse(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+	// Set the matched nodes to *goquery.Selection, *html.Node or []*html.Node for post-processing of callers
+	switch val.Type() {
+	case selectionType:
+		val.Set(reflect.ValueOf(selection))
+		return nil
+	case htmlNodeType:
+		if len(selection.Nodes) > 0 {
+			val.Set(reflect.ValueOf(selection.Nodes[0]))
+		}
+		return nil
+	case htmlNodeSliceType:
+		nodes := make([]*html.Node, len(selection.Nodes))
+		copy(nodes, selection.Nodes)
+		val.Set(reflect.ValueOf(nodes))
+		return nil
+	}
+
+	// The value parses the selection itself if it implements Unmarshaler
+	if unmarshaler, ok := findUnmarshaler(val); ok {
+		if err := unmarshaler.UnmarshalPagser(selection, p); err != nil {
+			return fmt.Errorf("%v unmarshal error: %w", val.Type(), err)
+		}
+		return nil
+	}
+
 	switch val.Kind() {
 	case reflect.Interface:
-		return p.doParseInterface(val, stackValues, selection)
+		// The nil interface is set to the registered implementation which matches the selection
+		if val.IsNil() {
+			if ok, err := p.doParseImplementation(ctx, val, stackValues, selection); ok {
+				return err
+			}
+		}
+		return p.doParseInterface(ctx, val, stackValues, selection)
 	case reflect.Pointer:
-		return p.doParsePointer(val, stackValues, selection)
+		return p.doParsePointer(ctx, val, stackValues, selection)
 	case reflect.Struct:
-		return p.doParseStruct(val, stackValues, selection)
+		return p.doParseStruct(ctx, val, stackValues, selection)
 	case reflect.Slice:
-		return p.doParseSlice(val, stackValues, selection)
+		return p.doParseSlice(ctx, val, stackValues, selection)
+	case reflect.Map:
+		return p.setFieldValue(val, nodeAttrs(selection), fieldOptions{Base: parseBaseOf(ctx)})
 	default:
 		// UnsafePointer
 		// Complex64
@@ -78,13 +168,11 @@ func (p *Pagser) doParse(val reflect.Value, stackValues []reflect.Value, selecti
 		// Array
 		// Chan
 		// Func
-		val.SetString(strings.TrimSpace(selection.Text()))
+		return p.setFieldValue(val, strings.TrimSpace(selection.Text()), fieldOptions{Base: parseBaseOf(ctx)})
 	}
-
-	return nil
 }
 
-func (p *Pagser) doParsePointer(val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+func (p *Pagser) doParsePointer(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
 	// If the pointer value is nil, create a new non-nil pointer to the underlying type
 	if val.IsNil() {
 		underlyingType := val.Type().Elem()
@@ -94,7 +182,7 @@ func (p *Pagser) doParsePointer(val reflect.Value, stackValues []reflect.Value,
 
 	// Parse into underlying value
 	underlyingValue := reflect.Indirect(val)
-	err := p.doParse(underlyingValue, stackValues, selection)
+	err := p.doParse(ctx, underlyingValue, stackValues, selection)
 	if err != nil {
 		return err
 	}
@@ -102,7 +190,85 @@ func (p *Pagser) doParsePointer(val reflect.Value, stackValues []reflect.Value,
 	return nil
 }
 
-func (p *Pagser) doParseInterface(val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+// fieldPathKey context key of the path of current field, eg: `Items[0].Name`
+type fieldPathKey struct{}
+
+// fieldPathCtx the context of current field, the path is built from the parent fields only when it is needed,
+// eg: the errors, Report and Config.OnField, so each field allocates only the context
+type fieldPathCtx struct {
+	context.Context
+	parent *fieldPathCtx // nil for the fields of root value
+	name   string        // the field name or the map key
+	index  int           // the slice index, -1 for the field and the map item
+	key    bool          // the name is the map key
+}
+
+// Value returns the context itself for fieldPathKey, otherwise the value of parent context
+func (c *fieldPathCtx) Value(key interface{}) interface{} {
+	if key == (fieldPathKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// String returns the path of field, empty for nil
+func (c *fieldPathCtx) String() string {
+	if c == nil {
+		return ""
+	}
+	switch parent := c.parent.String(); {
+	case c.index >= 0:
+		return parent + "[" + strconv.Itoa(c.index) + "]"
+	case c.key:
+		return parent + "[" + c.name + "]"
+	default:
+		return joinFieldPath(parent, c.name)
+	}
+}
+
+// withFieldName returns the context of the field of current value
+func withFieldName(ctx context.Context, name string) context.Context {
+	return &fieldPathCtx{Context: ctx, parent: fieldPathOf(ctx), name: name, index: -1}
+}
+
+// withFieldIndex returns the context of the slice item of current field
+func withFieldIndex(ctx context.Context, index int) context.Context {
+	return &fieldPathCtx{Context: ctx, parent: fieldPathOf(ctx), index: index}
+}
+
+// withFieldKey returns the context of the map item of current field
+func withFieldKey(ctx context.Context, key string) context.Context {
+	return &fieldPathCtx{Context: ctx, parent: fieldPathOf(ctx), name: key, index: -1, key: true}
+}
+
+// fieldPathOf returns the context of current field, nil for the root value
+func fieldPathOf(ctx context.Context) *fieldPathCtx {
+	c, _ := ctx.Value(fieldPathKey{}).(*fieldPathCtx)
+	return c
+}
+
+// fieldPath returns the path of current field, empty for the root value
+func fieldPath(ctx context.Context) string {
+	return fieldPathOf(ctx).String()
+}
+
+// interfaceOf returns the value as interface{}, nil if the value is invalid or an unexported field
+func interfaceOf(val reflect.Value) interface{} {
+	if !val.IsValid() || !val.CanInterface() {
+		return nil
+	}
+	return val.Interface()
+}
+
+// joinFieldPath joins the field name to the parent path
+func joinFieldPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func (p *Pagser) doParseInterface(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
 	// Get underlying value
 	underlyingValue := val.Elem()
 
@@ -118,7 +284,7 @@ func (p *Pagser) doParseInterface(val reflect.Value, stackValues []reflect.Value
 		underlyingValue = newPtr
 	}
 
-	err := p.doParse(underlyingValue, stackValues, selection)
+	err := p.doParse(ctx, underlyingValue, stackValues, selection)
 	if err != nil {
 		return err
 	}
@@ -132,77 +298,409 @@ func (p *Pagser) doParseInterface(val reflect.Value, stackValues []reflect.Value
 	return nil
 }
 
-func (p *Pagser) doParseStruct(val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
-	for i := 0; i < val.NumField(); i++ {
-		fieldValue := val.Field(i)
-		fieldType := val.Type().Field(i)
+func (p *Pagser) doParseStruct(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+	if hook, ok := findHook(val, beforeParserType); ok {
+		if err := hook.(BeforeParser).BeforeParse(selection); err != nil {
+			return fmt.Errorf("%v before parse error: %w", val.Type(), err)
+		}
+	}
+	if err := p.doParseFields(ctx, val, stackValues, selection); err != nil {
+		return err
+	}
+	if hook, ok := findHook(val, afterParserType); ok {
+		if err := hook.(AfterParser).AfterParse(); err != nil {
+			return fmt.Errorf("%v after parse error: %w", val.Type(), err)
+		}
+	}
+	return nil
+}
+
+// doParseFields parse the fields of struct, the hooks of struct are not called
+func (p *Pagser) doParseFields(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+	// the computed fields are evaluated after the other fields are parsed
+	var computed []computedField
+	// the errors of fields are collected if Config.CollectErrors is enabled
+	var errs []error
+	plan, err := p.structPlanOf(val.Type())
+	if err != nil {
+		return err
+	}
+	// the struct is the last of the parent values of its fields, the parent values are copied once for each struct,
+	// so the slice items parsed concurrently by Config.SliceWorkers do not share the appended values
+	stack := append(stackValues[:len(stackValues):len(stackValues)], val)
+	report := reportOf(ctx)
+	for _, field := range plan.fields {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-		// tagValue := fieldType.Tag.Get(parserTagName)
-		tagValue, tagOk := fieldType.Tag.Lookup(p.Config.TagName)
-		if !tagOk {
-			if p.Config.Debug {
-				fmt.Printf("[INFO] not found tag name=[%v] in field: %v, eg: `%v:\".navlink a->attr(href)\"`\n",
-					p.Config.TagName, fieldType.Name, p.Config.TagName)
+		fieldValue := val.Field(field.index)
+		if field.embedded {
+			if !isEmbeddedStruct(val.Type().Field(field.index), fieldValue) {
+				continue
+			}
+			// Parse the tagged fields of embedded struct against current selection,
+			// the hooks of embedded struct are promoted to the struct, so they are not called again
+			embeddedValue := fieldValue
+			if embeddedValue.Kind() == reflect.Pointer {
+				if embeddedValue.IsNil() {
+					embeddedValue.Set(reflect.New(embeddedValue.Type().Elem()))
+				}
+				embeddedValue = embeddedValue.Elem()
+			}
+			err := p.doParseFields(ctx, embeddedValue, stack, selection)
+			if err != nil {
+				err = fmt.Errorf("embedded %v parser error: %w", field.name, err)
+				if !p.canCollect(err) {
+					return err
+				}
+				errs = append(errs, err)
 			}
 			continue
 		}
-		if tagValue == ignoreSymbol {
+
+		tag := field.tag
+		if tag.Computed != nil {
+			computed = append(computed, computedField{index: field.index, expr: tag.Computed})
 			continue
 		}
 
-		cacheTag, ok := p.mapTags.Load(tagValue)
-		var tag *tagTokenizer
-		var err error
-		if !ok || cacheTag == nil {
-			tag, err = p.newTag(tagValue)
-			if err != nil {
+		fieldCtx := withFieldName(ctx, field.name)
+		var path string
+		var started time.Time
+		if report != nil || p.Config.OnField != nil {
+			path, started = fieldPath(fieldCtx), time.Now()
+		}
+		reportIndex := 0
+		if report != nil {
+			reportIndex = report.begin(path, tag.Selector, false)
+		}
+		node, err := p.doParseField(fieldCtx, val, stack, field, selection)
+		if report != nil {
+			report.end(reportIndex, node.Size(), tag.HasDefault && node.Size() <= 0, started, err)
+		}
+		if p.Config.OnField != nil {
+			p.Config.OnField(path, tag.Selector, node.Size(), interfaceOf(fieldValue), err)
+		}
+		if err != nil {
+			if !p.canCollect(err) {
 				return err
 			}
-			p.mapTags.Store(tagValue, tag)
-		} else {
-			tag = cacheTag.(*tagTokenizer)
+			errs = append(errs, err)
 		}
+	}
+	if err := p.setComputedFields(ctx, val, computed); err != nil {
+		if !p.canCollect(err) {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// doParseField parse the field of struct by the tag, returns the matched nodes of field,
+// the stack is the parent values of field, the struct val is the last
+func (p *Pagser) doParseField(ctx context.Context, val reflect.Value, stack []reflect.Value, field *fieldPlan, selection *goquery.Selection) (*goquery.Selection, error) {
+	fieldValue := val.Field(field.index)
+	tag, tagValue := field.tag, field.tagValue
+	opts := fieldOptions{Layout: field.layout, Base: parseBaseOf(ctx)}
 
-		node := selection
-		if tag.Selector != "" {
-			node = selection.Find(tag.Selector)
+	node := tag.find(selection)
+	if tag.HasDefault && node.Size() <= 0 {
+		// Set the default value of missing field, the functions are not called
+		svErr := p.setFieldValue(fieldValue, tag.Default, opts)
+		if svErr != nil {
+			return node, newParseError(ctx, tag, tagValue, "", fmt.Errorf("set default value error: %w", svErr))
 		}
+		return node, nil
+	}
+	if tag.Required && node.Size() <= 0 {
+		return node, newParseError(ctx, tag, tagValue, "", errors.New("is required: selector matched no nodes"))
+	}
+	if tag.OmitEmpty && node.Size() <= 0 {
+		// Keep the zero value of missing field, eg: nil pointer instead of an empty struct
+		return node, nil
+	}
+	if (tag.Strict || (p.Config.StrictSelectors && !tag.checksPresence())) && tag.Selector != "" && node.Size() <= 0 {
+		return node, newParseError(ctx, tag, tagValue, "", fmt.Errorf("selector `%v` matched no nodes", tag.Selector))
+	}
 
-		var callOutValue interface{}
+	// Call the function pipeline, the selection output is set to current node,
+	// other output is passed to the next function as the text of selection.
+	var callOutValue interface{}
+	hasOutValue := false
+	for k, fn := range tag.Funcs {
+		fnNode := node
+		if hasOutValue {
+			var valueErr error
+			if fnNode, valueErr = valueSelection(callOutValue); valueErr != nil {
+				return node, newParseError(ctx, tag, tagValue, fn.Name, fmt.Errorf("parse func error: %w", valueErr))
+			}
+		}
 		var callErr error
-		if tag.FuncName != "" {
-			callOutValue, callErr = p.findAndExecFunc(val, stackValues, tag, node)
-			if callErr != nil {
-				return fmt.Errorf("tag=`%v` parse func error: %v", tagValue, callErr)
-			}
-			if subNode, ok := callOutValue.(*goquery.Selection); ok {
-				// set sub node to current node
-				node = subNode
-			} else {
-				svErr := p.setFieldValue(fieldValue, callOutValue)
-				if svErr != nil {
-					return fmt.Errorf("tag=`%v` set value error: %v", tagValue, svErr)
+		callOutValue, callErr = p.execFunc(val, stack[:len(stack)-1], field.funcs[k], fn, fnNode, opts.Base)
+		if callErr != nil {
+			return node, newParseError(ctx, tag, tagValue, fn.Name, fmt.Errorf("parse func error: %w", callErr))
+		}
+		if subNode, ok := callOutValue.(*goquery.Selection); ok && !isValueSelection(subNode) {
+			// set sub node to current node
+			node = subNode
+			hasOutValue = false
+		} else {
+			hasOutValue = true
+		}
+	}
+	if hasOutValue {
+		if valueNode, ok := callOutValue.(*goquery.Selection); ok {
+			// the selection of pipeline values is set as text
+			callOutValue = nodeTextValue(fieldValue.Type(), valueNode)
+		}
+		if tag.Required && isEmptyValue(callOutValue) {
+			return node, newParseError(ctx, tag, tagValue, "", errors.New("is required: value is empty"))
+		}
+		if subMap, ok := callOutValue.(*SelectionMap); ok {
+			// parse the keyed sub nodes to map field
+			err := p.doParseMap(ctx, fieldValue, stack, subMap, opts)
+			if err != nil {
+				return node, newParseError(ctx, tag, tagValue, "", fmt.Errorf("parser error: %w", err))
+			}
+			return node, nil
+		}
+		if text, ok := callOutValue.(string); ok && field.setText != nil && field.setText(fieldValue, text) {
+			return node, nil
+		}
+		svErr := p.setFieldValue(fieldValue, callOutValue, opts)
+		if svErr != nil {
+			return node, newParseError(ctx, tag, tagValue, "", fmt.Errorf("set value error: %w", svErr))
+		}
+		return node, nil
+	}
+
+	if tag.Required && p.isTextType(fieldValue.Type()) && strings.TrimSpace(node.Text()) == "" {
+		return node, newParseError(ctx, tag, tagValue, "", errors.New("is required: value is empty"))
+	}
+
+	// Value types such as time.Time are set from the node text instead of being parsed as nested structs
+	if p.isValueType(fieldValue.Type()) {
+		svErr := p.setFieldValue(fieldValue, nodeTextValue(fieldValue.Type(), node), opts)
+		if svErr != nil {
+			return node, newParseError(ctx, tag, tagValue, "", fmt.Errorf("set value error: %w", svErr))
+		}
+		return node, nil
+	}
+
+	// The text of node is set to the string, bool and number fields like doParse but without boxing the values
+	if field.setText != nil && field.setText(fieldValue, strings.TrimSpace(node.Text())) {
+		return node, nil
+	}
+
+	// Do parse on struct field
+	err := p.doParse(ctx, fieldValue, stack, node)
+	if err != nil {
+		return node, newParseError(ctx, tag, tagValue, "", fmt.Errorf("parser error: %w", err))
+	}
+	return node, nil
+}
+
+// loadTag returns the cached tagTokenizer of tag value, or parses and caches it
+func (p *Pagser) loadTag(tagValue string) (*tagTokenizer, error) {
+	if cacheTag, ok := p.mapTags.Load(tagValue); ok && cacheTag != nil {
+		return cacheTag.(*tagTokenizer), nil
+	}
+	tag, err := p.newTag(tagValue)
+	if err != nil {
+		return nil, err
+	}
+	p.mapTags.Store(tagValue, tag)
+	return tag, nil
+}
+
+// fieldOrder returns the field indexes of struct in parse order, the fields are in declaration order
+// unless they depend on the fields declared after them by the `depends` tag, eg: `depends:"Price,Quantity"`,
+// the computed fields also depend on the fields referenced in expression.
+func (p *Pagser) fieldOrder(t reflect.Type) ([]int, error) {
+	if cache, ok := p.mapOrders.Load(t); ok {
+		return cache.([]int), nil
+	}
+	count := t.NumField()
+	computed := make([]bool, count)
+	deps := make([][]int, count)
+	for i := 0; i < count; i++ {
+		field := t.Field(i)
+		names := make([]string, 0)
+		if depends := strings.TrimSpace(field.Tag.Get(dependsTagName)); depends != "" {
+			for _, name := range strings.Split(depends, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
 				}
-				// goto parse next field
-				continue
 			}
 		}
+		if tagValue, ok := field.Tag.Lookup(p.Config.TagName); ok && tagValue != ignoreSymbol {
+			tag, err := p.loadTag(tagValue)
+			if err != nil {
+				return nil, err
+			}
+			if tag.Computed != nil {
+				computed[i] = true
+				for _, name := range tag.Computed.Fields {
+					// the unknown names are reported by the expression evaluation
+					if _, ok := t.FieldByName(name); ok {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+		for _, name := range names {
+			dep, ok := t.FieldByName(name)
+			if !ok || len(dep.Index) != 1 {
+				return nil, fmt.Errorf("field %v depends on unknown field %v", field.Name, name)
+			}
+			deps[i] = append(deps[i], dep.Index[0])
+		}
+	}
+	for i := 0; i < count; i++ {
+		for _, dep := range deps[i] {
+			if computed[dep] && !computed[i] {
+				return nil, fmt.Errorf("field %v can not depend on computed field %v", t.Field(i).Name, t.Field(dep).Name)
+			}
+		}
+	}
 
-		if stackValues == nil {
-			stackValues = make([]reflect.Value, 0)
+	// topological sort, the first field in declaration order whose dependencies are done is the next
+	order := make([]int, 0, count)
+	done := make([]bool, count)
+	for len(order) < count {
+		next := -1
+		for i := 0; i < count && next < 0; i++ {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[i] {
+				if !done[dep] && dep != i {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+			}
 		}
-		stackValues = append(stackValues, val)
+		if next < 0 {
+			cycle := make([]string, 0)
+			for i := 0; i < count; i++ {
+				if !done[i] {
+					cycle = append(cycle, t.Field(i).Name)
+				}
+			}
+			return nil, fmt.Errorf("fields %v of %v have cyclic dependencies", strings.Join(cycle, ", "), t)
+		}
+		done[next] = true
+		order = append(order, next)
+	}
+	p.mapOrders.Store(t, order)
+	return order, nil
+}
 
-		// Do parse on struct field
-		err = p.doParse(fieldValue, stackValues, node)
+// computedField the computed field of struct
+type computedField struct {
+	index int
+	expr  *fieldExpr
+}
+
+// setComputedFields evaluates the expressions of computed fields in dependency order with the parsed field values,
+// so a computed field can reference the other computed fields.
+func (p *Pagser) setComputedFields(ctx context.Context, val reflect.Value, fields []computedField) error {
+	var errs []error
+	for _, field := range fields {
+		fieldType := val.Type().Field(field.index)
+		path := joinFieldPath(fieldPath(ctx), fieldType.Name)
+		tagValue := computedSymbol + field.expr.Source
+		report, started := reportOf(ctx), time.Now()
+		err := p.setComputedField(ctx, val, field)
 		if err != nil {
-			return fmt.Errorf("tag=`%v` %#v parser error: %v", tagValue, fieldValue, err)
+			err = &ParseError{Path: path, Tag: tagValue, Err: err}
+		}
+		if report != nil {
+			report.end(report.begin(path, tagValue, true), 0, false, started, err)
+		}
+		if p.Config.OnField != nil {
+			p.Config.OnField(path, tagValue, 0, interfaceOf(val.Field(field.index)), err)
 		}
+		if err != nil {
+			if !p.canCollect(err) {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setComputedField evaluates the expression of computed field and set the value
+func (p *Pagser) setComputedField(ctx context.Context, val reflect.Value, field computedField) error {
+	fieldType := val.Type().Field(field.index)
+	value, err := field.expr.eval(val)
+	if err != nil {
+		return fmt.Errorf("evaluate error: %w", err)
+	}
+	err = p.setFieldValue(val.Field(field.index), value, fieldOptions{Layout: fieldType.Tag.Get(layoutTagName), Base: parseBaseOf(ctx)})
+	if err != nil {
+		return fmt.Errorf("set value error: %w", err)
 	}
 	return nil
 }
 
-func (p *Pagser) doParseSlice(val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
+// isEmbeddedStruct reports whether field is an embedded struct or pointer to struct which can be parsed
+func isEmbeddedStruct(fieldType reflect.StructField, fieldValue reflect.Value) bool {
+	if !fieldType.Anonymous {
+		return false
+	}
+	t := fieldType.Type
+	if t.Kind() == reflect.Ptr {
+		// Nil pointer of unexported embedded struct can not be allocated
+		if fieldValue.IsNil() && !fieldValue.CanSet() {
+			return false
+		}
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// isTextType returns true if the field is set from the node text when there is no function
+func (p *Pagser) isTextType(t reflect.Type) bool {
+	if p.isValueType(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Struct, reflect.Slice, reflect.Map:
+		return false
+	}
+	return true
+}
+
+// isEmptyValue returns true if the output value of function is nil, blank string or empty slice or map
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if text, ok := value.(string); ok {
+		return strings.TrimSpace(text) == ""
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		return len(raw) == 0 || string(raw) == "null"
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+func (p *Pagser) doParseSlice(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) error {
 	// Get slice to parse into, creating a new one if it is nil
 	slice := val
 	var newSlice bool
@@ -213,12 +711,25 @@ func (p *Pagser) doParseSlice(val reflect.Value, stackValues []reflect.Value, se
 
 	// Parse into slice
 	var err error
-	selection.EachWithBreak(func(i int, subNode *goquery.Selection) bool {
-		// Do parse on slice item
-		itemValue := slice.Index(i)
-		err = p.doParse(itemValue, stackValues, subNode)
-		return err == nil
-	})
+	var errs []error
+	if p.Config.SliceWorkers > 1 && selection.Size() > 1 {
+		errs, err = p.doParseSliceItems(ctx, slice, stackValues, selection)
+	} else {
+		selection.EachWithBreak(func(i int, subNode *goquery.Selection) bool {
+			if err = ctx.Err(); err != nil {
+				return false
+			}
+
+			// Do parse on slice item
+			itemValue := slice.Index(i)
+			err = p.doParse(withFieldIndex(ctx, i), itemValue, stackValues, subNode)
+			if err != nil && p.canCollect(err) {
+				errs = append(errs, err)
+				err = nil
+			}
+			return err == nil
+		})
+	}
 	if err != nil {
 		return err
 	}
@@ -228,10 +739,195 @@ func (p *Pagser) doParseSlice(val reflect.Value, stackValues []reflect.Value, se
 		val.Set(slice)
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// doParseSliceItems parse the items of slice by the Config.SliceWorkers goroutines, the items after the item which
+// returns the error which can not be collected are skipped, returns the collected errors in the order of items
+// and the first error of items which can not be collected
+func (p *Pagser) doParseSliceItems(ctx context.Context, slice reflect.Value, stackValues []reflect.Value, selection *goquery.Selection) ([]error, error) {
+	itemErrs := make([]error, selection.Size())
+	indexes := make(chan int)
+	// the least index of the items which failed, the items before it are still parsed, so the first error is kept
+	var failed atomic.Int64
+	failed.Store(int64(selection.Size()))
+	var wg sync.WaitGroup
+	for w := 0; w < p.Config.SliceWorkers && w < selection.Size(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if int64(i) > failed.Load() || ctx.Err() != nil {
+					continue
+				}
+				err := p.doParse(withFieldIndex(ctx, i), slice.Index(i), stackValues, selection.Eq(i))
+				for err != nil && !p.canCollect(err) {
+					least := failed.Load()
+					if int64(i) >= least || failed.CompareAndSwap(least, int64(i)) {
+						break
+					}
+				}
+				itemErrs[i] = err
+			}
+		}()
+	}
+	for i := range selection.Nodes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var errs []error
+	var firstErr error
+	for _, err := range itemErrs {
+		switch {
+		case err == nil:
+		case p.canCollect(err):
+			errs = append(errs, err)
+		case firstErr == nil:
+			firstErr = err
+		}
+	}
+	return errs, firstErr
+}
+
+// isValueType reports whether values of type t (or the items of a slice of t) are set from text
+// rather than parsed as a nested struct, eg: time.Time, url.URL, json.RawMessage, encoding.TextUnmarshaler,
+// registered converter types
+func (p *Pagser) isValueType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		if t == rawMessageType || p.hasTypeConverter(t) {
+			return true
+		}
+		t = t.Elem()
+	}
+	return t == timeType || t == urlType || isTextUnmarshaler(t) || p.hasTypeConverter(t)
+}
+
+// isTextUnmarshaler reports whether the pointer of type t implements encoding.TextUnmarshaler,
+// time.Time is excluded as it is parsed by layout.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t != timeType && reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// nodeTextValue returns the text of each element for slice types, otherwise the text of node
+func nodeTextValue(t reflect.Type, node *goquery.Selection) interface{} {
+	return selectionText(node, t.Kind() == reflect.Slice && t != rawMessageType)
+}
+
+// fieldOptions options used to cast value to the field type
+type fieldOptions struct {
+	Layout string     // time layout for time.Time fields, empty layout will try common formats
+	Base   *parseBase // base url of the parse, used to resolve relative urls for url.URL fields
+}
+
+// nodeAttrs returns the attributes of the first element of node
+func nodeAttrs(node *goquery.Selection) map[string]string {
+	attrs := make(map[string]string)
+	if len(node.Nodes) == 0 {
+		return attrs
+	}
+	for _, attr := range node.Nodes[0].Attr {
+		attrs[attr.Key] = attr.Val
+	}
+	return attrs
+}
+
+// doParseMap set the keys and values of SelectionMap to the map, the keys are cast to the map key type
+// and the values are parsed by doParse to the map elem type.
+func (p *Pagser) doParseMap(ctx context.Context, val reflect.Value, stackValues []reflect.Value, selMap *SelectionMap, opts fieldOptions) error {
+	if val.Kind() != reflect.Map {
+		return fmt.Errorf("%v is not a map", val.Type())
+	}
+
+	// Get map to parse into, creating a new one if it is nil
+	if val.IsNil() {
+		val.Set(reflect.MakeMapWithSize(val.Type(), len(selMap.Keys)))
+	}
+
+	var errs []error
+	for i, key := range selMap.Keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Cast key to the map key type
+		keyValue := reflect.New(val.Type().Key()).Elem()
+		err := p.setFieldValue(keyValue, key, opts)
+		if err != nil {
+			err = fmt.Errorf("map key `%v` error: %v", key, err)
+			if !p.canCollect(err) {
+				return err
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		// Do parse on map item
+		itemValue := reflect.New(val.Type().Elem()).Elem()
+		err = p.doParse(withFieldKey(ctx, key), itemValue, stackValues, selMap.Values[i])
+		if err != nil {
+			if !p.canCollect(err) {
+				return err
+			}
+			errs = append(errs, err)
+		}
+		val.SetMapIndex(keyValue, itemValue)
+	}
+	return errors.Join(errs...)
 }
 
-func (p *Pagser) setFieldValue(fieldValue reflect.Value, value interface{}) error {
+// setFieldValue set value to field, casting it to the field type.
+func (p *Pagser) setFieldValue(fieldValue reflect.Value, value interface{}, opts fieldOptions) error {
+	// Unmarshal json to the field, eg: the result of json() function
+	if raw, ok := value.(json.RawMessage); ok && fieldValue.Type() != rawMessageType && fieldValue.CanAddr() {
+		err := json.Unmarshal(raw, fieldValue.Addr().Interface())
+		if err != nil && p.Config.CastError {
+			return err
+		}
+		return nil
+	}
+
+	// Convert value by the registered type converter
+	if convert, ok := p.findTypeConverter(fieldValue.Type()); ok {
+		castValueInterface, err := convertValue(convert, value)
+		if err != nil {
+			if p.Config.CastError {
+				return err
+			}
+			return nil
+		}
+		return setCastValue(fieldValue, castValueInterface, value)
+	}
+
+	// If the value cannot be set to a pointer field directly, set it to the underlying value
+	if fieldValue.Kind() == reflect.Ptr && (value == nil || !reflect.TypeOf(value).AssignableTo(fieldValue.Type())) {
+		if fieldValue.IsNil() {
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+		}
+		return p.setFieldValue(fieldValue.Elem(), value, opts)
+	}
+
+	// Unmarshal text to fields implement encoding.TextUnmarshaler
+	if text, ok := value.(string); ok && isTextUnmarshaler(fieldValue.Type()) && fieldValue.CanAddr() {
+		err := fieldValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+		if err != nil && p.Config.CastError {
+			return err
+		}
+		return nil
+	}
+
+	// Set items one by one for slices of the item types which can not be cast at once
+	if fieldValue.Kind() == reflect.Slice {
+		itemType := fieldValue.Type().Elem()
+		if itemType == rawMessageType || isTextUnmarshaler(itemType) || p.hasTypeConverter(itemType) {
+			return p.setSliceItemsValue(fieldValue, value, opts)
+		}
+	}
+
 	var castValueInterface any
 	var err error
 	switch fieldValue.Kind() {
@@ -239,7 +935,11 @@ func (p *Pagser) setFieldValue(fieldValue reflect.Value, value interface{}) erro
 		castValueInterface, err = cast.ToBoolE(value)
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		castValueInterface, err = cast.ToInt64E(value)
+		if fieldValue.Type() == durationType {
+			castValueInterface, err = cast.ToDurationE(value)
+		} else {
+			castValueInterface, err = cast.ToInt64E(value)
+		}
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		castValueInterface, err = cast.ToUint64E(value)
@@ -250,7 +950,63 @@ func (p *Pagser) setFieldValue(fieldValue reflect.Value, value interface{}) erro
 	case reflect.String:
 		castValueInterface, err = cast.ToStringE(value)
 
+	case reflect.Struct:
+		switch fieldValue.Type() {
+		case timeType:
+			castValueInterface, err = toTimeE(value, opts.Layout)
+		case urlType:
+			castValueInterface, err = toUrlE(value, opts.Base)
+		default:
+			if groups, ok := value.(map[string]string); ok {
+				return p.setStructFieldsValue(fieldValue, groups, opts)
+			}
+			if form, ok := value.(FormData); ok && fieldValue.Type() != formDataType {
+				return p.setStructFieldsValue(fieldValue, form.formValues(), opts)
+			}
+			if price, ok := value.(Price); ok && fieldValue.Type() != priceType {
+				return p.setStructFieldsValue(fieldValue, price.priceFields(), opts)
+			}
+			castValueInterface = value
+		}
+
+	case reflect.Map:
+		// Run nested switch on item type of maps with string key
+		if fieldValue.Type().Key().Kind() != reflect.String {
+			castValueInterface = value
+			break
+		}
+		switch fieldValue.Type().Elem().Kind() {
+		case reflect.Bool:
+			castValueInterface, err = cast.ToStringMapBoolE(value)
+		case reflect.Int:
+			castValueInterface, err = cast.ToStringMapIntE(value)
+		case reflect.Int64:
+			castValueInterface, err = cast.ToStringMapInt64E(value)
+		case reflect.String:
+			castValueInterface, err = cast.ToStringMapStringE(value)
+		case reflect.Interface:
+			castValueInterface, err = toStringMapE(value)
+		default:
+			castValueInterface = value
+		}
+
 	case reflect.Slice, reflect.Array:
+		// The raw text is set to json.RawMessage to decode later
+		if fieldValue.Type() == rawMessageType {
+			if raw, ok := value.(json.RawMessage); ok {
+				// the json() output is set as is, it is not cast to string
+				castValueInterface = raw
+				break
+			}
+			var text string
+			text, err = cast.ToStringE(value)
+			castValueInterface = json.RawMessage(strings.TrimSpace(text))
+			break
+		}
+		// The table rows are set to the struct items by the header of columns
+		if rows, ok := value.([][]string); ok && fieldValue.Kind() == reflect.Slice && p.isTableRowType(fieldValue.Type().Elem()) {
+			return p.setTableRowsValue(fieldValue, rows, opts)
+		}
 		// Run nested switch on item type
 		switch fieldValue.Type().Elem().Kind() {
 		case reflect.Bool:
@@ -260,13 +1016,34 @@ func (p *Pagser) setFieldValue(fieldValue reflect.Value, value interface{}) erro
 		case reflect.Int32:
 			castValueInterface, err = toInt32SliceE(value)
 		case reflect.Int64:
-			castValueInterface, err = toInt64SliceE(value)
+			if fieldValue.Type().Elem() == durationType {
+				castValueInterface, err = cast.ToDurationSliceE(value)
+			} else {
+				castValueInterface, err = toInt64SliceE(value)
+			}
 		case reflect.Float32:
 			castValueInterface, err = toFloat32SliceE(value)
 		case reflect.Float64:
 			castValueInterface, err = toFloat64SliceE(value)
 		case reflect.String:
 			castValueInterface, err = cast.ToStringSliceE(value)
+		case reflect.Struct:
+			switch fieldValue.Type().Elem() {
+			case timeType:
+				castValueInterface, err = toTimeSliceE(value, opts.Layout)
+			case urlType:
+				if fieldValue.Kind() == reflect.Slice {
+					return p.setSliceItemsValue(fieldValue, value, opts)
+				}
+				castValueInterface = value
+			default:
+				castValueInterface = value
+			}
+		case reflect.Ptr:
+			if fieldValue.Kind() == reflect.Slice {
+				return p.setSliceItemsValue(fieldValue, value, opts)
+			}
+			castValueInterface = value
 		default:
 			castValueInterface = value
 		}
@@ -276,53 +1053,101 @@ func (p *Pagser) setFieldValue(fieldValue reflect.Value, value interface{}) erro
 	if err != nil && p.Config.CastError {
 		return err
 	}
+	return setCastValue(fieldValue, castValueInterface, value)
+}
+
+// setStructFieldsValue set the values to the struct fields by field name case-insensitively,
+// eg: regexGroups() named groups `(?P<author>\w+)` is set to field `Author`.
+func (p *Pagser) setStructFieldsValue(fieldValue reflect.Value, values map[string]string, opts fieldOptions) error {
+	for i := 0; i < fieldValue.NumField(); i++ {
+		fieldType := fieldValue.Type().Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		for name, value := range values {
+			if !strings.EqualFold(fieldType.Name, name) {
+				continue
+			}
+			fieldOpts := fieldOptions{Layout: fieldType.Tag.Get(layoutTagName), Base: opts.Base}
+			err := p.setFieldValue(fieldValue.Field(i), value, fieldOpts)
+			if err != nil {
+				return fmt.Errorf("field %v set value error: %w", fieldType.Name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
 
+// setCastValue set the cast value to field, converting it to field type if required
+func setCastValue(fieldValue reflect.Value, castValueInterface interface{}, value interface{}) error {
 	// Get the reflect value of cast value, converting it if required
 	castReflectValue := reflect.ValueOf(castValueInterface)
 	fieldType := fieldValue.Type()
+	if !castReflectValue.IsValid() {
+		fieldValue.Set(reflect.Zero(fieldType))
+		return nil
+	}
 	if castReflectValue.Type() != fieldType && castReflectValue.CanConvert(fieldType) {
 		castReflectValue = castReflectValue.Convert(fieldType)
 	}
+	if !castReflectValue.Type().AssignableTo(fieldType) {
+		return fmt.Errorf("unable to set %#v of type %T to %v", value, value, fieldType)
+	}
 
 	fieldValue.Set(castReflectValue)
 
 	return nil
 }
 
-func (p *Pagser) findAndExecFunc(val reflect.Value, stackValues []reflect.Value, selTag *tagTokenizer, node *goquery.Selection) (interface{}, error) {
-	// If function not set, return node as tring
-	if selTag.FuncName == "" {
-		return strings.TrimSpace(node.Text()), nil
+// setSliceItemsValue set each item of value to a new slice of the field type, for item types that can not be cast at once
+func (p *Pagser) setSliceItemsValue(fieldValue reflect.Value, value interface{}, opts fieldOptions) error {
+	items := reflect.ValueOf(value)
+	if !items.IsValid() || (items.Kind() != reflect.Slice && items.Kind() != reflect.Array) {
+		if p.Config.CastError {
+			return fmt.Errorf("unable to cast %#v of type %T to %v", value, value, fieldValue.Type())
+		}
+		return nil
+	}
+	slice := reflect.MakeSlice(fieldValue.Type(), items.Len(), items.Len())
+	for i := 0; i < items.Len(); i++ {
+		err := p.setFieldValue(slice.Index(i), items.Index(i).Interface(), opts)
+		if err != nil {
+			return err
+		}
 	}
+	fieldValue.Set(slice)
+	return nil
+}
 
+func (p *Pagser) findAndExecFunc(val reflect.Value, stackValues []reflect.Value, fn *tagFunc, node *goquery.Selection, parse *parseBase) (interface{}, error) {
 	// Try to find function in the methods of the value or its pointer, calling it if found
-	callMethod := findMethod(val, selTag.FuncName)
+	callMethod := findMethod(val, fn.Name)
 	if callMethod.IsValid() {
-		return execMethod(callMethod, selTag, node)
+		return execMethod(callMethod, fn, node)
 	}
 
 	// Try to find function in the methods of the parent values or their pointers, calling it if found
 	size := len(stackValues)
 	if size > 0 {
 		for i := size - 1; i >= 0; i-- {
-			callMethod = findMethod(stackValues[i], selTag.FuncName)
+			callMethod = findMethod(stackValues[i], fn.Name)
 			if callMethod.IsValid() {
-				return execMethod(callMethod, selTag, node)
+				return execMethod(callMethod, fn, node)
 			}
 		}
 	}
 
 	// Try to find function in the globally registered functions, calling it if found
-	if fn, ok := p.mapFuncs.Load(selTag.FuncName); ok {
-		cfn := fn.(CallFunc)
-		outValue, err := cfn(node, selTag.FuncParams...)
+	if f, ok := p.mapFuncs.Load(fn.Name); ok {
+		outValue, err := callFunc(f, parse, node, fn.Params...)
 		if err != nil {
-			return nil, fmt.Errorf("call registered func %v error: %v", selTag.FuncName, err)
+			return nil, fmt.Errorf("call registered func %v error: %v", fn.Name, err)
 		}
 		return outValue, nil
 	}
 
-	return nil, fmt.Errorf("method not found: %v", selTag.FuncName)
+	return nil, fmt.Errorf("method not found: %v", fn.Name)
 }
 
 // findMethod finds a function in the methods of a value or its pointer.
@@ -348,18 +1173,23 @@ func findMethod(val reflect.Value, funcName string) reflect.Value {
 	return reflect.Value{}
 }
 
-func execMethod(callMethod reflect.Value, selTag *tagTokenizer, node *goquery.Selection) (interface{}, error) {
+func execMethod(callMethod reflect.Value, fn *tagFunc, node *goquery.Selection) (interface{}, error) {
 	callParams := make([]reflect.Value, 0)
 	callParams = append(callParams, reflect.ValueOf(node))
+	if callMethod.Type().IsVariadic() {
+		for _, param := range fn.Params {
+			callParams = append(callParams, reflect.ValueOf(param))
+		}
+	}
 
 	callReturns := callMethod.Call(callParams)
 	if len(callReturns) <= 0 {
-		return nil, fmt.Errorf("method %v not return any value", selTag.FuncName)
+		return nil, fmt.Errorf("method %v not return any value", fn.Name)
 	}
 	if len(callReturns) > 1 {
 		if err, ok := callReturns[len(callReturns)-1].Interface().(error); ok {
 			if err != nil {
-				return nil, fmt.Errorf("method %v return error: %v", selTag.FuncName, err)
+				return nil, fmt.Errorf("method %v return error: %v", fn.Name, err)
 			}
 		}
 	}
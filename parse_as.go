@@ -0,0 +1,53 @@
+package pagser
+
+import (
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ParseAs parse html to a new value of type T and return it
+//	page, err := pagser.ParseAs[PageData](p, html)
+func ParseAs[T any](p *Pagser, document string) (T, error) {
+	return ParseReaderAs[T](p, strings.NewReader(document))
+}
+
+// ParseReaderAs parse reader to a new value of type T and return it
+func ParseReaderAs[T any](p *Pagser, reader io.Reader) (T, error) {
+	doc, err := p.newDocumentFromReader(reader, "")
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ParseDocumentAs[T](p, doc)
+}
+
+// ParseDocumentAs parse document to a new value of type T and return it
+func ParseDocumentAs[T any](p *Pagser, document *goquery.Document) (T, error) {
+	return parseAs[T](func(v interface{}) error {
+		return p.ParseDocument(v, document)
+	})
+}
+
+// ParseSelectionAs parse selection to a new value of type T and return it.
+// T may be a struct or a pointer to a struct.
+func ParseSelectionAs[T any](p *Pagser, selection *goquery.Selection) (T, error) {
+	return parseAs[T](func(v interface{}) error {
+		return p.ParseSelection(v, selection)
+	})
+}
+
+// parseAs parse to a new value of type T by parse and return it
+func parseAs[T any](parse func(v interface{}) error) (T, error) {
+	var v T
+	target := reflect.ValueOf(&v)
+	// If T is a pointer type, allocate the underlying value and parse into it
+	if t := reflect.TypeOf(v); t != nil && t.Kind() == reflect.Ptr {
+		target.Elem().Set(reflect.New(t.Elem()))
+		target = target.Elem()
+	}
+	err := parse(target.Interface())
+	return v, err
+}
@@ -0,0 +1,58 @@
+package pagser
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAs(t *testing.T) {
+	p := New()
+
+	data, err := ParseAs[PagserData](p, rawPagserHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser Example", data.Title)
+	require.Len(t, data.Navs, 4)
+	require.Equal(t, -1, data.Navs[0].ID)
+	require.Equal(t, "/list/mobile", data.Navs[3].Url)
+}
+
+func TestParseAs_Pointer(t *testing.T) {
+	p := New()
+
+	data, err := ParseAs[*PagserData](p, rawPagserHtml)
+	require.NoError(t, err)
+	require.NotNil(t, data)
+	require.Equal(t, "Pagser Example", data.Title)
+	require.Len(t, data.Navs, 4)
+}
+
+func TestParseAs_NonStruct(t *testing.T) {
+	p := New()
+
+	_, err := ParseAs[string](p, rawPagserHtml)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not a struct")
+}
+
+func TestParseDocumentAs_Url(t *testing.T) {
+	type linkData struct {
+		Link string `pagser:"a->absHref()"`
+	}
+	p := New()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<a href="/next">Next</a>`))
+	require.NoError(t, err)
+	doc.Url, err = url.Parse("https://example.com/page")
+	require.NoError(t, err)
+	data, err := ParseDocumentAs[linkData](p, doc)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/next", data.Link)
+
+	// the url of document is used only during parsing
+	_, err = ParseSelectionAs[linkData](p, doc.Selection)
+	require.Error(t, err)
+}
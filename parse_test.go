@@ -1,15 +1,21 @@
 package pagser
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
 )
 
 const rawParseHtml = `
@@ -585,3 +591,1020 @@ func TestPagser_RegisterFunc(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestPagser_ParseContext(t *testing.T) {
+	p := New()
+	p.RegisterFunc("MyGlobFunc", MyGlobalFunc)
+	p.RegisterFunc("SameFunc", SameFunc)
+
+	var data ParseData
+	err := p.ParseContext(context.Background(), &data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser Example", data.Title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var canceledData ParseData
+	err = p.ParseContext(ctx, &canceledData, rawParseHtml)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, "", canceledData.Title)
+}
+
+func TestParse_Time(t *testing.T) {
+	type TimeData struct {
+		Date        time.Time    `pagser:".date" layout:"2006-01-02"`
+		DatePtr     *time.Time   `pagser:".date" layout:"2006-01-02"`
+		DateFunc    time.Time    `pagser:".date->date('2006-01-02')"`
+		DateAttr    time.Time    `pagser:".date->attr(datetime)"`
+		DateNoMatch time.Time    `pagser:".missing->attr(datetime)"`
+		Dates       []time.Time  `pagser:".dates li" layout:"02/01/2006"`
+		DatesEach   []time.Time  `pagser:".dates li->eachText()" layout:"02/01/2006"`
+		DatePtrs    []*time.Time `pagser:".dates li" layout:"02/01/2006"`
+	}
+
+	p := New()
+
+	var data TimeData
+	err := p.Parse(&data, `
+<span class="date" datetime="2020-04-25T12:26:04Z">2020-04-25</span>
+<ul class="dates"><li>01/02/2020</li><li>03/04/2021</li></ul>
+`)
+	require.NoError(t, err)
+
+	date := time.Date(2020, 4, 25, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, date, data.Date)
+	require.NotNil(t, data.DatePtr)
+	require.Equal(t, date, *data.DatePtr)
+	require.Equal(t, date, data.DateFunc)
+	require.Equal(t, time.Date(2020, 4, 25, 12, 26, 4, 0, time.UTC), data.DateAttr)
+	require.True(t, data.DateNoMatch.IsZero())
+
+	dates := []time.Time{
+		time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+	require.Equal(t, dates, data.Dates)
+	require.Equal(t, dates, data.DatesEach)
+	require.Len(t, data.DatePtrs, 2)
+	require.Equal(t, dates[1], *data.DatePtrs[1])
+}
+
+func TestParse_TimeCastError(t *testing.T) {
+	type TimeData struct {
+		Date time.Time `pagser:".date" layout:"2006-01-02"`
+	}
+
+	cfg := DefaultConfig()
+	cfg.CastError = true
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+
+	var data TimeData
+	err = p.Parse(&data, `<span class="date">25/04/2020</span>`)
+	require.Error(t, err)
+}
+
+func TestParse_Duration(t *testing.T) {
+	type DurationData struct {
+		Uptime     time.Duration   `pagser:".uptime"`
+		UptimeFunc time.Duration   `pagser:".uptime->duration()"`
+		UptimeAttr time.Duration   `pagser:".uptime->attr(data-value)"`
+		Timeouts   []time.Duration `pagser:".timeouts li->eachText()"`
+	}
+
+	p := New()
+
+	var data DurationData
+	err := p.Parse(&data, `
+<span class="uptime" data-value="90s">1h30m</span>
+<ul class="timeouts"><li>90s</li><li>250ms</li></ul>
+`)
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Minute, data.Uptime)
+	require.Equal(t, 90*time.Minute, data.UptimeFunc)
+	require.Equal(t, 90*time.Second, data.UptimeAttr)
+	require.Equal(t, []time.Duration{90 * time.Second, 250 * time.Millisecond}, data.Timeouts)
+}
+
+func TestParse_Url(t *testing.T) {
+	type UrlData struct {
+		Link     url.URL    `pagser:"a.link->attr(href)"`
+		LinkPtr  *url.URL   `pagser:"a.link->attr(href)"`
+		LinkAbs  *url.URL   `pagser:"a.abs->attr(href)"`
+		LinkText url.URL    `pagser:"a.text"`
+		Links    []url.URL  `pagser:"a->eachAttr(href)"`
+		LinkPtrs []*url.URL `pagser:"a->eachAttr(href)"`
+	}
+
+	const rawUrlHtml = `
+<a class="link" href="/list/web">Web page</a>
+<a class="abs" href="https://github.com/foolin/pagser">Pagser</a>
+<a class="text" href="#">list/text</a>
+`
+
+	cfg := DefaultConfig()
+	cfg.BaseURL = "https://thisvar.com/index.html"
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+
+	var data UrlData
+	err = p.Parse(&data, rawUrlHtml)
+	require.NoError(t, err)
+	require.Equal(t, "https://thisvar.com/list/web", data.Link.String())
+	require.Equal(t, "https://thisvar.com/list/web", data.LinkPtr.String())
+	require.Equal(t, "https://github.com/foolin/pagser", data.LinkAbs.String())
+	require.Equal(t, "https://thisvar.com/list/text", data.LinkText.String())
+	require.Len(t, data.Links, 3)
+	require.Equal(t, "https://thisvar.com/index.html", data.Links[2].String())
+	require.Len(t, data.LinkPtrs, 3)
+	require.Equal(t, "https://github.com/foolin/pagser", data.LinkPtrs[1].String())
+
+	// <base href> of document takes precedence over the BaseURL config
+	var baseData UrlData
+	err = p.Parse(&baseData, `<head><base href="/docs/"></head><body>`+rawUrlHtml+`</body>`)
+	require.NoError(t, err)
+	require.Equal(t, "https://thisvar.com/list/web", baseData.Link.String())
+	require.Equal(t, "https://thisvar.com/docs/list/text", baseData.LinkText.String())
+
+	// relative url is kept if there is no base url
+	var relativeData UrlData
+	err = New().Parse(&relativeData, rawUrlHtml)
+	require.NoError(t, err)
+	require.Equal(t, "/list/web", relativeData.Link.String())
+}
+
+func TestParse_Map(t *testing.T) {
+	type MapData struct {
+		Attrs      map[string]string      `pagser:"img->attrs()"`
+		AttrsNoFun map[string]string      `pagser:"img"`
+		AttrsAny   map[string]interface{} `pagser:"img->attrs()"`
+		AttrsList  []map[string]string    `pagser:"img"`
+		NoMatch    map[string]string      `pagser:".missing->attrs()"`
+	}
+
+	p := New()
+
+	var data MapData
+	err := p.Parse(&data, `
+<img src="/logo.png" alt="Pagser" data-id="1">
+<img src="/icon.png">
+`)
+	require.NoError(t, err)
+
+	attrs := map[string]string{"src": "/logo.png", "alt": "Pagser", "data-id": "1"}
+	require.Equal(t, attrs, data.Attrs)
+	require.Equal(t, attrs, data.AttrsNoFun)
+	require.Equal(t, map[string]interface{}{"src": "/logo.png", "alt": "Pagser", "data-id": "1"}, data.AttrsAny)
+	require.Equal(t, []map[string]string{attrs, {"src": "/icon.png"}}, data.AttrsList)
+	require.Equal(t, map[string]string{}, data.NoMatch)
+}
+
+func TestParse_MapOf(t *testing.T) {
+	type MapOfData struct {
+		Groups      map[string][]string `pagser:".group->mapOf(h2, .item)"`
+		GroupFirst  map[string]string   `pagser:".group->mapOf(h2, .item:first-child)"`
+		GroupStruct map[string]struct {
+			ID     string   `pagser:"->attr(id)"`
+			Values []string `pagser:".item->eachAttr(value)"`
+		} `pagser:".group->mapOf(h2)"`
+	}
+
+	p := New()
+
+	var data MapOfData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"Email":  {"pagser@foolin.github", "hello@pagser.foolin"},
+		"Bool":   {"true", "false"},
+		"Number": {"12345", "67890"},
+		"Float":  {"123.45", "678.90"},
+	}, data.Groups)
+	require.Equal(t, "true", data.GroupFirst["Bool"])
+	require.Len(t, data.GroupStruct, 4)
+	require.Equal(t, "c", data.GroupStruct["Number"].ID)
+	require.Equal(t, []string{"12345", "67890"}, data.GroupStruct["Number"].Values)
+}
+
+type textStatus int
+
+func (s *textStatus) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "active":
+		*s = 1
+	case "sold-out":
+		*s = 2
+	default:
+		return fmt.Errorf("unknown status: %s", text)
+	}
+	return nil
+}
+
+type textID struct {
+	Prefix string
+	Number string
+}
+
+func (id *textID) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), "-", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid id: %s", text)
+	}
+	id.Prefix, id.Number = parts[0], parts[1]
+	return nil
+}
+
+func TestParse_TextUnmarshaler(t *testing.T) {
+	type TextData struct {
+		Status    textStatus   `pagser:".status"`
+		StatusPtr *textStatus  `pagser:".status->attr(data-status)"`
+		Statuses  []textStatus `pagser:"li->eachText()"`
+		ID        textID       `pagser:".id"`
+		IDAttr    *textID      `pagser:".id->attr(data-id)"`
+		IDs       []textID     `pagser:".id"`
+	}
+
+	p := New()
+
+	var data TextData
+	err := p.Parse(&data, `
+<span class="status" data-status="sold-out">active</span>
+<ul><li>active</li><li>sold-out</li></ul>
+<span class="id" data-id="B-2">A-1</span>
+`)
+	require.NoError(t, err)
+	require.Equal(t, textStatus(1), data.Status)
+	require.Equal(t, textStatus(2), *data.StatusPtr)
+	require.Equal(t, []textStatus{1, 2}, data.Statuses)
+	require.Equal(t, textID{"A", "1"}, data.ID)
+	require.Equal(t, textID{"B", "2"}, *data.IDAttr)
+	require.Equal(t, []textID{{"A", "1"}}, data.IDs)
+
+	cfg := DefaultConfig()
+	cfg.CastError = true
+	p, err = NewWithConfig(cfg)
+	require.NoError(t, err)
+	err = p.Parse(&data, `<span class="status">unknown</span>`)
+	require.Error(t, err)
+}
+
+func TestParse_Json(t *testing.T) {
+	type JsonData struct {
+		Props struct {
+			Page struct {
+				Title string `json:"title"`
+			} `json:"page"`
+			Tags []string `json:"tags"`
+		} `pagser:"script#__NEXT_DATA__->json()"`
+		PropsMap  map[string]interface{}  `pagser:"script#__NEXT_DATA__->json()"`
+		PropsPtr  *map[string]interface{} `pagser:"script#__NEXT_DATA__->json()"`
+		Count     int                     `pagser:"script#count->json()"`
+		TagsSlice []string                `pagser:"script#tags->json()"`
+	}
+
+	p := New()
+
+	var data JsonData
+	err := p.Parse(&data, `
+<script id="__NEXT_DATA__" type="application/json">
+	{"page": {"title": "Pagser"}, "tags": ["golang", "html"]}
+</script>
+<script id="count" type="application/json">42</script>
+<script id="tags" type="application/json">["a", "b"]</script>
+`)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser", data.Props.Page.Title)
+	require.Equal(t, []string{"golang", "html"}, data.Props.Tags)
+	require.Equal(t, map[string]interface{}{"title": "Pagser"}, data.PropsMap["page"])
+	require.NotNil(t, data.PropsPtr)
+	require.Len(t, *data.PropsPtr, 2)
+	require.Equal(t, 42, data.Count)
+	require.Equal(t, []string{"a", "b"}, data.TagsSlice)
+}
+
+func TestParse_JsonRawMessage(t *testing.T) {
+	type RawData struct {
+		Raw     json.RawMessage   `pagser:"script#data"`
+		RawText json.RawMessage   `pagser:"script#data->text()"`
+		RawJson json.RawMessage   `pagser:"script#data->json()"`
+		RawPtr  *json.RawMessage  `pagser:"script#data"`
+		RawList []json.RawMessage `pagser:"script->eachText()"`
+	}
+
+	p := New()
+
+	var data RawData
+	err := p.Parse(&data, `
+<script id="data" type="application/json">
+	{"name": "pagser"}
+</script>
+<script id="count" type="application/json">42</script>
+`)
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`{"name": "pagser"}`), data.Raw)
+	require.Equal(t, json.RawMessage(`{"name": "pagser"}`), data.RawText)
+	require.Equal(t, json.RawMessage(`{"name": "pagser"}`), data.RawJson)
+	require.NotNil(t, data.RawPtr)
+	require.Equal(t, json.RawMessage(`{"name": "pagser"}`), *data.RawPtr)
+	require.Equal(t, []json.RawMessage{json.RawMessage(`{"name": "pagser"}`), json.RawMessage(`42`)}, data.RawList)
+}
+
+type EmbeddedBase struct {
+	Title    string   `pagser:"title"`
+	Keywords []string `pagser:"meta[name='keywords']->attrSplit(content)"`
+}
+
+type embeddedNav struct {
+	NavSize int `pagser:".navlink li->size()"`
+}
+
+type EmbeddedMeta struct {
+	H1 string `pagser:"h1->BaseFunc()"`
+}
+
+func (m EmbeddedMeta) BaseFunc(selection *goquery.Selection, args ...string) (out interface{}, err error) {
+	return "Base-" + selection.Text(), nil
+}
+
+func TestParse_Embedded(t *testing.T) {
+	type EmbeddedData struct {
+		EmbeddedBase
+		embeddedNav
+		*EmbeddedMeta
+		Email string `pagser:".item[name='email']->attr('value')"`
+	}
+
+	p := New()
+
+	var data EmbeddedData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser Example", data.Title)
+	require.Len(t, data.Keywords, 7)
+	require.Equal(t, 4, data.NavSize)
+	require.NotNil(t, data.EmbeddedMeta)
+	require.Equal(t, "Base-Pagser H1 Title", data.H1)
+	require.Equal(t, "pagser@foolin.github", data.Email)
+}
+
+func TestParse_Selection(t *testing.T) {
+	type SelectionData struct {
+		Nav        *goquery.Selection            `pagser:".navlink li->selection()"`
+		NavNoFunc  *goquery.Selection            `pagser:".navlink li"`
+		NavFirst   *goquery.Selection            `pagser:".navlink li->first()"`
+		NavEach    []*goquery.Selection          `pagser:".navlink li"`
+		NoMatch    *goquery.Selection            `pagser:".missing"`
+		GroupItems map[string]*goquery.Selection `pagser:".group->mapOf(h2, .item)"`
+	}
+
+	p := New()
+
+	var data SelectionData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, 4, data.Nav.Size())
+	require.Equal(t, 4, data.NavNoFunc.Size())
+	require.Equal(t, "Index", data.NavFirst.Text())
+	require.Len(t, data.NavEach, 4)
+	require.Equal(t, "Web page", data.NavEach[1].Find("a").Text())
+	require.NotNil(t, data.NoMatch)
+	require.Equal(t, 0, data.NoMatch.Size())
+	require.Equal(t, 2, data.GroupItems["Email"].Size())
+}
+
+func TestParse_HtmlNode(t *testing.T) {
+	type NodeData struct {
+		H1       *html.Node   `pagser:"h1"`
+		NavNodes []*html.Node `pagser:".navlink li"`
+		NavFirst *html.Node   `pagser:".navlink li->first()"`
+		NoMatch  *html.Node   `pagser:".missing"`
+	}
+
+	p := New()
+
+	var data NodeData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.NotNil(t, data.H1)
+	require.Equal(t, "h1", data.H1.Data)
+	require.Len(t, data.NavNodes, 4)
+	require.Equal(t, "li", data.NavNodes[3].Data)
+	require.Equal(t, data.NavNodes[0], data.NavFirst)
+	require.Nil(t, data.NoMatch)
+}
+
+type PipelineData struct {
+	Price      string   `pagser:".price->text()->trim('$ ')"`
+	PriceFloat float64  `pagser:".price->text()->trim('$ ')"`
+	Words      []string `pagser:".words->textSplit(|)->eachTextJoin(-)->textSplit(-)"`
+	WordsJoin  string   `pagser:".words->textSplit(|)->eachTextJoin(-)"`
+	WordFirst  string   `pagser:".words->textSplit(|)->first()->text()"`
+	WordLast   string   `pagser:".words->textSplit(|)->last()"`
+	NavFirst   string   `pagser:".navlink li->first()->text()"`
+	NavConcat  string   `pagser:".navlink li a->eqAndAttr(1, href)->textConcat('->', $value)"`
+	StructArgs string   `pagser:"h1->PrefixFunc('Prefix-')"`
+}
+
+func (pd PipelineData) PrefixFunc(selection *goquery.Selection, args ...string) (out interface{}, err error) {
+	return args[0] + selection.Text(), nil
+}
+
+func TestParse_Pipeline(t *testing.T) {
+	p := New()
+
+	var data PipelineData
+	err := p.Parse(&data, `
+<h1>Pagser</h1>
+<div class="navlink"><ul><li><a href="/">Index</a></li><li><a href="/list/web">Web page</a></li></ul></div>
+<div class="words">A|B|C|D</div>
+<span class="price"> $ 12.50 </span>
+`)
+	require.NoError(t, err)
+	require.Equal(t, "12.50", data.Price)
+	require.Equal(t, 12.5, data.PriceFloat)
+	require.Equal(t, []string{"A", "B", "C", "D"}, data.Words)
+	require.Equal(t, "A-B-C-D", data.WordsJoin)
+	require.Equal(t, "A", data.WordFirst)
+	require.Equal(t, "D", data.WordLast)
+	require.Equal(t, "Index", data.NavFirst)
+	require.Equal(t, "->/list/web", data.NavConcat)
+	require.Equal(t, "Prefix-Pagser", data.StructArgs)
+
+	// the json output is passed as is, the map output can not be passed as the text
+	type RawPipelineData struct {
+		Name string `pagser:"script->json()->regex('\"name\": \"(\\w+)\"')"`
+	}
+	var raw RawPipelineData
+	err = p.Parse(&raw, `<script type="application/json">{"name": "pagser"}</script>`)
+	require.NoError(t, err)
+	require.Equal(t, "pagser", raw.Name)
+
+	type MapPipelineData struct {
+		Name string `pagser:"dl->dlMap()->trim()"`
+	}
+	var mapped MapPipelineData
+	err = p.Parse(&mapped, `<dl><dt>name</dt><dd>pagser</dd></dl>`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can not be passed to the next function")
+}
+
+func TestParse_FallbackSelectors(t *testing.T) {
+	type FallbackData struct {
+		Title      string   `pagser:".new-title || h1.title || h1"`
+		TitleFunc  string   `pagser:".new-title || h1->text()"`
+		TitleFirst string   `pagser:"h1 || .new-title->text()"`
+		Items      []string `pagser:".missing || .navlink li->eachText()"`
+		NoMatch    string   `pagser:".missing || .none->textEmpty('nodata')"`
+	}
+
+	p := New()
+
+	var data FallbackData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser H1 Title", data.Title)
+	require.Equal(t, "Pagser H1 Title", data.TitleFunc)
+	require.Equal(t, "Pagser H1 Title", data.TitleFirst)
+	require.Equal(t, []string{"Index", "Web page", "Pc Page", "Mobile Page"}, data.Items)
+	require.Equal(t, "nodata", data.NoMatch)
+
+	type InvalidData struct {
+		Title string `pagser:"h1 || ->text()"`
+	}
+	var invalid InvalidData
+	err = p.Parse(&invalid, rawParseHtml)
+	require.Error(t, err)
+}
+
+func TestParse_Required(t *testing.T) {
+	type RequiredData struct {
+		Title string   `pagser:"h1->text(),required"`
+		Items []string `pagser:".navlink li->eachText(),required"`
+		Text  string   `pagser:"h1,required"`
+	}
+
+	p := New()
+
+	var data RequiredData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser H1 Title", data.Title)
+	require.Equal(t, "Pagser H1 Title", data.Text)
+
+	type MissingData struct {
+		Title string `pagser:".missing->text(),required"`
+	}
+	var missing MissingData
+	err = p.Parse(&missing, rawParseHtml)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Title is required")
+
+	type EmptyData struct {
+		Title string `pagser:"h1->attr(data-missing),required"`
+	}
+	var empty EmptyData
+	err = p.Parse(&empty, rawParseHtml)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "value is empty")
+
+	// the function finds no value, eg: jsonLd() of the missing type
+	type RequiredJsonLdData struct {
+		Event map[string]interface{} `pagser:"html->jsonLd(Event),required"`
+	}
+	var requiredJsonLd RequiredJsonLdData
+	err = p.Parse(&requiredJsonLd, `<script type="application/ld+json">{"@type": "Product"}</script>`)
+	require.Error(t, err)
+}
+
+func TestParse_Default(t *testing.T) {
+	type DefaultData struct {
+		Title    string   `pagser:"h1->text(),default=none"`
+		Price    float64  `pagser:".price->text(),default=9.5"`
+		Count    int      `pagser:".count,default=3"`
+		Name     string   `pagser:".name->attr(title),default='a, b'"`
+		Tags     []string `pagser:".tags->eachText(),default=x"`
+		Required string   `pagser:".missing->text(),default=ok,required"`
+	}
+
+	p := New()
+
+	var data DefaultData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser H1 Title", data.Title)
+	require.Equal(t, 9.5, data.Price)
+	require.Equal(t, 3, data.Count)
+	require.Equal(t, "a, b", data.Name)
+	require.Equal(t, []string{"x"}, data.Tags)
+	require.Equal(t, "ok", data.Required)
+}
+
+func TestParse_OmitEmpty(t *testing.T) {
+	type SubData struct {
+		Label string `pagser:"h2"`
+	}
+	type OmitEmptyData struct {
+		Missing    *SubData `pagser:".group:last-child,omitempty"`
+		Allocated  *SubData `pagser:".group:last-child"`
+		Found      *SubData `pagser:".group->eq(0),omitempty"`
+		MissingPtr *string  `pagser:".missing->text(),omitempty"`
+	}
+
+	p := New()
+
+	var data OmitEmptyData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Nil(t, data.Missing)
+	require.NotNil(t, data.Allocated)
+	require.NotNil(t, data.Found)
+	require.Equal(t, "Email", data.Found.Label)
+	require.Nil(t, data.MissingPtr)
+}
+
+func TestParse_Strict(t *testing.T) {
+	type StrictData struct {
+		Title   string `pagser:"h1,strict"`
+		Missing string `pagser:".missing-title"`
+	}
+	type StrictMissingData struct {
+		Title   string `pagser:".missing-title->text(),strict"`
+		Default string `pagser:".missing-default,default=ok"`
+	}
+
+	p := New()
+
+	var data StrictData
+	err := p.Parse(&data, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "Pagser H1 Title", data.Title)
+
+	var missing StrictMissingData
+	err = p.Parse(&missing, rawParseHtml)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "`.missing-title` matched no nodes")
+
+	cfg := DefaultConfig()
+	cfg.StrictSelectors = true
+	strict, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+
+	err = strict.Parse(&data, rawParseHtml)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "Missing", parseErr.Path)
+
+	type StrictAllowedData struct {
+		Title     string  `pagser:"h1"`
+		Default   string  `pagser:".missing-default,default=ok"`
+		OmitEmpty *string `pagser:".missing-omitempty,omitempty"`
+		Self      string  `pagser:"->attr(lang)"`
+	}
+	var allowed StrictAllowedData
+	err = strict.Parse(&allowed, rawParseHtml)
+	require.NoError(t, err)
+	require.Equal(t, "ok", allowed.Default)
+
+	// the presence functions report the missing nodes, unless the field is `strict`
+	type StrictPresenceData struct {
+		HasBadge bool   `pagser:".missing-badge->exists()"`
+		Badge    string `pagser:".missing-badge->ifExists('', 'yes', 'no')"`
+		Count    int    `pagser:".missing-badge->size()"`
+	}
+	var presence StrictPresenceData
+	err = strict.Parse(&presence, rawParseHtml)
+	require.NoError(t, err)
+	require.False(t, presence.HasBadge)
+	require.Equal(t, "no", presence.Badge)
+	require.Equal(t, 0, presence.Count)
+
+	type StrictPresenceExplicitData struct {
+		HasBadge bool `pagser:".missing-badge->exists(),strict"`
+	}
+	var explicit StrictPresenceExplicitData
+	err = strict.Parse(&explicit, rawParseHtml)
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "HasBadge", parseErr.Path)
+}
+
+type TreeNode struct {
+	Name     string      `pagser:"->ownText()"`
+	Link     string      `pagser:"> a->attr(href)"`
+	Children []*TreeNode `pagser:"> ul > li"`
+}
+
+func TestParse_Tree(t *testing.T) {
+	type TreeData struct {
+		Tree     []*TreeNode `pagser:".menu > li"`
+		Children []TreeNode  `pagser:".menu > li->first()->child(ul)->child(li)"`
+	}
+
+	p := New()
+
+	var data TreeData
+	err := p.Parse(&data, `
+<ul class="menu">
+	<li>Books<ul>
+		<li><a href="/fiction">Fiction</a></li>
+		<li>Science<ol><li>Physics</li></ol><ul><li>Biology<ul><li>Zoology</li></ul></li></ul></li>
+	</ul></li>
+	<li>Music</li>
+</ul>
+`)
+	require.NoError(t, err)
+	require.Len(t, data.Tree, 2)
+	require.Equal(t, "Books", data.Tree[0].Name)
+	require.Len(t, data.Tree[0].Children, 2)
+	require.Equal(t, "/fiction", data.Tree[0].Children[0].Link)
+	require.Empty(t, data.Tree[0].Children[0].Children)
+	science := data.Tree[0].Children[1]
+	require.Equal(t, "Science", science.Name)
+	require.Len(t, science.Children, 1)
+	require.Equal(t, "Biology", science.Children[0].Name)
+	require.Equal(t, "Zoology", science.Children[0].Children[0].Name)
+	require.Equal(t, "Music", data.Tree[1].Name)
+	require.Empty(t, data.Tree[1].Children)
+	require.Len(t, data.Children, 2)
+}
+
+func TestParse_AbsHrefBaseURL(t *testing.T) {
+	type AbsHrefData struct {
+		Link     string `pagser:"a->absHref()"`
+		Explicit string `pagser:"a->absHref('https://github.com/')"`
+	}
+
+	p, err := NewWithConfig(Config{TagName: "pagser", FuncSymbol: "->", BaseURL: "https://example.com/docs/"})
+	require.NoError(t, err)
+
+	var data AbsHrefData
+	err = p.Parse(&data, `<a href="intro?a=1">Intro</a>`)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/docs/intro?a=1", data.Link)
+	require.Equal(t, "https://github.com/intro?a=1", data.Explicit)
+
+	err = p.Parse(&data, `<head><base href="/v2/"></head><a href="intro">Intro</a>`)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/v2/intro", data.Link)
+
+	err = New().Parse(&data, `<a href="intro">Intro</a>`)
+	require.Error(t, err)
+}
+
+func TestParse_DocumentUrlConcurrent(t *testing.T) {
+	type Item struct {
+		Link string  `pagser:"a->absHref()"`
+		Url  url.URL `pagser:"a->attr(href)"`
+	}
+	type ItemsData struct {
+		Items []Item `pagser:"li"`
+	}
+
+	var sb strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&sb, `<li><a href="item-%d">Item</a></li>`, i)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<ul>" + sb.String() + "</ul>"))
+	require.NoError(t, err)
+	doc.Url, err = url.Parse("https://example.com/list/")
+	require.NoError(t, err)
+
+	// the parses of the same document do not share the document url, so the first one finished does not drop it
+	p := New()
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for g := range errs {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for k := 0; k < 5 && errs[g] == nil; k++ {
+				var data ItemsData
+				if errs[g] = p.ParseDocument(&data, doc); errs[g] == nil && data.Items[499].Link != "https://example.com/list/item-499" {
+					errs[g] = fmt.Errorf("unexpected link %v", data.Items[499].Link)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	var data ItemsData
+	require.NoError(t, p.ParseDocument(&data, doc))
+	require.Equal(t, "https://example.com/list/item-0", data.Items[0].Url.String())
+	// the document url is not kept after the parse
+	require.Error(t, p.ParseSelection(&data, doc.Selection))
+}
+
+func TestParse_Computed(t *testing.T) {
+	type CartItem struct {
+		Name      string  `pagser:".name"`
+		Price     float64 `pagser:".price->number()"`
+		Quantity  int     `pagser:".qty"`
+		FullPrice float64 `pagser:"=Price * Quantity"`
+		Label     string  `pagser:"=Name + ' x' + Quantity + ' = ' + FullPrice"`
+		Bulk      bool    `pagser:"= Quantity >= 10"`
+	}
+	type Cart struct {
+		Items []CartItem `pagser:".item"`
+	}
+
+	p := New()
+
+	var data Cart
+	err := p.Parse(&data, `<div class="item"><span class="name">Pen</span><span class="price">$1.50</span><span class="qty">4</span></div>
+		<div class="item"><span class="name">Paper</span><span class="price">$0.25</span><span class="qty">12</span></div>`)
+	require.NoError(t, err)
+	require.Equal(t, []CartItem{
+		{Name: "Pen", Price: 1.5, Quantity: 4, FullPrice: 6, Label: "Pen x4 = 6", Bulk: false},
+		{Name: "Paper", Price: 0.25, Quantity: 12, FullPrice: 3, Label: "Paper x12 = 3", Bulk: true},
+	}, data.Items)
+
+	var invalid struct {
+		Total float64 `pagser:"=Price *"`
+	}
+	require.Error(t, p.Parse(&invalid, `<div></div>`))
+
+	var unknown struct {
+		Total float64 `pagser:"=Price * 2"`
+	}
+	require.Error(t, p.Parse(&unknown, `<div></div>`))
+}
+
+type DependsData struct {
+	Link     string  `pagser:"a->JoinHost()" depends:"Host"`
+	Total    float64 `pagser:"=Subtotal + Shipping"`
+	Subtotal float64 `pagser:"=Price * Quantity"`
+	Host     string  `pagser:"meta[name='host']->attr(content)"`
+	Price    float64 `pagser:".price"`
+	Quantity int     `pagser:".qty"`
+	Shipping float64 `pagser:".shipping"`
+}
+
+func (d *DependsData) JoinHost(node *goquery.Selection, args ...string) (interface{}, error) {
+	return d.Host + node.AttrOr("href", ""), nil
+}
+
+func TestParse_Depends(t *testing.T) {
+	p := New()
+
+	var data DependsData
+	err := p.Parse(&data, `<meta name="host" content="https://example.com"><a href="/item/1">Item</a>
+		<span class="price">2.5</span><span class="qty">4</span><span class="shipping">5</span>`)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/item/1", data.Link)
+	require.Equal(t, 10.0, data.Subtotal)
+	require.Equal(t, 15.0, data.Total)
+
+	order, err := p.fieldOrder(reflect.TypeOf(data))
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 0, 4, 5, 2, 6, 1}, order)
+
+	var cycle struct {
+		A string `pagser:"h1" depends:"B"`
+		B string `pagser:"h2" depends:"A"`
+	}
+	require.Error(t, p.Parse(&cycle, `<h1>a</h1><h2>b</h2>`))
+
+	var unknown struct {
+		A string `pagser:"h1" depends:"Missing"`
+	}
+	require.Error(t, p.Parse(&unknown, `<h1>a</h1>`))
+
+	var dependsComputed struct {
+		A string `pagser:"h1" depends:"B"`
+		B string `pagser:"='b'"`
+	}
+	require.Error(t, p.Parse(&dependsComputed, `<h1>a</h1>`))
+}
+
+type UnmarshalPrice struct {
+	Amount   float64
+	Currency string
+}
+
+func (up *UnmarshalPrice) UnmarshalPagser(sel *goquery.Selection, p *Pagser) error {
+	text := strings.TrimSpace(sel.Text())
+	if text == "" {
+		return errors.New("empty price")
+	}
+	up.Currency = sel.AttrOr("data-currency", "USD")
+	_, err := fmt.Sscanf(text, "%f", &up.Amount)
+	return err
+}
+
+func TestParse_Unmarshaler(t *testing.T) {
+	type Product struct {
+		Name  string          `pagser:"h2"`
+		Price UnmarshalPrice  `pagser:".price"`
+		Sale  *UnmarshalPrice `pagser:".sale"`
+	}
+	type ProductList struct {
+		Products []Product        `pagser:".product"`
+		Prices   []UnmarshalPrice `pagser:".price"`
+	}
+
+	p := New()
+
+	var data ProductList
+	err := p.Parse(&data, `<div class="product"><h2>A</h2><span class="price" data-currency="EUR">10.5</span><span class="sale">9</span></div>
+		<div class="product"><h2>B</h2><span class="price">3</span><span class="sale">2</span></div>`)
+	require.NoError(t, err)
+	require.Equal(t, []Product{
+		{Name: "A", Price: UnmarshalPrice{10.5, "EUR"}, Sale: &UnmarshalPrice{9, "USD"}},
+		{Name: "B", Price: UnmarshalPrice{3, "USD"}, Sale: &UnmarshalPrice{2, "USD"}},
+	}, data.Products)
+	require.Equal(t, []UnmarshalPrice{{10.5, "EUR"}, {3, "USD"}}, data.Prices)
+
+	var price UnmarshalPrice
+	require.NoError(t, p.ParseSelection(&price, newTewSelection(`<span data-currency="GBP">7</span>`)))
+	require.Equal(t, UnmarshalPrice{7, "GBP"}, price)
+
+	var empty struct {
+		Price UnmarshalPrice `pagser:".price"`
+	}
+	require.Error(t, p.Parse(&empty, `<div></div>`))
+}
+
+type HookBase struct {
+	Calls []string
+}
+
+func (hb *HookBase) BeforeParse(sel *goquery.Selection) error {
+	hb.Calls = append(hb.Calls, "before")
+	return nil
+}
+
+type HookItem struct {
+	HookBase
+	Name  string `pagser:"->text()"`
+	Upper string
+}
+
+func (hi *HookItem) AfterParse() error {
+	if hi.Name == "" {
+		return errors.New("name is empty")
+	}
+	hi.Upper = strings.ToUpper(hi.Name)
+	hi.Calls = append(hi.Calls, "after")
+	return nil
+}
+
+type HookData struct {
+	Source string
+	Items  []HookItem `pagser:"li"`
+}
+
+func (hd *HookData) BeforeParse(sel *goquery.Selection) error {
+	hd.Source = sel.Find("ul").AttrOr("data-source", "")
+	return nil
+}
+
+func TestParse_Hooks(t *testing.T) {
+	p := New()
+
+	var data HookData
+	err := p.Parse(&data, `<ul data-source="test"><li>a</li><li>b</li></ul>`)
+	require.NoError(t, err)
+	require.Equal(t, "test", data.Source)
+	require.Len(t, data.Items, 2)
+	require.Equal(t, "A", data.Items[0].Upper)
+	require.Equal(t, "B", data.Items[1].Upper)
+	require.Equal(t, []string{"before", "after"}, data.Items[0].Calls)
+
+	var invalid HookData
+	err = p.Parse(&invalid, `<ul><li>a</li><li> </li></ul>`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "name is empty")
+}
+
+func TestParse_OnField(t *testing.T) {
+	type Item struct {
+		Name  string  `pagser:"h2"`
+		Price float64 `pagser:".price"`
+		Total float64 `pagser:"=Price * 2"`
+	}
+	type ListData struct {
+		Title string `pagser:"h1,required"`
+		Items []Item `pagser:".item"`
+	}
+
+	type fieldCall struct {
+		path     string
+		selector string
+		matched  int
+		value    interface{}
+		hasErr   bool
+	}
+	calls := make([]fieldCall, 0)
+	cfg := DefaultConfig()
+	cfg.OnField = func(path string, selector string, matched int, value interface{}, err error) {
+		calls = append(calls, fieldCall{path, selector, matched, value, err != nil})
+	}
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+
+	var data ListData
+	err = p.Parse(&data, `<h1>List</h1><div class="item"><h2>A</h2><span class="price">1.5</span></div>`)
+	require.NoError(t, err)
+	require.Equal(t, []fieldCall{
+		{"Title", "h1", 1, "List", false},
+		{"Items[0].Name", "h2", 1, "A", false},
+		{"Items[0].Price", ".price", 1, 1.5, false},
+		{"Items[0].Total", "=Price * 2", 0, 3.0, false},
+		{"Items", ".item", 1, data.Items, false},
+	}, calls)
+
+	calls = calls[:0]
+	err = p.Parse(&data, `<div></div>`)
+	require.Error(t, err)
+	require.Equal(t, []fieldCall{{"Title", "h1", 0, "List", true}}, calls)
+}
+
+func TestParse_SliceWorkers(t *testing.T) {
+	type item struct {
+		Name  string `pagser:"h2"`
+		Price int    `pagser:".price"`
+	}
+	type listData struct {
+		Items []item `pagser:".item"`
+	}
+	var html strings.Builder
+	want := make([]item, 0, 500)
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&html, `<div class="item"><h2>Item %v</h2><span class="price">%v</span></div>`, i, i)
+		want = append(want, item{Name: fmt.Sprintf("Item %v", i), Price: i})
+	}
+
+	cfg := DefaultConfig()
+	cfg.SliceWorkers = 8
+	p, err := NewWithConfig(cfg)
+	require.NoError(t, err)
+	var data listData
+	require.NoError(t, p.Parse(&data, html.String()))
+	require.Equal(t, want, data.Items)
+
+	// the first failed item is returned
+	type strictData struct {
+		Items []struct {
+			Name string `pagser:"h2,required"`
+		} `pagser:".item"`
+	}
+	var strict strictData
+	err = p.Parse(&strict, html.String()+`<div class="item"></div><div class="item"></div>`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Items[500].Name")
+
+	// the errors of all failed items are collected in order
+	cfg.CollectErrors = true
+	p, err = NewWithConfig(cfg)
+	require.NoError(t, err)
+	err = p.Parse(&strict, `<div class="item"></div><div class="item"><h2>A</h2></div><div class="item"></div>`)
+	require.Error(t, err)
+	message := err.Error()
+	require.Contains(t, message, "Items[0].Name")
+	require.Contains(t, message, "Items[2].Name")
+	require.True(t, strings.Index(message, "Items[0]") < strings.Index(message, "Items[2]"), message)
+	require.Equal(t, "A", strict.Items[1].Name)
+}
@@ -0,0 +1,71 @@
+package pagser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/spf13/cast"
+	"golang.org/x/net/html"
+)
+
+// valueNodeName the element name of the value nodes in pipeline
+const valueNodeName = "pagser-value"
+
+// valueSelection converts the output value of function to a selection for the next function in pipeline,
+// each item of slice value is an element, other value is a single element, and the text of element is the value.
+// The error is returned if the value can not be cast to the text, eg: the map and struct values.
+func valueSelection(value interface{}) (*goquery.Selection, error) {
+	if sel, ok := value.(*goquery.Selection); ok {
+		return sel, nil
+	}
+	nodes := make([]*html.Node, 0)
+	rv := reflect.ValueOf(value)
+	if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < rv.Len(); i++ {
+			node, err := newValueNode(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("item %v error: %w", i, err)
+			}
+			nodes = append(nodes, node)
+		}
+	} else {
+		node, err := newValueNode(value)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return &goquery.Selection{Nodes: nodes}, nil
+}
+
+// newValueNode returns the value node whose text is the value, the bytes such as json.RawMessage are the text as is
+func newValueNode(value interface{}) (*html.Node, error) {
+	var text string
+	switch v := value.(type) {
+	case json.RawMessage:
+		text = string(v)
+	case []byte:
+		text = string(v)
+	default:
+		var err error
+		text, err = cast.ToStringE(value)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline value can not be passed to the next function: %w", err)
+		}
+	}
+	node := &html.Node{Type: html.ElementNode, Data: valueNodeName}
+	node.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+	return node, nil
+}
+
+// isValueSelection reports whether the selection is converted from values of pipeline
+func isValueSelection(sel *goquery.Selection) bool {
+	for _, node := range sel.Nodes {
+		if node.Type != html.ElementNode || node.Data != valueNodeName || node.Parent != nil {
+			return false
+		}
+	}
+	return len(sel.Nodes) > 0
+}
@@ -0,0 +1,170 @@
+package pagser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// structPlan the compiled fields of struct type in parse order, it is immutable after compiled
+type structPlan struct {
+	fields []*fieldPlan
+}
+
+// fieldPlan the compiled field of struct
+type fieldPlan struct {
+	index    int
+	name     string
+	tagValue string
+	tag      *tagTokenizer // nil for the embedded struct without tag
+	layout   string        // time layout of `layout` tag
+	embedded bool          // embedded struct without tag, parsed against the selection of struct
+	funcs    []funcPlan    // resolved functions of tag.Funcs
+	setText  textSetter    // the setter of the text of field, nil if the field is set by setFieldValue
+}
+
+// textSetter sets the text to the field of the builtin kind like setFieldValue without boxing the values,
+// returns false if the text can not be set, eg: the invalid number, then it is set by setFieldValue
+type textSetter func(fieldValue reflect.Value, text string) bool
+
+// funcPlan the resolved function of tag, the function is resolved at runtime if neither method nor call is set
+type funcPlan struct {
+	method int         // index of the method of struct pointer, -1 if the struct has no method of the name
+	call   interface{} // the registered CallFunc or baseUrlFunc, set only if no struct of Schema has the method of the name
+}
+
+// structPlanOf returns the cached structPlan of struct type, or compiles and caches it
+func (p *Pagser) structPlanOf(t reflect.Type) (*structPlan, error) {
+	if cache, ok := p.mapPlans.Load(t); ok {
+		return cache.(*structPlan), nil
+	}
+	plan, err := p.compileStructPlan(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.mapPlans.Store(t, plan)
+	return plan, nil
+}
+
+// compileStructPlan compiles the fields of struct type, the functions which are not the methods of struct are
+// resolved to the registered functions if resolveCall reports true for them, eg: no struct of Schema has the method
+func (p *Pagser) compileStructPlan(t reflect.Type, resolveCall func(name string) bool) (*structPlan, error) {
+	order, err := p.fieldOrder(t)
+	if err != nil {
+		return nil, err
+	}
+	ptrType := reflect.PtrTo(t)
+	plan := &structPlan{fields: make([]*fieldPlan, 0, len(order))}
+	for _, i := range order {
+		fieldType := t.Field(i)
+		tagValue, tagOk := fieldType.Tag.Lookup(p.Config.TagName)
+		if !tagOk {
+			if fieldType.Anonymous && indirectPtrType(fieldType.Type).Kind() == reflect.Struct &&
+				(fieldType.Type.Kind() == reflect.Struct || fieldType.Type.Kind() == reflect.Ptr) {
+				plan.fields = append(plan.fields, &fieldPlan{index: i, name: fieldType.Name, embedded: true})
+			} else if p.Config.Debug {
+				fmt.Printf("[INFO] not found tag name=[%v] in field: %v, eg: `%v:\".navlink a->attr(href)\"`\n",
+					p.Config.TagName, fieldType.Name, p.Config.TagName)
+			}
+			continue
+		}
+		if tagValue == ignoreSymbol {
+			continue
+		}
+		tag, err := p.loadTag(tagValue)
+		if err != nil {
+			return nil, err
+		}
+		field := &fieldPlan{
+			index:    i,
+			name:     fieldType.Name,
+			tagValue: tagValue,
+			tag:      tag,
+			layout:   fieldType.Tag.Get(layoutTagName),
+			setText:  p.textSetterOf(fieldType.Type),
+		}
+		for _, fn := range tag.Funcs {
+			resolved := funcPlan{method: -1}
+			if method, ok := ptrType.MethodByName(fn.Name); ok {
+				resolved.method = method.Index
+			} else if resolveCall != nil && resolveCall(fn.Name) {
+				if f, ok := p.mapFuncs.Load(fn.Name); ok {
+					resolved.call = f
+				}
+			}
+			field.funcs = append(field.funcs, resolved)
+		}
+		plan.fields = append(plan.fields, field)
+	}
+	return plan, nil
+}
+
+// textSetterOf returns the textSetter of the string, bool and number types, nil for the other types and the types
+// which are set by the registered converter, encoding.TextUnmarshaler or Unmarshaler
+func (p *Pagser) textSetterOf(t reflect.Type) textSetter {
+	if t == durationType || p.hasTypeConverter(t) || isTextUnmarshaler(t) || reflect.PtrTo(t).Implements(unmarshalerType) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(fieldValue reflect.Value, text string) bool {
+			fieldValue.SetString(text)
+			return true
+		}
+	case reflect.Bool:
+		return func(fieldValue reflect.Value, text string) bool {
+			b, err := strconv.ParseBool(text)
+			if err != nil {
+				return false
+			}
+			fieldValue.SetBool(b)
+			return true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(fieldValue reflect.Value, text string) bool {
+			// the decimals such as `1.0` are cast by setFieldValue
+			n, err := strconv.ParseInt(text, 0, 0)
+			if err != nil {
+				return false
+			}
+			fieldValue.SetInt(n)
+			return true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(fieldValue reflect.Value, text string) bool {
+			n, err := strconv.ParseInt(text, 0, 0)
+			if err != nil || n < 0 {
+				return false
+			}
+			fieldValue.SetUint(uint64(n))
+			return true
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(fieldValue reflect.Value, text string) bool {
+			f, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return false
+			}
+			fieldValue.SetFloat(f)
+			return true
+		}
+	}
+	return nil
+}
+
+// execFunc calls the resolved function of tag, the unresolved function is found by findAndExecFunc
+func (p *Pagser) execFunc(val reflect.Value, stackValues []reflect.Value, resolved funcPlan, fn *tagFunc, node *goquery.Selection, parse *parseBase) (interface{}, error) {
+	if resolved.method >= 0 && val.CanAddr() {
+		return execMethod(val.Addr().Method(resolved.method), fn, node)
+	}
+	if resolved.call != nil {
+		outValue, err := callFunc(resolved.call, parse, node, fn.Params...)
+		if err != nil {
+			return nil, fmt.Errorf("call registered func %v error: %v", fn.Name, err)
+		}
+		return outValue, nil
+	}
+	return p.findAndExecFunc(val, stackValues, fn, node, parse)
+}
@@ -0,0 +1,64 @@
+package pagser
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var priceType = reflect.TypeOf(Price{})
+
+// Price the amount and currency extracted by price() function
+type Price struct {
+	Amount   float64
+	Currency string // ISO 4217 currency code, eg: `USD`, empty if not found
+}
+
+// currencySymbols the currency symbols to ISO 4217 codes, the longer symbols are matched first
+var currencySymbols = []struct {
+	symbol string
+	code   string
+}{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"AU$", "AUD"}, {"NZ$", "NZD"}, {"HK$", "HKD"}, {"NT$", "TWD"},
+	{"S$", "SGD"}, {"R$", "BRL"}, {"C$", "CAD"}, {"A$", "AUD"}, {"MX$", "MXN"}, {"zł", "PLN"}, {"Kč", "CZK"},
+	{"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"￥", "CNY"}, {"元", "CNY"}, {"₹", "INR"}, {"₩", "KRW"},
+	{"₽", "RUB"}, {"₺", "TRY"}, {"₫", "VND"}, {"₱", "PHP"}, {"฿", "THB"}, {"₪", "ILS"}, {"₴", "UAH"},
+	{"₦", "NGN"}, {"$", "USD"},
+}
+
+// rxCurrencyCode matches the ISO 4217 currency code
+var rxCurrencyCode = regexp.MustCompile(`\b(USD|EUR|GBP|JPY|CNY|RMB|INR|KRW|RUB|TRY|BRL|CAD|AUD|NZD|HKD|TWD|SGD|MXN|CHF|SEK|NOK|DKK|PLN|CZK|HUF|ZAR|THB|VND|PHP|IDR|MYR|ILS|AED|SAR|UAH|NGN)\b`)
+
+// parsePrice parses the amount and currency from text like `£1,299.00`, `USD 49.99`, `1.234,56 €`
+func parsePrice(text string, locale string) (Price, error) {
+	amount, err := parseLocaleNumber(text, locale)
+	if err != nil {
+		return Price{}, err
+	}
+	return Price{Amount: amount, Currency: priceCurrency(text)}, nil
+}
+
+// priceCurrency returns the currency code of the code or symbol in text, empty if not found
+func priceCurrency(text string) string {
+	if code := rxCurrencyCode.FindString(strings.ToUpper(text)); code != "" {
+		if code == "RMB" {
+			return "CNY"
+		}
+		return code
+	}
+	for _, currency := range currencySymbols {
+		if strings.Contains(text, currency.symbol) {
+			return currency.code
+		}
+	}
+	return ""
+}
+
+// priceFields returns the fields of price for the custom struct
+func (price Price) priceFields() map[string]string {
+	return map[string]string{
+		"amount":   strconv.FormatFloat(price.Amount, 'f', -1, 64),
+		"currency": price.Currency,
+	}
+}
@@ -0,0 +1,36 @@
+package pagser
+
+import (
+	"testing"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		text   string
+		locale string
+		want   Price
+	}{
+		{"£1,299.00", "", Price{1299, "GBP"}},
+		{"USD 49.99", "", Price{49.99, "USD"}},
+		{"$ 19", "", Price{19, "USD"}},
+		{"R$ 1.234,56", "pt-BR", Price{1234.56, "BRL"}},
+		{"1.234,56 €", "de", Price{1234.56, "EUR"}},
+		{"49,90 eur", "", Price{49.9, "EUR"}},
+		{"¥1200", "", Price{1200, "JPY"}},
+		{"HK$88", "", Price{88, "HKD"}},
+		{"Price: 15.5", "", Price{15.5, ""}},
+	}
+	for _, tt := range tests {
+		got, err := parsePrice(tt.text, tt.locale)
+		if err != nil {
+			t.Fatalf("parse `%v` error: %v", tt.text, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parse `%v` want %v, but got %v", tt.text, tt.want, got)
+		}
+	}
+
+	if _, err := parsePrice("free", ""); err == nil {
+		t.Fatal("parse `free` want an error")
+	}
+}
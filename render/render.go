@@ -0,0 +1,172 @@
+// Package render renders the JavaScript-heavy pages by a headless browser through chromedp and parses the rendered
+// DOM by pagser, the Chrome or Chromium must be installed, eg:
+//
+//	var data PageData
+//	err := render.RenderAndParse(ctx, "https://example.com", &data, render.WithWaitVisible("div.items"))
+//
+// The browser is started and closed for each call, the browser of ctx is reused if ctx is created by
+// chromedp.NewContext, so the pages are rendered in the new tabs of the same browser.
+package render
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/chromedp"
+	"github.com/foolin/pagser"
+)
+
+// DefaultTimeout the default timeout of rendering the page
+const DefaultTimeout = 60 * time.Second
+
+// Option configure the rendering of RenderAndParse and Render
+type Option func(opts *options)
+
+type options struct {
+	pagser       *pagser.Pagser
+	timeout      time.Duration
+	waitSelector string // the selector of element waited for, default is `body` ready
+	waitVisible  bool
+	sleep        time.Duration
+	actions      []chromedp.Action
+	allocator    []chromedp.ExecAllocatorOption
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		timeout:      DefaultTimeout,
+		waitSelector: "body",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.pagser == nil {
+		o.pagser = pagser.New()
+	}
+	return o
+}
+
+// WithPagser set the pagser to parse the rendered page, default is `pagser.New()`
+func WithPagser(p *pagser.Pagser) Option {
+	return func(opts *options) {
+		opts.pagser = p
+	}
+}
+
+// WithTimeout set the timeout of rendering the page, including starting the browser, default is DefaultTimeout,
+// 0 is no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = timeout
+	}
+}
+
+// WithWaitReady waits until the element of selector is ready before the page is parsed, default is `body`
+func WithWaitReady(selector string) Option {
+	return func(opts *options) {
+		opts.waitSelector = selector
+		opts.waitVisible = false
+	}
+}
+
+// WithWaitVisible waits until the element of selector is visible before the page is parsed,
+// eg: the list which is loaded by the scripts
+func WithWaitVisible(selector string) Option {
+	return func(opts *options) {
+		opts.waitSelector = selector
+		opts.waitVisible = true
+	}
+}
+
+// WithSleep sleeps for the duration after waiting for the element, eg: the page which loads the content lazily
+func WithSleep(d time.Duration) Option {
+	return func(opts *options) {
+		opts.sleep = d
+	}
+}
+
+// WithActions add the chromedp actions run after waiting for the element and before the page is parsed,
+// eg: click the button to load more items
+//
+//	render.WithActions(chromedp.Click("button.more"), chromedp.WaitVisible("div.item:nth-child(20)"))
+func WithActions(actions ...chromedp.Action) Option {
+	return func(opts *options) {
+		opts.actions = append(opts.actions, actions...)
+	}
+}
+
+// WithAllocatorOptions add the options of starting the browser to chromedp.DefaultExecAllocatorOptions,
+// eg: chromedp.ExecPath or chromedp.ProxyServer, they are ignored if the browser of ctx is reused
+func WithAllocatorOptions(allocatorOpts ...chromedp.ExecAllocatorOption) Option {
+	return func(opts *options) {
+		opts.allocator = append(opts.allocator, allocatorOpts...)
+	}
+}
+
+// WithUserAgent set the `User-Agent` of the browser, it is ignored if the browser of ctx is reused
+func WithUserAgent(userAgent string) Option {
+	return WithAllocatorOptions(chromedp.UserAgent(userAgent))
+}
+
+// RenderAndParse renders the page of url by the headless browser and parses the rendered DOM to v by ParseDocument,
+// the relative urls are resolved against the final url of page
+func RenderAndParse(ctx context.Context, url string, v interface{}, opts ...Option) error {
+	o := newOptions(opts...)
+	html, location, err := render(ctx, url, o)
+	if err != nil {
+		return err
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return err
+	}
+	doc.Url = location
+	return o.pagser.ParseDocumentContext(ctx, v, doc)
+}
+
+// Render renders the page of url by the headless browser and returns the rendered html
+func Render(ctx context.Context, url string, opts ...Option) (string, error) {
+	html, _, err := render(ctx, url, newOptions(opts...))
+	return html, err
+}
+
+// render renders the page of url and returns the html of the rendered DOM and the final url
+func render(ctx context.Context, rawURL string, o *options) (string, *url.URL, error) {
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	if chromedp.FromContext(ctx) == nil {
+		allocatorOpts := append(chromedp.DefaultExecAllocatorOptions[:], o.allocator...)
+		var cancel context.CancelFunc
+		ctx, cancel = chromedp.NewExecAllocator(ctx, allocatorOpts...)
+		defer cancel()
+	}
+	ctx, cancel := chromedp.NewContext(ctx)
+	defer cancel()
+
+	var html, location string
+	actions := []chromedp.Action{chromedp.Navigate(rawURL)}
+	if o.waitVisible {
+		actions = append(actions, chromedp.WaitVisible(o.waitSelector, chromedp.ByQuery))
+	} else {
+		actions = append(actions, chromedp.WaitReady(o.waitSelector, chromedp.ByQuery))
+	}
+	if o.sleep > 0 {
+		actions = append(actions, chromedp.Sleep(o.sleep))
+	}
+	actions = append(actions, o.actions...)
+	actions = append(actions, chromedp.Location(&location), chromedp.OuterHTML("html", &html, chromedp.ByQuery))
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		return "", nil, err
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", nil, err
+	}
+	return html, u, nil
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/foolin/pagser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+	o := newOptions()
+	require.NotNil(t, o.pagser)
+	require.Equal(t, DefaultTimeout, o.timeout)
+	require.Equal(t, "body", o.waitSelector)
+	require.False(t, o.waitVisible)
+
+	p := pagser.New()
+	o = newOptions(WithPagser(p), WithTimeout(time.Second), WithWaitVisible("ul"), WithSleep(time.Millisecond),
+		WithActions(chromedp.Sleep(time.Millisecond)), WithUserAgent("pagser"))
+	require.Equal(t, p, o.pagser)
+	require.Equal(t, time.Second, o.timeout)
+	require.Equal(t, "ul", o.waitSelector)
+	require.True(t, o.waitVisible)
+	require.Equal(t, time.Millisecond, o.sleep)
+	require.Len(t, o.actions, 1)
+	require.Len(t, o.allocator, 1)
+
+	o = newOptions(WithWaitVisible("ul"), WithWaitReady("li"))
+	require.Equal(t, "li", o.waitSelector)
+	require.False(t, o.waitVisible)
+}
+
+// the rendering is tested only if the Chrome or Chromium is installed
+func lookBrowser(t *testing.T) {
+	for _, name := range []string{"headless-shell", "headless_shell", "chromium", "chromium-browser",
+		"google-chrome", "google-chrome-stable", "chrome"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return
+		}
+	}
+	t.Skip("chrome is not installed")
+}
+
+func TestRenderAndParse(t *testing.T) {
+	lookBrowser(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><ul></ul><script>
+setTimeout(function() {
+	document.querySelector("ul").innerHTML = '<li><a href="/a">A</a></li><li><a href="/b">B</a></li>';
+}, 100);
+</script></body></html>`))
+	}))
+	defer server.Close()
+
+	type item struct {
+		Name string `pagser:"a"`
+		Link string `pagser:"a->absHref()"`
+	}
+	var data struct {
+		Items []item `pagser:"li"`
+	}
+	err := RenderAndParse(context.Background(), server.URL+"/page", &data, WithWaitVisible("li"))
+	require.NoError(t, err)
+	require.Equal(t, []item{{Name: "A", Link: server.URL + "/a"}, {Name: "B", Link: server.URL + "/b"}}, data.Items)
+
+	html, err := Render(context.Background(), server.URL, WithWaitVisible("li"))
+	require.NoError(t, err)
+	require.Contains(t, html, `<li><a href="/a">A</a></li>`)
+}
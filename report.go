@@ -0,0 +1,78 @@
+package pagser
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Report the diagnostics of ParseWithReport, lists the parsed fields to maintain the tags against the changing page
+type Report struct {
+	Fields   []FieldReport // the parsed fields in parse order, the nested fields follow the field of struct
+	Duration time.Duration // time spent of parsing the document
+	mu       sync.Mutex
+}
+
+// FieldReport the diagnostics of field
+type FieldReport struct {
+	Path     string        // field path from the root struct, eg: `NavList[2].Link.Url`
+	Selector string        // selector of tag, the expression of computed field, eg: `=Price * Quantity`
+	Matched  int           // count of the nodes matched by the selector
+	Default  bool          // whether the default value is applied
+	Computed bool          // whether the field is computed from the other fields
+	Duration time.Duration // time spent of parsing the field, including the nested fields
+	Err      error         // the error of field
+}
+
+// Missed returns the fields whose selector matches nothing, except the computed fields
+func (r *Report) Missed() []FieldReport {
+	missed := make([]FieldReport, 0)
+	for _, field := range r.Fields {
+		if field.Matched == 0 && !field.Computed {
+			missed = append(missed, field)
+		}
+	}
+	return missed
+}
+
+// begin reserves the report of field before it is parsed, so the fields are listed in parse order
+func (r *Report) begin(path string, selector string, computed bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Fields = append(r.Fields, FieldReport{Path: path, Selector: selector, Computed: computed})
+	return len(r.Fields) - 1
+}
+
+// end fills the report of field after it is parsed
+func (r *Report) end(index int, matched int, isDefault bool, started time.Time, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	field := &r.Fields[index]
+	field.Matched = matched
+	field.Default = isDefault
+	field.Duration = time.Since(started)
+	field.Err = err
+}
+
+// reportKey context key of the Report of ParseWithReport
+type reportKey struct{}
+
+// reportOf returns the Report of ctx, nil if the parse is not reported
+func reportOf(ctx context.Context) *Report {
+	report, _ := ctx.Value(reportKey{}).(*Report)
+	return report
+}
+
+// ParseWithReport parse html to struct and returns the Report of fields, the report is returned even if parse fails
+func (p *Pagser) ParseWithReport(v interface{}, document string) (*Report, error) {
+	return p.ParseContextWithReport(context.Background(), v, document)
+}
+
+// ParseContextWithReport parse html to struct and returns the Report of fields, parse will be aborted when the ctx is done
+func (p *Pagser) ParseContextWithReport(ctx context.Context, v interface{}, document string) (*Report, error) {
+	report := &Report{}
+	started := time.Now()
+	err := p.ParseContext(context.WithValue(ctx, reportKey{}, report), v, document)
+	report.Duration = time.Since(started)
+	return report, err
+}
@@ -0,0 +1,46 @@
+package pagser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithReport(t *testing.T) {
+	type NavItem struct {
+		Name string `pagser:"span"`
+		Icon string `pagser:"i->attr(class)"`
+	}
+	type ReportData struct {
+		Title   string    `pagser:"h1"`
+		Price   float64   `pagser:".price,default=9.5"`
+		NavList []NavItem `pagser:".nav li"`
+		Label   string    `pagser:"=Title + '!'"`
+	}
+
+	p := New()
+
+	var data ReportData
+	report, err := p.ParseWithReport(&data, `<h1>Nav</h1><ul class="nav"><li><span>A</span></li><li><span>B</span></li></ul>`)
+	require.NoError(t, err)
+	require.Equal(t, "Nav!", data.Label)
+
+	var paths []string
+	for _, field := range report.Fields {
+		paths = append(paths, field.Path)
+	}
+	require.Equal(t, []string{"Title", "Price", "NavList", "NavList[0].Name", "NavList[0].Icon",
+		"NavList[1].Name", "NavList[1].Icon", "Label"}, paths)
+	require.Equal(t, "h1", report.Fields[0].Selector)
+	require.Equal(t, 1, report.Fields[0].Matched)
+	require.True(t, report.Fields[1].Default)
+	require.Equal(t, 2, report.Fields[2].Matched)
+	require.True(t, report.Fields[7].Computed)
+	require.Equal(t, "=Title + '!'", report.Fields[7].Selector)
+
+	var missed []string
+	for _, field := range report.Missed() {
+		missed = append(missed, field.Path)
+	}
+	require.Equal(t, []string{"Price", "NavList[0].Icon", "NavList[1].Icon"}, missed)
+}
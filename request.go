@@ -0,0 +1,124 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RequestOption configure the http request used by ParseURL
+type RequestOption func(opts *requestOptions)
+
+type requestOptions struct {
+	ctx      context.Context
+	client   *http.Client
+	header   http.Header
+	timeout  time.Duration
+	maxPages int // max pages of ParsePaged, 0 is unlimited
+
+	detailConcurrency int // max concurrent requests of ParseListDetail
+}
+
+func newRequestOptions(opts ...RequestOption) *requestOptions {
+	ro := &requestOptions{
+		ctx:    context.Background(),
+		client: http.DefaultClient,
+		header: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(ro)
+	}
+	return ro
+}
+
+// WithContext set the context of the request
+func WithContext(ctx context.Context) RequestOption {
+	return func(opts *requestOptions) {
+		opts.ctx = ctx
+	}
+}
+
+// WithHttpClient set the http client used to send the request, default is `http.DefaultClient`
+func WithHttpClient(client *http.Client) RequestOption {
+	return func(opts *requestOptions) {
+		opts.client = client
+	}
+}
+
+// WithHeader add a header to the request
+func WithHeader(key, value string) RequestOption {
+	return func(opts *requestOptions) {
+		opts.header.Add(key, value)
+	}
+}
+
+// WithUserAgent set the `User-Agent` header of the request
+func WithUserAgent(userAgent string) RequestOption {
+	return func(opts *requestOptions) {
+		opts.header.Set("User-Agent", userAgent)
+	}
+}
+
+// WithTimeout set the timeout of the whole request, including reading the body
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(opts *requestOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// ParseURL fetch the page of url and parse it to struct, the relative urls of url.URL fields, absHref() and links()
+// are resolved against the final url of response, the page is decompressed and decoded to UTF-8 like ParseResponse.
+//	var data PageData
+//	err := p.ParseURL(&data, "https://example.com", pagser.WithUserAgent("pagser"), pagser.WithTimeout(10*time.Second))
+func (p *Pagser) ParseURL(v interface{}, url string, opts ...RequestOption) error {
+	ro := newRequestOptions(opts...)
+
+	ctx := ro.ctx
+	if ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+	}
+
+	doc, err := p.fetchDocument(ctx, ro, url)
+	if err != nil {
+		return err
+	}
+	return p.ParseDocumentContext(ctx, v, doc)
+}
+
+// fetchDocument fetch the page of url and return the document with the final url of response
+func (p *Pagser) fetchDocument(ctx context.Context, ro *requestOptions, url string) (*goquery.Document, error) {
+	res, err := ro.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return p.responseDocument(res)
+}
+
+// get send the GET request of url with the headers of options, the response is returned only if its status is 2xx
+func (ro *requestOptions) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range ro.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	res, err := ro.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		res.Body.Close()
+		return nil, fmt.Errorf("request %v error: unexpected status %v", url, res.Status)
+	}
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package pagser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagser_ParseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "pagser-test", r.Header.Get("User-Agent"))
+		require.Equal(t, "yes", r.Header.Get("X-Test"))
+		_, _ = w.Write([]byte(rawPagserHtml))
+	}))
+	defer server.Close()
+
+	p := New()
+
+	var data PagserData
+	err := p.ParseURL(&data, server.URL, WithUserAgent("pagser-test"), WithHeader("X-Test", "yes"))
+	require.NoError(t, err)
+	require.Equal(t, "Pagser Example", data.Title)
+	require.Len(t, data.Navs, 4)
+}
+
+func TestPagser_ParseURL_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := New()
+
+	var data PagserData
+	err := p.ParseURL(&data, server.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+}
+
+func TestPagser_ParseURL_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	p := New()
+
+	var data PagserData
+	err := p.ParseURL(&data, server.URL, WithTimeout(10*time.Millisecond))
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = p.ParseURL(&data, server.URL, WithContext(ctx))
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestPagser_ParseURL_ResolveUrls(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/list/page", http.StatusFound)
+	})
+	mux.HandleFunc("/list/page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<a class="next" href="?page=2">Next</a><a class="home" href="/">Home</a>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	type UrlData struct {
+		Next     string  `pagser:".next->absHref()"`
+		Home     url.URL `pagser:".home->attr(href)"`
+		Explicit string  `pagser:".home->absHref('https://example.com/')"`
+	}
+
+	p := New()
+
+	var data UrlData
+	err := p.ParseURL(&data, server.URL+"/old/")
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/list/page?page=2", data.Next)
+	require.Equal(t, server.URL+"/", data.Home.String())
+	require.Equal(t, "https://example.com/", data.Explicit)
+
+	// the document url is not kept after parsing
+	err = p.Parse(&data, `<a class="next" href="?page=2">Next</a><a class="home" href="/">Home</a>`)
+	require.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package pagser
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/brotli"
+)
+
+// ParseResponse parse the body of response to struct, the body is decompressed by the `Content-Encoding` header,
+// including `gzip`, `deflate` and `br`, and decoded to UTF-8 by the charset of `Content-Type` header, the relative urls
+// are resolved against the url of the request of response, the RSS, Atom or other xml `Content-Type` is parsed like
+// ParseXML. The status is not checked and the body is not closed.
+//	res, err := http.Get("https://example.com")
+//	defer res.Body.Close()
+//	err = p.ParseResponse(&data, res)
+func (p *Pagser) ParseResponse(v interface{}, res *http.Response) error {
+	return p.ParseResponseContext(context.Background(), v, res)
+}
+
+// ParseResponseContext parse the body of response to struct like ParseResponse, parse will be aborted when the ctx is done
+func (p *Pagser) ParseResponseContext(ctx context.Context, v interface{}, res *http.Response) error {
+	doc, err := p.responseDocument(res)
+	if err != nil {
+		return err
+	}
+	return p.ParseDocumentContext(ctx, v, doc)
+}
+
+// responseDocument returns the document of the decompressed and decoded body of response with the url of its request,
+// the body of xml `Content-Type`, such as `application/rss+xml`, is parsed as xml like ParseXML
+func (p *Pagser) responseDocument(res *http.Response) (*goquery.Document, error) {
+	body, err := decompressBody(res.Body, res.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	var doc *goquery.Document
+	if contentType := res.Header.Get("Content-Type"); isXMLContentType(contentType) {
+		doc, err = p.newXMLDocumentFromReader(body)
+	} else {
+		doc, err = p.newDocumentFromReader(body, contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Resolve the relative urls of document against the final url of response
+	if res.Request != nil {
+		doc.Url = res.Request.URL
+	}
+	return doc, nil
+}
+
+// decompressBody returns the reader of the body decompressed by the encodings of `Content-Encoding` header,
+// they are applied in order, so the body is decompressed in reverse order, eg: `gzip, br`
+func decompressBody(body io.Reader, contentEncoding string) (io.Reader, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch encoding := strings.ToLower(strings.TrimSpace(encodings[i])); encoding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			body, err = gzip.NewReader(body)
+		case "deflate":
+			body, err = newDeflateReader(body)
+		case "br":
+			body = brotli.NewReader(body)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding `%v`", encoding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("content encoding `%v` error: %w", encodings[i], err)
+		}
+	}
+	return body, nil
+}
+
+// newDeflateReader returns the reader of the `deflate` body, it is the zlib format, but some servers send
+// the raw deflate data without the zlib header
+func newDeflateReader(body io.Reader) (io.Reader, error) {
+	reader := bufio.NewReader(body)
+	header, err := reader.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// the zlib header: the deflate method and the check bits of the 16-bit header
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(reader)
+	}
+	return flate.NewReader(reader), nil
+}
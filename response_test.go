@@ -0,0 +1,114 @@
+package pagser
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+type responseData struct {
+	Title string `pagser:"h1"`
+	Link  string `pagser:"a->absHref()"`
+}
+
+func compressBody(t *testing.T, encoding string, data []byte) []byte {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "zlib":
+		writer = zlib.NewWriter(&buf)
+	case "flate":
+		var err error
+		writer, err = flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+	case "br":
+		writer = brotli.NewWriter(&buf)
+	}
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestPagser_ParseResponse(t *testing.T) {
+	document := []byte(`<h1>Title</h1><a href="/next">Next</a>`)
+	tests := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"", document},
+		{"identity", document},
+		{"gzip", compressBody(t, "gzip", document)},
+		{"deflate", compressBody(t, "zlib", document)},
+		{"deflate", compressBody(t, "flate", document)},
+		{"br", compressBody(t, "br", document)},
+		{"gzip, br", compressBody(t, "br", compressBody(t, "gzip", document))},
+	}
+	p := New()
+	reqURL, err := url.Parse("https://example.com/page")
+	require.NoError(t, err)
+	for _, tt := range tests {
+		res := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Encoding": {tt.encoding}},
+			Body:       io.NopCloser(bytes.NewReader(tt.body)),
+			Request:    &http.Request{URL: reqURL},
+		}
+		var data responseData
+		require.NoError(t, p.ParseResponse(&data, res), tt.encoding)
+		require.Equal(t, responseData{Title: "Title", Link: "https://example.com/next"}, data, tt.encoding)
+	}
+
+	res := &http.Response{
+		Header: http.Header{"Content-Encoding": {"compress"}},
+		Body:   io.NopCloser(bytes.NewReader(document)),
+	}
+	var data responseData
+	require.EqualError(t, p.ParseResponse(&data, res), "unsupported content encoding `compress`")
+
+	res = &http.Response{
+		Header: http.Header{"Content-Encoding": {"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader(document)),
+	}
+	require.Error(t, p.ParseResponse(&data, res))
+}
+
+func TestPagser_ParseResponseCharset(t *testing.T) {
+	document, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(`<h1>你好</h1>`))
+	require.NoError(t, err)
+	res := &http.Response{
+		Header: http.Header{
+			"Content-Encoding": {"gzip"},
+			"Content-Type":     {"text/html; charset=gbk"},
+		},
+		Body: io.NopCloser(bytes.NewReader(compressBody(t, "gzip", document))),
+	}
+	var data charsetData
+	require.NoError(t, New().ParseResponse(&data, res))
+	require.Equal(t, "你好", data.Title)
+}
+
+func TestPagser_ParseURLEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "br", r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(compressBody(t, "br", []byte(`<h1>Title</h1><a href="/next">Next</a>`)))
+	}))
+	defer server.Close()
+
+	var data responseData
+	require.NoError(t, New().ParseURL(&data, server.URL, WithHeader("Accept-Encoding", "br")))
+	require.Equal(t, responseData{Title: "Title", Link: server.URL + "/next"}, data)
+}
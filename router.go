@@ -0,0 +1,132 @@
+package pagser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ErrNoRoute the error of the url which matches no route of Router, it can be checked by errors.Is
+var ErrNoRoute = errors.New("no route matches the url")
+
+// Router parses the pages to the struct types of the url patterns, the first registered pattern which matches the
+// url is used, eg: the multi-site scrapers. The routes must be registered before ParseURL is called concurrently.
+//	router := pagser.NewRouter(p)
+//	err := router.Handle("https://shop.example.com/item/*", Item{})
+//	err = router.HandleRegexp(`^https://news\.example\.com/\d+$`, &Article{})
+//	v, err := router.ParseURL("https://shop.example.com/item/123")
+//	switch data := v.(type) {
+//	case *Item:
+//	case *Article:
+//	}
+type Router struct {
+	p      *Pagser
+	routes []*route
+}
+
+// route the url pattern and the schema of its struct type
+type route struct {
+	pattern string
+	re      *regexp.Regexp
+	schema  *Schema
+}
+
+// NewRouter returns the empty router which parses the pages by p
+func NewRouter(p *Pagser) *Router {
+	return &Router{p: p}
+}
+
+// Handle registers the struct type of prototype for the urls which match the glob pattern, the prototype is a struct
+// or a pointer to a struct, it is compiled by Pagser.Compile, so the functions must be registered before.
+// The `*` matches any characters except `/`, the `**` matches any characters and the `?` matches one character
+// except `/`, the pattern must match the whole url, eg: `https://*.example.com/item/*` or `**/item/*`.
+func (r *Router) Handle(pattern string, prototype interface{}) error {
+	return r.handle(pattern, globRegexp(pattern), prototype)
+}
+
+// HandleRegexp registers the struct type of prototype like Handle for the urls which match the regular expression,
+// the expression matches any part of url unless it is anchored by `^` and `$`
+func (r *Router) HandleRegexp(expr string, prototype interface{}) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("route pattern `%v` is invalid: %w", expr, err)
+	}
+	return r.handle(expr, re, prototype)
+}
+
+func (r *Router) handle(pattern string, re *regexp.Regexp, prototype interface{}) error {
+	t := reflect.TypeOf(prototype)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("route `%v` prototype %v is not a struct or a pointer to struct",
+			pattern, reflect.TypeOf(prototype))
+	}
+	schema, err := r.p.Compile(t)
+	if err != nil {
+		return fmt.Errorf("route `%v` error: %w", pattern, err)
+	}
+	r.routes = append(r.routes, &route{pattern: pattern, re: re, schema: schema})
+	return nil
+}
+
+// Match returns the struct type of the first route which matches the url, false if no route matches
+func (r *Router) Match(url string) (reflect.Type, bool) {
+	if rt := r.match(url); rt != nil {
+		return rt.schema.Type(), true
+	}
+	return nil, false
+}
+
+func (r *Router) match(url string) *route {
+	for _, rt := range r.routes {
+		if rt.re.MatchString(url) {
+			return rt
+		}
+	}
+	return nil
+}
+
+// ParseURL fetch the page of url like Pagser.ParseURL and parse it to a new value of the struct type of the route
+// which matches the url, return the pointer to the value, the error wraps ErrNoRoute if no route matches
+func (r *Router) ParseURL(url string, opts ...RequestOption) (interface{}, error) {
+	rt := r.match(url)
+	if rt == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoRoute, url)
+	}
+	ro := newRequestOptions(opts...)
+	doc, err := r.p.fetchPage(ro, url)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.New(rt.schema.Type()).Interface()
+	if err := rt.schema.ParseDocumentContext(ro.ctx, v, doc); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// globRegexp returns the regular expression of the glob pattern of Router.Handle
+func globRegexp(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; {
+		case c == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
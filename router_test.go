@@ -0,0 +1,94 @@
+package pagser
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+type routeItem struct {
+	Name  string  `pagser:"h1"`
+	Price float64 `pagser:".price"`
+	Link  string  `pagser:"a->absHref()"`
+}
+
+type routeArticle struct {
+	Title string `pagser:"h1->Upper()"`
+}
+
+func TestRouter_ParseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<h1>Name</h1><span class="price">1.5</span><a href="/a">a</a>`))
+	}))
+	defer server.Close()
+
+	p := New()
+	p.RegisterFunc("Upper", func(node *goquery.Selection, args ...string) (interface{}, error) {
+		return "UPPER " + node.Text(), nil
+	})
+	router := NewRouter(p)
+	require.NoError(t, router.Handle(server.URL+"/item/*", routeItem{}))
+	require.NoError(t, router.HandleRegexp(`/news/\d+$`, &routeArticle{}))
+	require.NoError(t, router.Handle("**/news/**", routeItem{}))
+
+	v, err := router.ParseURL(server.URL + "/item/1?ref=home")
+	require.NoError(t, err)
+	require.Equal(t, &routeItem{Name: "Name", Price: 1.5, Link: server.URL + "/a"}, v)
+
+	v, err = router.ParseURL(server.URL + "/news/123")
+	require.NoError(t, err)
+	require.Equal(t, &routeArticle{Title: "UPPER Name"}, v)
+
+	// the first registered route wins
+	typ, ok := router.Match(server.URL + "/news/2024/01/post")
+	require.True(t, ok)
+	require.Equal(t, reflect.TypeOf(routeItem{}), typ)
+
+	_, ok = router.Match(server.URL + "/item/1/reviews")
+	require.False(t, ok)
+	_, err = router.ParseURL(server.URL + "/item/1/reviews")
+	require.True(t, errors.Is(err, ErrNoRoute), "%v", err)
+}
+
+func TestRouter_Handle(t *testing.T) {
+	router := NewRouter(New())
+	err := router.Handle("**", "string")
+	require.EqualError(t, err, "route `**` prototype string is not a struct or a pointer to struct")
+	err = router.HandleRegexp("(", routeItem{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "route pattern `(` is invalid")
+	err = router.Handle("**", struct {
+		Title string `pagser:"h1->Missing()"`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "route `**` error")
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		matched bool
+	}{
+		{"https://*.example.com/item/*", "https://shop.example.com/item/1", true},
+		{"https://*.example.com/item/*", "https://shop.example.com/item/1/2", false},
+		{"https://*.example.com/item/*", "https://example.com/item/1", false},
+		{"https://example.com/item/**", "https://example.com/item/1/2", true},
+		{"https://example.com/p?ge", "https://example.com/page", true},
+		{"https://example.com/p?ge", "https://example.com/p/ge", false},
+		{"https://example.com/a+b", "https://example.com/a+b", true},
+		{"https://example.com/a+b", "https://example.com/aab", false},
+		{"https://example.com/café/*", "https://example.com/café/1", true},
+		{"https://example.com/caf?/*", "https://example.com/café/1", true},
+		{"https://例え.jp/**", "https://例え.jp/a/b", true},
+		{"https://example.com/café/*", "https://example.com/cafe/1", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.matched, globRegexp(tt.pattern).MatchString(tt.url), "%v %v", tt.pattern, tt.url)
+	}
+}
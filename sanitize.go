@@ -0,0 +1,118 @@
+package pagser
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// sanitizeDrops the elements dropped with their content
+var sanitizeDrops = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"iframe": true, "object": true, "embed": true, "frame": true, "frameset": true,
+}
+
+// sanitizeTags the basic formatting elements kept by sanitize() function
+var sanitizeTags = []string{
+	"a", "b", "blockquote", "br", "code", "em", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "li",
+	"ol", "p", "pre", "s", "strong", "sub", "sup", "u", "ul",
+}
+
+// sanitizeAttrs the attributes kept in the elements, all other attributes such as `style` and `onclick` are dropped
+var sanitizeAttrs = map[string][]string{
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title", "width", "height"},
+}
+
+// sanitizeVoids the void elements without end tag
+var sanitizeVoids = map[string]bool{
+	"br": true, "hr": true, "img": true,
+}
+
+// stripTags returns the inner html of elements with the tags stripped except the allowed tags,
+// the text is kept and escaped, the elements such as `<script>` and `<style>` are dropped with their content,
+// the comments are dropped, and only the safe attributes and urls of allowed tags are kept.
+func stripTags(node *goquery.Selection, allowed []string) (string, error) {
+	tags := make(map[string]bool)
+	for _, tag := range allowed {
+		name := strings.ToLower(strings.Trim(strings.TrimSpace(tag), "<>/"))
+		if name == "" {
+			return "", fmt.Errorf("invalid tag `%v`", tag)
+		}
+		tags[name] = true
+	}
+	var sb strings.Builder
+	for _, n := range node.Nodes {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			writeStripped(&sb, c, tags)
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// writeStripped writes the node and its descendants with the tags stripped except the allowed tags
+func writeStripped(sb *strings.Builder, n *html.Node, tags map[string]bool) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if sanitizeDrops[n.Data] {
+		return
+	}
+	allowed := tags[n.Data]
+	if allowed {
+		sb.WriteString("<" + n.Data)
+		for _, attr := range n.Attr {
+			if isSanitizeAttr(n.Data, attr) {
+				sb.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+		}
+		sb.WriteString(">")
+		if sanitizeVoids[n.Data] {
+			return
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeStripped(sb, c, tags)
+	}
+	if allowed {
+		sb.WriteString("</" + n.Data + ">")
+	}
+}
+
+// isSanitizeAttr returns true if the attribute of tag is kept, the urls of `href` and `src` must be relative
+// or http, https, mailto url.
+func isSanitizeAttr(tag string, attr html.Attribute) bool {
+	if attr.Namespace != "" {
+		return false
+	}
+	kept := false
+	for _, name := range sanitizeAttrs[tag] {
+		if attr.Key == name {
+			kept = true
+			break
+		}
+	}
+	if !kept {
+		return false
+	}
+	if attr.Key == "href" || attr.Key == "src" {
+		u, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil {
+			return false
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "", "http", "https", "mailto":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package pagser
+
+import (
+	"testing"
+)
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		html    string
+		allowed []string
+		want    string
+	}{
+		{`<div>Hello <b>World</b><script>alert(1)</script></div>`, nil, "Hello World"},
+		{`<div>Tom &amp; <span>Jerry</span><!-- comment --></div>`, nil, "Tom &amp; Jerry"},
+		{`<div><p style="color:red">Hello <b onclick="x()">World</b></p><style>p{}</style></div>`, []string{"p", "b"}, "<p>Hello <b>World</b></p>"},
+		{`<div><a href="/a" onclick="x()">A</a> <a href="javascript:alert(1)">B</a></div>`, []string{"<a>"}, `<a href="/a">A</a> <a>B</a>`},
+		{`<div>Line<br/>Next<img src="/a.png" onerror="x()"></div>`, sanitizeTags, `Line<br>Next<img src="/a.png">`},
+	}
+	for _, tt := range tests {
+		got, err := stripTags(newTewSelection(tt.html), tt.allowed)
+		if err != nil {
+			t.Fatalf("stripTags `%v` error: %v", tt.html, err)
+		}
+		if got != tt.want {
+			t.Fatalf("stripTags `%v` want %q, but got %q", tt.html, tt.want, got)
+		}
+	}
+}
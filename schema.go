@@ -0,0 +1,139 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Schema the compiled struct type, the tags are tokenized, the selectors are compiled and the functions are resolved
+// once, it is immutable and reusable by multiple goroutines, the functions registered after Compile are not used.
+//
+//	schema, err := p.Compile(reflect.TypeOf(PageData{}))
+//	err = schema.Parse(&data, html)
+type Schema struct {
+	p   *Pagser // the copy of Pagser with the compiled plans
+	typ reflect.Type
+}
+
+// Compile compiles the struct type and its nested struct types to Schema, t is a struct or pointer to struct
+func (p *Pagser) Compile(t reflect.Type) (*Schema, error) {
+	if t == nil {
+		return nil, fmt.Errorf("nil is not a struct")
+	}
+	t = indirectPtrType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", t)
+	}
+
+	s := &Schema{p: p.clone(), typ: t}
+	types := s.p.schemaTypes(t, make(map[reflect.Type]bool), nil)
+	if err := s.p.Validate(reflect.New(t).Interface()); err != nil {
+		return nil, err
+	}
+
+	// the functions are resolved to the registered functions only if no struct may be the parent with the method
+	methods := make(map[string]bool)
+	for _, st := range types {
+		ptrType := reflect.PtrTo(st)
+		for i := 0; i < ptrType.NumMethod(); i++ {
+			methods[ptrType.Method(i).Name] = true
+		}
+	}
+	resolveCall := func(name string) bool {
+		return !methods[name]
+	}
+	for _, st := range types {
+		plan, err := s.p.compileStructPlan(st, resolveCall)
+		if err != nil {
+			return nil, err
+		}
+		s.p.mapPlans.Store(st, plan)
+	}
+	return s, nil
+}
+
+// Type returns the struct type of schema
+func (s *Schema) Type() reflect.Type {
+	return s.typ
+}
+
+// Parse parse html to struct by the schema
+func (s *Schema) Parse(v interface{}, document string) error {
+	return s.ParseContext(context.Background(), v, document)
+}
+
+// ParseContext parse html to struct by the schema, parse will be aborted when the ctx is done
+func (s *Schema) ParseContext(ctx context.Context, v interface{}, document string) error {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(document))
+	if err != nil {
+		return err
+	}
+	return s.ParseSelectionContext(ctx, v, doc.Selection)
+}
+
+// ParseDocument parse document to struct by the schema
+func (s *Schema) ParseDocument(v interface{}, document *goquery.Document) error {
+	return s.ParseDocumentContext(context.Background(), v, document)
+}
+
+// ParseDocumentContext parse document to struct by the schema, the relative urls are resolved against the `Url` of
+// document like Pagser.ParseDocumentContext
+func (s *Schema) ParseDocumentContext(ctx context.Context, v interface{}, document *goquery.Document) error {
+	return s.ParseSelectionContext(withDocumentUrl(ctx, document), v, document.Selection)
+}
+
+// ParseSelectionContext parse selection to struct by the schema, parse will be aborted when the ctx is done
+func (s *Schema) ParseSelectionContext(ctx context.Context, v interface{}, selection *goquery.Selection) error {
+	if t := reflect.TypeOf(v); t == nil || t.Kind() != reflect.Ptr || t.Elem() != s.typ {
+		return fmt.Errorf("%v is not the pointer of schema type %v", t, s.typ)
+	}
+	return s.p.ParseSelectionContext(ctx, v, selection)
+}
+
+// clone returns the copy of Pagser with the same config, functions and converters, the caches are not copied
+func (p *Pagser) clone() *Pagser {
+	c := &Pagser{Config: p.Config}
+	p.mapFuncs.Range(func(key, value interface{}) bool {
+		c.mapFuncs.Store(key, value)
+		return true
+	})
+	p.mapConverters.Range(func(key, value interface{}) bool {
+		c.mapConverters.Store(key, value)
+		return true
+	})
+	p.mapImpls.Range(func(key, value interface{}) bool {
+		c.mapImpls.Store(key, value)
+		return true
+	})
+	return c
+}
+
+// schemaTypes returns the struct type and its nested struct types which are parsed by the tagged fields
+func (p *Pagser) schemaTypes(t reflect.Type, visited map[reflect.Type]bool, types []reflect.Type) []reflect.Type {
+	if visited[t] {
+		return types
+	}
+	visited[t] = true
+	types = append(types, t)
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		if tagValue, ok := fieldType.Tag.Lookup(p.Config.TagName); (!ok && !fieldType.Anonymous) || tagValue == ignoreSymbol {
+			continue
+		}
+		elemType := fieldType.Type
+		for elemType.Kind() == reflect.Ptr || elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array || elemType.Kind() == reflect.Map {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Struct && p.isNestedStruct(elemType) {
+			types = p.schemaTypes(elemType, visited, types)
+		}
+		for _, implType := range p.implementationStructs(elemType) {
+			types = p.schemaTypes(implType, visited, types)
+		}
+	}
+	return types
+}
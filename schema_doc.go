@@ -0,0 +1,108 @@
+package pagser
+
+import (
+	"reflect"
+)
+
+// SchemaDoc the description of what the schema extracts, it can be marshaled to JSON or YAML, eg: generate the
+// documentation of scraper or diff the schema versions.
+//
+//	data, err := json.MarshalIndent(schema.Describe(), "", "  ")
+//	data, err := yaml.Marshal(schema.Describe())
+type SchemaDoc struct {
+	Type   string     `json:"type" yaml:"type"`     // struct type of schema, eg: `main.PageData`
+	Fields []FieldDoc `json:"fields" yaml:"fields"` // fields in declaration order, the fields of nested structs follow their parent
+}
+
+// FieldDoc the description of field of SchemaDoc
+type FieldDoc struct {
+	Path      string    `json:"path" yaml:"path"`                               // field path, the items of slice are `Name[]`, the values of map are `Name{}`
+	Type      string    `json:"type" yaml:"type"`                               // target type of field, eg: `[]string`
+	Tag       string    `json:"tag" yaml:"tag"`                                 // struct tag value of field
+	Selectors []string  `json:"selectors,omitempty" yaml:"selectors,omitempty"` // alternative selectors, empty for the selection itself
+	Funcs     []FuncDoc `json:"funcs,omitempty" yaml:"funcs,omitempty"`         // function pipeline
+	Computed  string    `json:"computed,omitempty" yaml:"computed,omitempty"`   // expression of computed field
+	Layout    string    `json:"layout,omitempty" yaml:"layout,omitempty"`       // time layout of `layout` tag
+	Required  bool      `json:"required,omitempty" yaml:"required,omitempty"`
+	Strict    bool      `json:"strict,omitempty" yaml:"strict,omitempty"`
+	OmitEmpty bool      `json:"omitempty,omitempty" yaml:"omitempty,omitempty"`
+	Default   *string   `json:"default,omitempty" yaml:"default,omitempty"`     // nil if the tag has no default value
+	Recursive bool      `json:"recursive,omitempty" yaml:"recursive,omitempty"` // the fields of recursive struct are described by its ancestor only
+}
+
+// FuncDoc the function of FieldDoc
+type FuncDoc struct {
+	Name string   `json:"name" yaml:"name"`
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// Describe returns the description of every field, selector, function and target type of the schema
+func (s *Schema) Describe() SchemaDoc {
+	doc := SchemaDoc{Type: s.typ.String(), Fields: make([]FieldDoc, 0)}
+	s.describeFields(&doc.Fields, s.typ, "", []reflect.Type{s.typ})
+	return doc
+}
+
+// describeFields appends the descriptions of fields of struct type, stackTypes are the ancestor struct types
+func (s *Schema) describeFields(fields *[]FieldDoc, t reflect.Type, parent string, stackTypes []reflect.Type) {
+	p := s.p
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		tagValue, tagOk := fieldType.Tag.Lookup(p.Config.TagName)
+		if !tagOk && fieldType.Anonymous {
+			if embeddedType := indirectPtrType(fieldType.Type); embeddedType.Kind() == reflect.Struct {
+				s.describeFields(fields, embeddedType, parent, stackTypes)
+			}
+			continue
+		}
+		if !tagOk || tagValue == ignoreSymbol {
+			continue
+		}
+		// the tags are validated by Compile
+		tag, _ := p.loadTag(tagValue)
+		field := FieldDoc{
+			Path:   joinFieldPath(parent, fieldType.Name),
+			Type:   fieldType.Type.String(),
+			Tag:    tagValue,
+			Layout: fieldType.Tag.Get(layoutTagName),
+		}
+		if tag.Computed != nil {
+			field.Computed = tag.Computed.Source
+			*fields = append(*fields, field)
+			continue
+		}
+		field.Selectors = tag.Selectors
+		field.Required, field.Strict, field.OmitEmpty = tag.Required, tag.Strict, tag.OmitEmpty
+		if tag.HasDefault {
+			defaultValue := tag.Default
+			field.Default = &defaultValue
+		}
+		for _, fn := range tag.Funcs {
+			field.Funcs = append(field.Funcs, FuncDoc{Name: fn.Name, Args: fn.Params})
+		}
+
+		elemType, elemPath := fieldType.Type, field.Path
+		for elemType.Kind() == reflect.Ptr || elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array || elemType.Kind() == reflect.Map {
+			switch elemType.Kind() {
+			case reflect.Slice, reflect.Array:
+				elemPath += "[]"
+			case reflect.Map:
+				elemPath += "{}"
+			}
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct || !p.isNestedStruct(elemType) {
+			*fields = append(*fields, field)
+			continue
+		}
+		for _, st := range stackTypes {
+			if st == elemType {
+				field.Recursive = true
+			}
+		}
+		*fields = append(*fields, field)
+		if !field.Recursive {
+			s.describeFields(fields, elemType, elemPath, append(stackTypes, elemType))
+		}
+	}
+}
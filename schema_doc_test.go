@@ -0,0 +1,60 @@
+package pagser
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type describeMenu struct {
+	Name     string          `pagser:"> a"`
+	Children []*describeMenu `pagser:"> ul > li"`
+}
+
+type describeData struct {
+	Title string         `pagser:"h1 || h2->text(),required"`
+	Price float64        `pagser:".price->trim('$', ' ')->number(),default=0"`
+	Menus []describeMenu `pagser:".menu > li"`
+	Total float64        `pagser:"=Price * 2"`
+	Skip  string         `pagser:"-"`
+}
+
+func TestSchema_Describe(t *testing.T) {
+	p := New()
+	schema, err := p.Compile(reflect.TypeOf(describeData{}))
+	require.NoError(t, err)
+
+	zero := "0"
+	doc := schema.Describe()
+	require.Equal(t, SchemaDoc{
+		Type: "pagser.describeData",
+		Fields: []FieldDoc{
+			{Path: "Title", Type: "string", Tag: "h1 || h2->text(),required", Selectors: []string{"h1", "h2"},
+				Funcs: []FuncDoc{{Name: "text", Args: []string{}}}, Required: true},
+			{Path: "Price", Type: "float64", Tag: ".price->trim('$', ' ')->number(),default=0", Selectors: []string{".price"},
+				Funcs: []FuncDoc{{Name: "trim", Args: []string{"$", " "}}, {Name: "number", Args: []string{}}}, Default: &zero},
+			{Path: "Menus", Type: "[]pagser.describeMenu", Tag: ".menu > li", Selectors: []string{".menu > li"}},
+			{Path: "Menus[].Name", Type: "string", Tag: "> a", Selectors: []string{"> a"}},
+			{Path: "Menus[].Children", Type: "[]*pagser.describeMenu", Tag: "> ul > li", Selectors: []string{"> ul > li"}, Recursive: true},
+			{Path: "Total", Type: "float64", Tag: "=Price * 2", Computed: "Price * 2"},
+		},
+	}, doc)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var jsonDoc SchemaDoc
+	require.NoError(t, json.Unmarshal(data, &jsonDoc))
+	require.Equal(t, doc.Fields[1].Default, jsonDoc.Fields[1].Default)
+	require.Contains(t, string(data), `"path":"Menus[].Children"`)
+
+	data, err = yaml.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "path: Menus[].Children")
+	var yamlDoc SchemaDoc
+	require.NoError(t, yaml.Unmarshal(data, &yamlDoc))
+	require.Equal(t, len(doc.Fields), len(yamlDoc.Fields))
+	require.Equal(t, doc.Fields[0].Selectors, yamlDoc.Fields[0].Selectors)
+}
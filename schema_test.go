@@ -0,0 +1,106 @@
+package pagser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaItem struct {
+	Name  string `pagser:"span->upper()"`
+	Link  string `pagser:"a->attr(href)"`
+	Label string `pagser:"span->ItemLabel()"`
+}
+
+func (item schemaItem) ItemLabel(node *goquery.Selection, args ...string) (out interface{}, err error) {
+	return "item:" + strings.TrimSpace(node.Text()), nil
+}
+
+type schemaData struct {
+	Title string       `pagser:"h1"`
+	Items []schemaItem `pagser:".nav li"`
+	First *schemaItem  `pagser:".nav li->eq(0)"`
+	Count int          `pagser:".nav li->size()"`
+	Tags  []string     `pagser:".tag"`
+	Extra *schemaItem  `pagser:".missing"`
+}
+
+func TestSchema_Parse(t *testing.T) {
+	const html = `<h1>Title</h1><ul class="nav"><li><span>a</span><a href="/a">A</a></li><li><span>b</span></li></ul><i class="tag">x</i>`
+
+	p := New()
+	p.RegisterFunc("upper", func(node *goquery.Selection, args ...string) (out interface{}, err error) {
+		return strings.ToUpper(node.Text()), nil
+	})
+	schema, err := p.Compile(reflect.TypeOf(&schemaData{}))
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeOf(schemaData{}), schema.Type())
+
+	// the functions registered after Compile are not used by schema
+	p.RegisterFunc("upper", func(node *goquery.Selection, args ...string) (out interface{}, err error) {
+		return "changed", nil
+	})
+
+	var data schemaData
+	require.NoError(t, schema.Parse(&data, html))
+	require.Equal(t, "Title", data.Title)
+	require.Len(t, data.Items, 2)
+	require.Equal(t, schemaItem{Name: "A", Link: "/a", Label: "item:a"}, data.Items[0])
+	require.Equal(t, "B", data.Items[1].Name)
+	require.Equal(t, "item:a", data.First.Label)
+	require.Equal(t, 2, data.Count)
+	require.Equal(t, []string{"x"}, data.Tags)
+
+	// the schema is reusable
+	var again schemaData
+	require.NoError(t, schema.Parse(&again, html))
+	require.Equal(t, data, again)
+
+	require.Error(t, schema.Parse(data, html))
+	var other schemaItem
+	require.Error(t, schema.Parse(&other, html))
+}
+
+func TestSchema_Compile(t *testing.T) {
+	p := New()
+
+	_, err := p.Compile(reflect.TypeOf(""))
+	require.Error(t, err)
+	_, err = p.Compile(nil)
+	require.Error(t, err)
+
+	type BadData struct {
+		Title string `pagser:"h1->unknown()"`
+	}
+	_, err = p.Compile(reflect.TypeOf(BadData{}))
+	require.Error(t, err)
+
+	type BadSelector struct {
+		Title string `pagser:"h1[->text()"`
+	}
+	_, err = p.Compile(reflect.TypeOf(BadSelector{}))
+	require.Error(t, err)
+}
+
+func BenchmarkSchema_Parse(b *testing.B) {
+	const html = `<h1>Title</h1><ul class="nav"><li><span>a</span><a href="/a">A</a></li><li><span>b</span></li></ul><i class="tag">x</i>`
+
+	p := New()
+	p.RegisterFunc("upper", func(node *goquery.Selection, args ...string) (out interface{}, err error) {
+		return strings.ToUpper(node.Text()), nil
+	})
+	schema, err := p.Compile(reflect.TypeOf(schemaData{}))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var data schemaData
+		if err := schema.Parse(&data, html); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
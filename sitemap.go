@@ -0,0 +1,191 @@
+package pagser
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// maxSitemapBytes the max uncompressed size of sitemap by the sitemaps protocol
+const maxSitemapBytes = 50 << 20
+
+// defaultSitemapPriority the default priority of the url of sitemap
+const defaultSitemapPriority = 0.5
+
+// Sitemap the parsed sitemap, the URLs of `<urlset>` or the Sitemaps of `<sitemapindex>`
+type Sitemap struct {
+	URLs     []SitemapURL // the urls of `<urlset>`
+	Sitemaps []SitemapURL // the sitemaps of `<sitemapindex>`, only the Loc and LastMod are set
+}
+
+// SitemapURL the `<url>` of `<urlset>` or the `<sitemap>` of `<sitemapindex>`
+type SitemapURL struct {
+	Loc        string    // the absolute url
+	LastMod    time.Time // the `<lastmod>` in the W3C datetime format, in UTC, zero if it is empty or invalid
+	ChangeFreq string    // the `<changefreq>`, eg: `daily`, empty if it is not set
+	Priority   float64   // the `<priority>` from 0.0 to 1.0, default is 0.5
+}
+
+// Locs returns the urls of sitemap, eg: to parse each url by ParseURL
+func (s *Sitemap) Locs() []string {
+	locs := make([]string, 0, len(s.URLs))
+	for _, u := range s.URLs {
+		locs = append(locs, u.Loc)
+	}
+	return locs
+}
+
+// sitemapEntry the xml of the `<url>` or `<sitemap>` of sitemap
+type sitemapEntry struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+// sitemapXML the xml of `<urlset>` or `<sitemapindex>`
+type sitemapXML struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// sitemapTimeLayouts the layouts of the W3C datetime of `<lastmod>`
+var sitemapTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// ParseSitemap parse the sitemap or sitemap index xml, the gzipped sitemap is decompressed
+//	sitemap, err := pagser.ParseSitemap(file)
+//	for _, u := range sitemap.URLs {
+//		fmt.Println(u.Loc, u.LastMod, u.ChangeFreq, u.Priority)
+//	}
+func ParseSitemap(reader io.Reader) (*Sitemap, error) {
+	buffered := bufio.NewReader(reader)
+	// the gzip magic number
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("sitemap gzip error: %w", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	} else {
+		reader = buffered
+	}
+
+	var doc sitemapXML
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxSitemapBytes))
+	decoder.CharsetReader = charset.NewReaderLabel
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("sitemap xml error: %w", err)
+	}
+	switch doc.XMLName.Local {
+	case "urlset", "sitemapindex":
+	default:
+		return nil, fmt.Errorf("sitemap root element <%v> is not <urlset> or <sitemapindex>", doc.XMLName.Local)
+	}
+	sitemap := &Sitemap{}
+	for _, entry := range doc.URLs {
+		if u, ok := entry.sitemapURL(); ok {
+			sitemap.URLs = append(sitemap.URLs, u)
+		}
+	}
+	for _, entry := range doc.Sitemaps {
+		if u, ok := entry.sitemapURL(); ok {
+			u.ChangeFreq, u.Priority = "", 0
+			sitemap.Sitemaps = append(sitemap.Sitemaps, u)
+		}
+	}
+	return sitemap, nil
+}
+
+// sitemapURL returns the SitemapURL of entry, false if it has no loc
+func (e sitemapEntry) sitemapURL() (SitemapURL, bool) {
+	u := SitemapURL{
+		Loc:        strings.TrimSpace(e.Loc),
+		ChangeFreq: strings.ToLower(strings.TrimSpace(e.ChangeFreq)),
+		Priority:   defaultSitemapPriority,
+	}
+	if u.Loc == "" {
+		return u, false
+	}
+	if lastMod := strings.TrimSpace(e.LastMod); lastMod != "" {
+		for _, layout := range sitemapTimeLayouts {
+			if t, err := time.Parse(layout, lastMod); err == nil {
+				u.LastMod = t.UTC()
+				break
+			}
+		}
+	}
+	if priority, err := strconv.ParseFloat(strings.TrimSpace(e.Priority), 64); err == nil {
+		u.Priority = priority
+	}
+	return u, true
+}
+
+// FetchSitemap fetch the sitemap of url and return its urls, the sitemaps of sitemap index are fetched and their
+// urls are returned in order, each sitemap is fetched once. The options of request are the same as ParseURL.
+//	urls, err := pagser.FetchSitemap("https://example.com/sitemap.xml")
+//	for _, u := range urls {
+//		err = p.ParseURL(&data, u.Loc)
+//	}
+func FetchSitemap(url string, opts ...RequestOption) ([]SitemapURL, error) {
+	ro := newRequestOptions(opts...)
+	var urls []SitemapURL
+	visited := map[string]bool{url: true}
+	queue := []string{url}
+	for len(queue) > 0 {
+		sitemap, err := fetchSitemap(ro, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		urls = append(urls, sitemap.URLs...)
+		for _, child := range sitemap.Sitemaps {
+			if !visited[child.Loc] {
+				visited[child.Loc] = true
+				queue = append(queue, child.Loc)
+			}
+		}
+	}
+	return urls, nil
+}
+
+// fetchSitemap fetch and parse the sitemap of url
+func fetchSitemap(ro *requestOptions, url string) (*Sitemap, error) {
+	ctx := ro.ctx
+	if ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+	}
+
+	res, err := ro.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := decompressBody(res.Body, res.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	sitemap, err := ParseSitemap(body)
+	if err != nil {
+		return nil, fmt.Errorf("request %v error: %w", url, err)
+	}
+	return sitemap, nil
+}
@@ -0,0 +1,115 @@
+package pagser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSitemap = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url>
+		<loc> https://example.com/ </loc>
+		<lastmod>2005-01-01</lastmod>
+		<changefreq>Monthly</changefreq>
+		<priority>0.8</priority>
+	</url>
+	<url>
+		<loc>https://example.com/catalog?item=12&amp;desc=vacation_hawaii</loc>
+		<lastmod>2004-12-23T18:00:15+00:00</lastmod>
+	</url>
+	<url>
+		<loc></loc>
+	</url>
+	<url>
+		<loc>https://example.com/bad</loc>
+		<lastmod>yesterday</lastmod>
+		<priority>high</priority>
+	</url>
+</urlset>`
+
+func TestParseSitemap(t *testing.T) {
+	sitemap, err := ParseSitemap(strings.NewReader(testSitemap))
+	require.NoError(t, err)
+	require.Empty(t, sitemap.Sitemaps)
+	require.Equal(t, []SitemapURL{
+		{
+			Loc:        "https://example.com/",
+			LastMod:    time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC),
+			ChangeFreq: "monthly",
+			Priority:   0.8,
+		},
+		{
+			Loc:      "https://example.com/catalog?item=12&desc=vacation_hawaii",
+			LastMod:  time.Date(2004, 12, 23, 18, 0, 15, 0, time.UTC),
+			Priority: 0.5,
+		},
+		{Loc: "https://example.com/bad", Priority: 0.5},
+	}, sitemap.URLs)
+	require.Equal(t, []string{"https://example.com/", "https://example.com/catalog?item=12&desc=vacation_hawaii",
+		"https://example.com/bad"}, sitemap.Locs())
+
+	// the gzipped sitemap
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, _ = writer.Write([]byte(testSitemap))
+	require.NoError(t, writer.Close())
+	sitemap, err = ParseSitemap(&buf)
+	require.NoError(t, err)
+	require.Len(t, sitemap.URLs, 3)
+
+	// the sitemap of other charset
+	sitemap, err = ParseSitemap(strings.NewReader(`<?xml version="1.0" encoding="ISO-8859-1"?>` +
+		"<urlset><url><loc>https://example.com/caf\xe9</loc></url></urlset>"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/café", sitemap.URLs[0].Loc)
+
+	_, err = ParseSitemap(strings.NewReader(`<html></html>`))
+	require.EqualError(t, err, "sitemap root element <html> is not <urlset> or <sitemapindex>")
+	_, err = ParseSitemap(strings.NewReader(`<urlset><url>`))
+	require.Error(t, err)
+}
+
+func TestFetchSitemap(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			_, _ = w.Write([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>` + server.URL + `/posts.xml</loc><lastmod>2024-01-02</lastmod></sitemap>
+<sitemap><loc>` + server.URL + `/pages.xml</loc></sitemap>
+<sitemap><loc>` + server.URL + `/sitemap.xml</loc></sitemap>
+</sitemapindex>`))
+		case "/posts.xml":
+			_, _ = w.Write([]byte(`<urlset><url><loc>` + server.URL + `/post/1</loc></url></urlset>`))
+		case "/pages.xml":
+			_, _ = w.Write([]byte(`<urlset><url><loc>` + server.URL + `/about</loc></url></urlset>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sitemap, err := ParseSitemap(strings.NewReader(`<sitemapindex><sitemap><loc>https://example.com/a.xml</loc>` +
+		`<lastmod>2024-01-02</lastmod></sitemap></sitemapindex>`))
+	require.NoError(t, err)
+	require.Equal(t, []SitemapURL{{Loc: "https://example.com/a.xml", LastMod: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		sitemap.Sitemaps)
+
+	urls, err := FetchSitemap(server.URL + "/sitemap.xml")
+	require.NoError(t, err)
+	require.Equal(t, []SitemapURL{
+		{Loc: server.URL + "/post/1", Priority: 0.5},
+		{Loc: server.URL + "/about", Priority: 0.5},
+	}, urls)
+
+	_, err = FetchSitemap(server.URL + "/missing.xml")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+}
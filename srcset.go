@@ -0,0 +1,95 @@
+package pagser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ImageCandidate the image variant of `srcset` attribute parsed by srcset() function
+type ImageCandidate struct {
+	URL     string
+	Width   int     // width descriptor, eg: `480w`, 0 if not present
+	Density float64 // pixel density descriptor, eg: `2x`, 1 if neither width nor density present
+}
+
+// parseSrcset parses the image candidates of `srcset` attribute value, the commas in url like data uri are kept,
+// the candidates with invalid descriptors are skipped.
+func parseSrcset(srcset string) []ImageCandidate {
+	candidates := make([]ImageCandidate, 0)
+	pos := 0
+	for pos < len(srcset) {
+		// skip the whitespaces and commas before url
+		for pos < len(srcset) && (isSpaceByte(srcset[pos]) || srcset[pos] == ',') {
+			pos++
+		}
+		if pos >= len(srcset) {
+			break
+		}
+		start := pos
+		for pos < len(srcset) && !isSpaceByte(srcset[pos]) {
+			pos++
+		}
+		rawUrl := srcset[start:pos]
+		descriptors := ""
+		if strings.HasSuffix(rawUrl, ",") {
+			// the candidate without descriptors
+			rawUrl = strings.TrimRight(rawUrl, ",")
+		} else {
+			start = pos
+			depth := 0
+			for pos < len(srcset) && (srcset[pos] != ',' || depth > 0) {
+				switch srcset[pos] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+				pos++
+			}
+			descriptors = srcset[start:pos]
+		}
+		candidate, ok := newImageCandidate(rawUrl, strings.Fields(descriptors))
+		if ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// newImageCandidate returns the image candidate of url and descriptors, false if descriptors are invalid
+func newImageCandidate(rawUrl string, descriptors []string) (ImageCandidate, bool) {
+	candidate := ImageCandidate{URL: rawUrl}
+	for _, descriptor := range descriptors {
+		if len(descriptor) < 2 {
+			return candidate, false
+		}
+		value := descriptor[:len(descriptor)-1]
+		switch descriptor[len(descriptor)-1] {
+		case 'w', 'W':
+			width, err := strconv.Atoi(value)
+			if err != nil || width <= 0 || candidate.Width > 0 {
+				return candidate, false
+			}
+			candidate.Width = width
+		case 'x', 'X':
+			density, err := strconv.ParseFloat(value, 64)
+			if err != nil || density < 0 || candidate.Density > 0 {
+				return candidate, false
+			}
+			candidate.Density = density
+		case 'h', 'H':
+			// height descriptor is ignored
+		default:
+			return candidate, false
+		}
+	}
+	if candidate.Width == 0 && candidate.Density == 0 {
+		candidate.Density = 1
+	}
+	return candidate, true
+}
+
+// isSpaceByte returns true if the byte is ascii whitespace
+func isSpaceByte(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '\f'
+}
@@ -0,0 +1,178 @@
+package pagser
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// streamPeekBytes the bytes of the beginning of stream to determine the charset
+const streamPeekBytes = 1024
+
+// streamImpliedEnds the open elements which are closed by the start tag, eg: the `<li>` by the next `<li>`
+var streamImpliedEnds = map[string]map[string]bool{
+	"li":     {"li": true},
+	"dt":     {"dt": true, "dd": true},
+	"dd":     {"dt": true, "dd": true},
+	"p":      {"p": true},
+	"tr":     {"tr": true, "td": true, "th": true},
+	"td":     {"td": true, "th": true},
+	"th":     {"td": true, "th": true},
+	"option": {"option": true},
+}
+
+// streamVoidElements the elements which have no end tag
+var streamVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// ParseStream parse each element of reader which matches the selector to a new value of type T and call fn with it
+// in document order, the html is tokenized without building the whole document, so only the open elements and the
+// current item are kept in memory, eg: the html exports of hundreds of MB. The parsing is stopped and the error is
+// returned as is if fn returns an error.
+// The items are parsed from their elements by ParseSelection, the ancestors of item can be matched by the selector
+// and the tags, but the siblings can not, eg: `:nth-child` and `+`, and the item is matched by its start tag, so its
+// content can not, eg: `:has` and `:contains`. The elements are nested by their tags, only the end tags of li, dt,
+// dd, p, tr, td, th and option can be omitted, the elements matched inside an item are a part of it.
+// Config.MaxDocumentBytes is not applied.
+//	err := pagser.ParseStream(p, file, "table.rows > tbody > tr", func(row Row) error {
+//		return db.Insert(row)
+//	})
+func ParseStream[T any](p *Pagser, reader io.Reader, selector string, fn func(item T) error) error {
+	return ParseStreamContext(context.Background(), p, reader, selector, fn)
+}
+
+// ParseStreamContext parse the items of reader like ParseStream, parse will be aborted when the ctx is done
+func ParseStreamContext[T any](ctx context.Context, p *Pagser, reader io.Reader, selector string,
+	fn func(item T) error) error {
+	matcher, err := cascadia.Compile(selector)
+	if err != nil {
+		return fmt.Errorf("item selector `%v` is invalid: %w", selector, err)
+	}
+	reader, err = streamCharset(reader)
+	if err != nil {
+		return err
+	}
+
+	index := 0
+	emit := func(node *html.Node) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		item, err := parseAs[T](func(v interface{}) error {
+			return p.ParseSelectionContext(ctx, v, goquery.NewDocumentFromNode(node).Selection)
+		})
+		if err != nil {
+			return fmt.Errorf("item %v error: %w", index, err)
+		}
+		index++
+		return fn(item)
+	}
+
+	z := html.NewTokenizer(reader)
+	stack := []*html.Node{{Type: html.DocumentNode}}
+	var item *html.Node // the element of current item, nil if no item is open
+	// pop closes the open elements from stack[i], the closed item is emitted, the elements out of item are removed
+	pop := func(i int) error {
+		for len(stack) > i {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if node == item {
+				item = nil
+				if err := emit(node); err != nil {
+					return err
+				}
+			}
+			if item == nil {
+				node.Parent.RemoveChild(node)
+			}
+		}
+		return nil
+	}
+	for {
+		switch tt := z.Next(); tt {
+		case html.ErrorToken:
+			if z.Err() != io.EOF {
+				return z.Err()
+			}
+			// the unclosed elements are closed at the end
+			return pop(1)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			if ends := streamImpliedEnds[token.Data]; ends != nil {
+				i := len(stack)
+				for i > 1 && ends[stack[i-1].Data] {
+					i--
+				}
+				if err := pop(i); err != nil {
+					return err
+				}
+			}
+			node := &html.Node{Type: html.ElementNode, Data: token.Data, DataAtom: token.DataAtom, Attr: token.Attr}
+			stack[len(stack)-1].AppendChild(node)
+			stack = append(stack, node)
+			if item == nil && matcher.Match(node) {
+				item = node
+			}
+			if tt == html.SelfClosingTagToken || streamVoidElements[token.Data] {
+				if err := pop(len(stack) - 1); err != nil {
+					return err
+				}
+			}
+		case html.EndTagToken:
+			// the unmatched end tag is ignored, the unclosed elements in it are closed
+			token := z.Token()
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Data == token.Data {
+					if err := pop(i); err != nil {
+						return err
+					}
+					break
+				}
+			}
+		case html.TextToken, html.CommentToken:
+			if item != nil {
+				token := z.Token()
+				nodeType := html.TextNode
+				if tt == html.CommentToken {
+					nodeType = html.CommentNode
+				}
+				stack[len(stack)-1].AppendChild(&html.Node{Type: nodeType, Data: token.Data})
+			}
+		}
+	}
+}
+
+// streamCharset returns the reader of UTF-8 html decoded from reader by the charset of its beginning,
+// like newDocumentFromReader the html which is valid UTF-8 is not decoded by the guessed charset
+func streamCharset(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReaderSize(reader, streamPeekBytes)
+	prefix, err := buffered.Peek(streamPeekBytes)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	enc, name, certain := charset.DetermineEncoding(prefix, "")
+	if name == "utf-8" || (!certain && validUTF8Prefix(prefix)) {
+		return buffered, nil
+	}
+	return enc.NewDecoder().Reader(buffered), nil
+}
+
+// validUTF8Prefix reports whether content is valid UTF-8 except the partial rune at the end
+func validUTF8Prefix(content []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(content) > 0; i++ {
+		if utf8.Valid(content) {
+			return true
+		}
+		content = content[:len(content)-1]
+	}
+	return utf8.Valid(content)
+}
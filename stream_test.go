@@ -0,0 +1,115 @@
+package pagser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+type streamRow struct {
+	Name  string   `pagser:"td.name"`
+	Price float64  `pagser:"td.price"`
+	Tags  []string `pagser:"td.tags li"`
+	List  string   `pagser:"->closestAttr(table, id)"`
+}
+
+func TestParseStream(t *testing.T) {
+	p := New()
+	p.RegisterFunc("closestAttr", func(node *goquery.Selection, args ...string) (interface{}, error) {
+		return node.Closest(args[0]).AttrOr(args[1], ""), nil
+	})
+	html := `<html><body>
+<table id="rows"><tbody>
+<tr><td class="name">A &amp; B</td><td class="price">1.5</td><td class="tags"><ul><li>x<li>y</ul></td></tr>
+<tr><td class="name">C<br>D<td class="price">2
+<tr><td class="name"><!-- c -->E</td><td class="price">3</td></tr>
+</tbody></table>
+<table id="other"><tr><td class="name">F</td></tr></table>
+<script>var s = "<tr><td>no</td></tr>";</script>
+</body></html>`
+
+	var rows []streamRow
+	err := ParseStream(p, strings.NewReader(html), "table#rows tr", func(row streamRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []streamRow{
+		{Name: "A & B", Price: 1.5, Tags: []string{"x", "y"}, List: "rows"},
+		{Name: "CD", Price: 2, Tags: []string{}, List: "rows"},
+		{Name: "E", Price: 3, Tags: []string{}, List: "rows"},
+	}, rows)
+
+	// the error of fn stops parsing
+	stop := errors.New("stop")
+	count := 0
+	err = ParseStream(p, strings.NewReader(html), "tr", func(row *streamRow) error {
+		count++
+		return stop
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, 1, count)
+
+	err = ParseStream(p, strings.NewReader(html), "tr[", func(row streamRow) error { return nil })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "item selector `tr[` is invalid")
+}
+
+func TestParseStream_Large(t *testing.T) {
+	reader, writer := io.Pipe()
+	go func() {
+		_, _ = fmt.Fprint(writer, `<ul class="items">`)
+		for i := 0; i < 100000; i++ {
+			_, _ = fmt.Fprintf(writer, `<li class="item"><a href="/item/%v">Item %v</a></li>`, i, i)
+		}
+		_, _ = fmt.Fprint(writer, `</ul>`)
+		_ = writer.Close()
+	}()
+	type item struct {
+		Name string `pagser:"a"`
+		Link string `pagser:"a->attr(href)"`
+	}
+	count := 0
+	err := ParseStream(New(), reader, "li.item", func(it item) error {
+		require.Equal(t, fmt.Sprintf("Item %v", count), it.Name)
+		require.Equal(t, fmt.Sprintf("/item/%v", count), it.Link)
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 100000, count)
+}
+
+func TestParseStream_Charset(t *testing.T) {
+	content, err := charmap.Windows1252.NewEncoder().String(`<meta charset="windows-1252"><p>Café</p>`)
+	require.NoError(t, err)
+	var texts []string
+	err = ParseStream(New(), bytes.NewReader([]byte(content)), "p", func(item struct {
+		Text string `pagser:"->text()"`
+	}) error {
+		texts = append(texts, item.Text)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Café"}, texts)
+
+	// the utf-8 without charset is not decoded by the default windows-1252
+	texts = texts[:0]
+	err = ParseStream(New(), strings.NewReader(`<p>`+strings.Repeat("a", 2000)+`</p><p>日本</p>`), "p",
+		func(item struct {
+			Text string `pagser:"->text()"`
+		}) error {
+			texts = append(texts, item.Text)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, texts, 2)
+	require.Equal(t, "日本", texts[1])
+}
@@ -0,0 +1,162 @@
+package pagser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// tableSpan the cell spanned from the rows above
+type tableSpan struct {
+	text string
+	rows int
+}
+
+// tableRows returns the cells text of each row in the first table element, the header and body rows are
+// in document order, the cells of `colspan` and `rowspan` are expanded, so all rows of the grid
+// have the columns at the same positions. Rows of nested tables are ignored.
+func tableRows(node *goquery.Selection) [][]string {
+	rows := make([][]string, 0)
+	table := node.First()
+	if !table.Is("table") {
+		table = table.Find("table").First()
+	}
+	if table.Size() == 0 {
+		return rows
+	}
+	spans := make(map[int]*tableSpan)
+	table.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		if tr.Closest("table").Get(0) != table.Get(0) {
+			return
+		}
+		row := make([]string, 0)
+		col := 0
+		// fill the cells spanned from the rows above
+		fillSpans := func() {
+			for span, ok := spans[col]; ok; span, ok = spans[col] {
+				row = append(row, span.text)
+				if span.rows--; span.rows <= 0 {
+					delete(spans, col)
+				}
+				col++
+			}
+		}
+		tr.ChildrenFiltered("th, td").Each(func(j int, cell *goquery.Selection) {
+			fillSpans()
+			text := strings.TrimSpace(cell.Text())
+			colspan := tableSpanValue(cell, "colspan", tableMaxColspan)
+			rowspan := tableSpanValue(cell, "rowspan", tableMaxRowspan)
+			for k := 0; k < colspan; k++ {
+				row = append(row, text)
+				if rowspan > 1 {
+					spans[col] = &tableSpan{text: text, rows: rowspan - 1}
+				}
+				col++
+			}
+		})
+		fillSpans()
+		// the spans at the end of row after skipped columns
+		for len(spans) > 0 {
+			maxCol := -1
+			for c := range spans {
+				if c > maxCol {
+					maxCol = c
+				}
+			}
+			if maxCol < col {
+				break
+			}
+			row = append(row, "")
+			col++
+			fillSpans()
+		}
+		rows = append(rows, row)
+	})
+	return rows
+}
+
+// tableMaxColspan and tableMaxRowspan the max `colspan` and `rowspan` of cell like the browsers,
+// the greater values are clamped, so the huge spans can not expand the grid out of memory
+const (
+	tableMaxColspan = 1000
+	tableMaxRowspan = 65534
+)
+
+// tableSpanValue returns the `colspan` or `rowspan` attribute value of cell clamped to max, default is 1,
+// the `rowspan="0"` spanning the rest of table is 1
+func tableSpanValue(cell *goquery.Selection, name string, max int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(cell.AttrOr(name, "1")))
+	if err != nil || value < 1 {
+		return 1
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// isTableRowType returns true if the item type of slice can be set from the table row, eg: struct or pointer to struct
+func (p *Pagser) isTableRowType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !p.isValueType(t)
+}
+
+// setTableRowsValue set the table rows to the struct items of slice, the first row is the header,
+// the cell of each row is set to the field by matching the header text with the `header` tag or the field name
+// case-insensitively, eg: `header:"Price"`.
+func (p *Pagser) setTableRowsValue(fieldValue reflect.Value, rows [][]string, opts fieldOptions) error {
+	if len(rows) == 0 {
+		fieldValue.Set(reflect.MakeSlice(fieldValue.Type(), 0, 0))
+		return nil
+	}
+	headers := rows[0]
+	itemType := fieldValue.Type().Elem()
+	structType := itemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	// the column index of each field, -1 if the header is not found
+	columns := make([]int, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		columns[i] = -1
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get(headerTagName)
+		if name == "" {
+			name = field.Name
+		}
+		for col, header := range headers {
+			if strings.EqualFold(strings.TrimSpace(header), strings.TrimSpace(name)) {
+				columns[i] = col
+				break
+			}
+		}
+	}
+	slice := reflect.MakeSlice(fieldValue.Type(), len(rows)-1, len(rows)-1)
+	for i, row := range rows[1:] {
+		item := slice.Index(i)
+		if itemType.Kind() == reflect.Pointer {
+			item.Set(reflect.New(structType))
+			item = item.Elem()
+		}
+		for j, col := range columns {
+			if col < 0 || col >= len(row) {
+				continue
+			}
+			field := structType.Field(j)
+			err := p.setFieldValue(item.Field(j), row[col], fieldOptions{Layout: field.Tag.Get(layoutTagName), Base: opts.Base})
+			if err != nil {
+				return fmt.Errorf("table row %v column `%v` set value error: %w", i+1, headers[col], err)
+			}
+		}
+	}
+	fieldValue.Set(slice)
+	return nil
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
 )
 
 type tokenState int
@@ -20,13 +23,62 @@ const (
 //->fn(xxx)
 //->fn('xxx')
 //->fn('xxx\'xxx', 'xxx,xxx')
-var rxFunc = regexp.MustCompile("^\\s*([a-zA-Z]+)\\s*(\\(([^\\)]*)\\))?\\s*$")
+//->fn('(\d+)')
+var rxFunc = regexp.MustCompile("^\\s*([a-zA-Z]+)\\s*(\\((.*)\\))?\\s*$")
+
+// modifierRequired field modifier makes parse return an error if the field value is missing, eg: `h1->text(),required`
+const modifierRequired = "required"
+
+// modifierOmitEmpty field modifier leaves the field unset if the selector matches nothing, eg: pointer fields keep nil
+const modifierOmitEmpty = "omitempty"
+
+// modifierStrict field modifier makes parse return an error if the selector matches nothing, eg: `.price,strict`
+const modifierStrict = "strict"
+
+// modifierDefault field modifier sets the default value if the selector matches nothing, eg: `.price->text(),default=0.0`
+const modifierDefault = "default="
+
+// selectorFallbackSymbol separator of the fallback selectors, eg: `.new-title || h1.title || h1`
+const selectorFallbackSymbol = "||"
 
 // tagTokenizer struct tag info
 type tagTokenizer struct {
 	Selector   string
-	FuncName   string
-	FuncParams []string
+	Selectors  []string   // alternative selectors split from Selector, the first one matches any node is used
+	Funcs      []*tagFunc // function pipeline, the output of each function is the input of next function
+	Required   bool       // returns an error if the selector matches nothing or the value is empty
+	Default    string     // default value if the selector matches nothing
+	HasDefault bool
+	OmitEmpty  bool               // leaves the field unset if the selector matches nothing
+	Strict     bool               // returns an error if the selector matches nothing
+	Computed   *fieldExpr         // expression of computed field evaluated after the other fields, eg: `=Price * Quantity`
+	matchers   []*selectorMatcher // compiled Selectors
+}
+
+// selectorMatcher the compiled selector, the selector starts with `>` is compiled to the matchers of children
+type selectorMatcher struct {
+	children []goquery.Matcher // compound selectors of children in order, eg: `> ul > li`
+	matcher  goquery.Matcher   // selector of descendants after the children, nil if there is none
+}
+
+// selectorError the error of invalid selector of tag
+type selectorError struct {
+	Selector string // selector of tag
+	Err      error
+}
+
+func (e *selectorError) Error() string {
+	return fmt.Sprintf("selector `%v` is invalid: %v", e.Selector, e.Err)
+}
+
+func (e *selectorError) Unwrap() error {
+	return e.Err
+}
+
+// tagFunc function info of struct tag
+type tagFunc struct {
+	Name   string
+	Params []string
 }
 
 func (p *Pagser) newTag(tagValue string) (*tagTokenizer, error) {
@@ -35,33 +87,251 @@ func (p *Pagser) newTag(tagValue string) (*tagTokenizer, error) {
 	if tagValue == "" {
 		return tag, nil
 	}
-	selectors := strings.Split(tagValue, p.Config.FuncSymbol)
-	funcValue := ""
-	for i := 0; i < len(selectors); i++ {
-		switch i {
-		case 0:
-			tag.Selector = strings.TrimSpace(selectors[i])
-		case 1:
-			funcValue = selectors[i]
+	if strings.HasPrefix(strings.TrimSpace(tagValue), computedSymbol) {
+		expr, err := parseFieldExpr(strings.TrimPrefix(strings.TrimSpace(tagValue), computedSymbol))
+		if err != nil {
+			return nil, fmt.Errorf("tag=`%v` is invalid: expression %v", tagValue, err)
 		}
-	}
-	matches := rxFunc.FindStringSubmatch(funcValue)
-	if len(matches) < 3 {
+		tag.Computed = expr
 		return tag, nil
 	}
-	tag.FuncName = strings.TrimSpace(matches[1])
-	//tag.FuncParams = strings.Split(matches[2], ",")
-	params, err := parseFuncParamTokens(matches[3])
-	if err != nil {
-		return nil, fmt.Errorf("tag=`%v` is invalid: %v", tagValue, err)
+	value, modifiers := splitTagModifiers(tagValue)
+	for _, modifier := range modifiers {
+		switch {
+		case modifier == modifierRequired:
+			tag.Required = true
+		case modifier == modifierOmitEmpty:
+			tag.OmitEmpty = true
+		case modifier == modifierStrict:
+			tag.Strict = true
+		case strings.HasPrefix(modifier, modifierDefault):
+			defaultValue := strings.TrimSpace(strings.TrimPrefix(modifier, modifierDefault))
+			if len(defaultValue) >= 2 && defaultValue[0] == '\'' && defaultValue[len(defaultValue)-1] == '\'' {
+				defaultValue = strings.ReplaceAll(defaultValue[1:len(defaultValue)-1], "\\'", "'")
+			}
+			tag.Default = defaultValue
+			tag.HasDefault = true
+		}
+	}
+	selectors := splitFuncTokens(value, p.Config.FuncSymbol)
+	tag.Selector = strings.TrimSpace(selectors[0])
+	if tag.Selector != "" {
+		for _, selector := range splitFallbackSelectors(tag.Selector) {
+			selector = strings.TrimSpace(selector)
+			if selector == "" {
+				return nil, fmt.Errorf("tag=`%v` is invalid: empty fallback selector", tagValue)
+			}
+			matcher, err := compileSelectorMatcher(selector)
+			if err != nil {
+				return nil, fmt.Errorf("tag=`%v` is invalid: %w", tagValue, err)
+			}
+			tag.Selectors = append(tag.Selectors, selector)
+			tag.matchers = append(tag.matchers, matcher)
+		}
+	}
+	for _, funcValue := range selectors[1:] {
+		matches := rxFunc.FindStringSubmatch(funcValue)
+		if len(matches) < 3 {
+			if len(tag.Funcs) == 0 {
+				// keep compatible with the single function tag
+				return tag, nil
+			}
+			return nil, fmt.Errorf("tag=`%v` is invalid: function `%v` syntax error", tagValue, strings.TrimSpace(funcValue))
+		}
+		//tag.FuncParams = strings.Split(matches[2], ",")
+		params, err := parseFuncParamTokens(matches[3])
+		if err != nil {
+			return nil, fmt.Errorf("tag=`%v` is invalid: %v", tagValue, err)
+		}
+		tag.Funcs = append(tag.Funcs, &tagFunc{
+			Name:   strings.TrimSpace(matches[1]),
+			Params: params,
+		})
 	}
-	tag.FuncParams = params
 	if p.Config.Debug {
 		fmt.Printf("----- debug -----\n`%v`\n%v\n", tagValue, prettyJson(tag))
 	}
 	return tag, nil
 }
 
+// presenceFuncs the builtin functions which report whether the selector matches any node, eg: `.badge->exists()`
+var presenceFuncs = map[string]bool{"exists": true, "ifExists": true, "size": true}
+
+// checksPresence reports whether the pipeline of tag reports the presence of nodes, so the missing nodes
+// are not the error of Config.StrictSelectors
+func (tag *tagTokenizer) checksPresence() bool {
+	for _, fn := range tag.Funcs {
+		if presenceFuncs[fn.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// find gets the descendants of selection by the selectors in order, until one of them matches any node.
+// The selection itself is returned if there is no selector.
+func (tag *tagTokenizer) find(selection *goquery.Selection) *goquery.Selection {
+	if tag.Selector == "" {
+		return selection
+	}
+	var node *goquery.Selection
+	for _, matcher := range tag.matchers {
+		node = matcher.find(selection)
+		if node.Size() > 0 {
+			break
+		}
+	}
+	return node
+}
+
+// compileSelectorMatcher compiles the selector, the selector starts with `>` is relative to the selection,
+// eg: `> ul > li` gets the items of child lists only, which is useful for recursive structs.
+func compileSelectorMatcher(selector string) (*selectorMatcher, error) {
+	rawSelector := selector
+	selector = strings.TrimSpace(selector)
+	m := &selectorMatcher{}
+	for strings.HasPrefix(selector, ">") {
+		compound, rest := splitCompoundSelector(strings.TrimSpace(selector[1:]))
+		child, err := cascadia.Compile(compound)
+		if err != nil {
+			return nil, &selectorError{Selector: rawSelector, Err: err}
+		}
+		m.children = append(m.children, child)
+		selector = rest
+	}
+	if selector != "" {
+		matcher, err := cascadia.Compile(selector)
+		if err != nil {
+			return nil, &selectorError{Selector: rawSelector, Err: err}
+		}
+		m.matcher = matcher
+	}
+	return m, nil
+}
+
+// find gets the children and then the descendants of selection by the compiled selector
+func (m *selectorMatcher) find(selection *goquery.Selection) *goquery.Selection {
+	for _, child := range m.children {
+		selection = selection.ChildrenMatcher(child)
+	}
+	if m.matcher == nil {
+		return selection
+	}
+	return selection.FindMatcher(m.matcher)
+}
+
+// splitCompoundSelector split the first compound selector before combinator, eg: `ul.menu > li` -> `ul.menu`, `> li`
+func splitCompoundSelector(selector string) (string, string) {
+	depth := 0
+	var quote byte
+	for pos := 0; pos < len(selector); pos++ {
+		ch := selector[pos]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				pos++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(' || ch == '[':
+			depth++
+		case ch == ')' || ch == ']':
+			depth--
+		case depth == 0 && strings.ContainsRune(" \t\n>+~", rune(ch)):
+			return selector[:pos], strings.TrimSpace(selector[pos:])
+		}
+	}
+	return selector, ""
+}
+
+// isTagModifier returns true if the text is a field modifier
+func isTagModifier(text string) bool {
+	return text == modifierRequired || text == modifierOmitEmpty || text == modifierStrict || strings.HasPrefix(text, modifierDefault)
+}
+
+// splitTagModifiers split the trailing field modifiers from tag value, eg: `h1->text(),required`,
+// the commas in quotes, parentheses or brackets are ignored, so selector groups such as `h1, h2` are kept.
+func splitTagModifiers(text string) (string, []string) {
+	modifiers := make([]string, 0)
+	end := len(text)
+	commas := topLevelIndexes(text, ",")
+	for i := len(commas) - 1; i >= 0; i-- {
+		modifier := strings.TrimSpace(text[commas[i]+1 : end])
+		if !isTagModifier(modifier) {
+			break
+		}
+		modifiers = append([]string{modifier}, modifiers...)
+		end = commas[i]
+	}
+	return text[:end], modifiers
+}
+
+// topLevelIndexes returns the positions of sep in text, the sep in quotes, parentheses or brackets is ignored
+func topLevelIndexes(text string, sep string) []int {
+	indexes := make([]int, 0)
+	depth := 0
+	var quote byte
+	for pos := 0; pos < len(text); pos++ {
+		ch := text[pos]
+		switch {
+		case quote != 0:
+			if ch == '\\' {
+				pos++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(' || ch == '[':
+			depth++
+		case ch == ')' || ch == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(text[pos:], sep):
+			indexes = append(indexes, pos)
+			pos += len(sep) - 1
+		}
+	}
+	return indexes
+}
+
+// splitFallbackSelectors split the selector by selectorFallbackSymbol like strings.Split,
+// the symbol in quotes, parentheses or brackets is ignored, eg: `a[title='x||y'] || a`
+func splitFallbackSelectors(selector string) []string {
+	selectors := make([]string, 0)
+	start := 0
+	for _, pos := range topLevelIndexes(selector, selectorFallbackSymbol) {
+		selectors = append(selectors, selector[start:pos])
+		start = pos + len(selectorFallbackSymbol)
+	}
+	return append(selectors, selector[start:])
+}
+
+// splitFuncTokens split tag value by the function symbol, the symbol in single quotes is ignored,
+// it falls back to split by all symbols if the quotes are not closed.
+func splitFuncTokens(text string, symbol string) []string {
+	tokens := make([]string, 0)
+	inQuote := false
+	start := 0
+	for pos := 0; pos < len(text); pos++ {
+		switch {
+		case text[pos] == '\\' && inQuote:
+			pos++
+		case text[pos] == '\'':
+			inQuote = !inQuote
+		case !inQuote && strings.HasPrefix(text[pos:], symbol):
+			tokens = append(tokens, text[start:pos])
+			pos += len(symbol) - 1
+			start = pos + 1
+		}
+	}
+	if inQuote {
+		return strings.Split(text, symbol)
+	}
+	return append(tokens, text[start:])
+}
+
 func parseFuncParamTokens(text string) ([]string, error) {
 	tokens := make([]string, 0)
 	textLen := len(text)
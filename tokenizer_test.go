@@ -1,6 +1,9 @@
 package pagser
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestFuncParamTokens(t *testing.T) {
 	inputs := []string{
@@ -38,3 +41,159 @@ func TestFuncParamTokens(t *testing.T) {
 
 	}
 }
+
+func TestPagser_NewTagPipeline(t *testing.T) {
+	p := New()
+	tests := []struct {
+		tag      string
+		selector string
+		funcs    []string
+	}{
+		{`h1`, `h1`, []string{}},
+		{`h1->text()`, `h1`, []string{"text"}},
+		{`.price->text()->trim('$')`, `.price`, []string{"text", "trim"}},
+		{`a->attr(href)->textConcat('->', $value)`, `a`, []string{"attr", "textConcat"}},
+		{`a[title='a->b']->first()->text()`, `a[title='a->b']`, []string{"first", "text"}},
+	}
+	for _, tt := range tests {
+		tag, err := p.newTag(tt.tag)
+		if err != nil {
+			t.Fatalf("tag `%v` error: %v", tt.tag, err)
+		}
+		if tag.Selector != tt.selector {
+			t.Fatalf("tag `%v` selector want `%v`, but got `%v`", tt.tag, tt.selector, tag.Selector)
+		}
+		if len(tag.Funcs) != len(tt.funcs) {
+			t.Fatalf("tag `%v` funcs want %v, but got %v", tt.tag, tt.funcs, prettyJson(tag.Funcs))
+		}
+		for i, fn := range tag.Funcs {
+			if fn.Name != tt.funcs[i] {
+				t.Fatalf("tag `%v` func %v want `%v`, but got `%v`", tt.tag, i, tt.funcs[i], fn.Name)
+			}
+		}
+	}
+
+	_, err := p.newTag(`h1->text()->trim(`)
+	if err == nil {
+		t.Fatal("invalid pipeline function must return error")
+	}
+}
+
+func TestSplitTagModifiers(t *testing.T) {
+	tests := []struct {
+		tag       string
+		value     string
+		modifiers []string
+	}{
+		{`h1->text()`, `h1->text()`, []string{}},
+		{`h1->text(),required`, `h1->text()`, []string{"required"}},
+		{`h1, h2 , required`, `h1, h2 `, []string{"required"}},
+		{`h1, h2`, `h1, h2`, []string{}},
+		{`a->attrConcat(href, 'x,required')`, `a->attrConcat(href, 'x,required')`, []string{}},
+		{`a[title="a,required"]`, `a[title="a,required"]`, []string{}},
+		{`.price->text(),default=0.0,required`, `.price->text()`, []string{"default=0.0", "required"}},
+		{`.name,default='a, b'`, `.name`, []string{"default='a, b'"}},
+		{`.price,strict,omitempty`, `.price`, []string{"strict", "omitempty"}},
+	}
+	for _, tt := range tests {
+		value, modifiers := splitTagModifiers(tt.tag)
+		if value != tt.value {
+			t.Fatalf("tag `%v` value want `%v`, but got `%v`", tt.tag, tt.value, value)
+		}
+		if len(modifiers) != len(tt.modifiers) {
+			t.Fatalf("tag `%v` modifiers want %v, but got %v", tt.tag, tt.modifiers, modifiers)
+		}
+		for i, modifier := range modifiers {
+			if modifier != tt.modifiers[i] {
+				t.Fatalf("tag `%v` modifier %v want `%v`, but got `%v`", tt.tag, i, tt.modifiers[i], modifier)
+			}
+		}
+	}
+}
+
+func TestSplitFallbackSelectors(t *testing.T) {
+	tests := []struct {
+		selector  string
+		selectors []string
+	}{
+		{`h1`, []string{`h1`}},
+		{`.new-title || h1.title || h1`, []string{`.new-title `, ` h1.title `, ` h1`}},
+		{`a[title='x||y']`, []string{`a[title='x||y']`}},
+		{`a[title="x||y"] || a`, []string{`a[title="x||y"] `, ` a`}},
+		{`li:not([data-x='a||b']) || li`, []string{`li:not([data-x='a||b']) `, ` li`}},
+		{`h1 || `, []string{`h1 `, ` `}},
+	}
+	for _, tt := range tests {
+		selectors := splitFallbackSelectors(tt.selector)
+		if len(selectors) != len(tt.selectors) {
+			t.Fatalf("selector `%v` want %q, but got %q", tt.selector, tt.selectors, selectors)
+		}
+		for i, selector := range selectors {
+			if selector != tt.selectors[i] {
+				t.Fatalf("selector `%v` want %q, but got %q", tt.selector, tt.selectors, selectors)
+			}
+		}
+	}
+
+	p := New()
+	type TitleData struct {
+		Title string `pagser:"a[title='x||y'] || h1->attr(title)"`
+	}
+	var data TitleData
+	if err := p.Parse(&data, `<a title="x||y">link</a><h1 title="h1">title</h1>`); err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "x||y" {
+		t.Fatalf("title want `x||y`, but got `%v`", data.Title)
+	}
+}
+
+func TestSplitCompoundSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		compound string
+		rest     string
+	}{
+		{`ul`, `ul`, ``},
+		{`ul > li`, `ul`, `> li`},
+		{`ul>li`, `ul`, `>li`},
+		{`a[title="a > b"] span`, `a[title="a > b"]`, `span`},
+		{`li:not(.a > .b) + li`, `li:not(.a > .b)`, `+ li`},
+	}
+	for _, tt := range tests {
+		compound, rest := splitCompoundSelector(tt.selector)
+		if compound != tt.compound || rest != tt.rest {
+			t.Fatalf("selector `%v` want `%v`, `%v`, but got `%v`, `%v`", tt.selector, tt.compound, tt.rest, compound, rest)
+		}
+	}
+}
+
+func TestPagser_NewTagSelector(t *testing.T) {
+	p := New()
+	tag, err := p.newTag(`.new-title || > ul > li a || h1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tag.matchers) != 3 {
+		t.Fatalf("matchers want 3, but got %v", len(tag.matchers))
+	}
+	if m := tag.matchers[1]; len(m.children) != 2 || m.matcher == nil {
+		t.Fatalf("selector `> ul > li a` want 2 children and descendants matcher, but got %v children", len(m.children))
+	}
+
+	for _, tagValue := range []string{`h1[->text()`, `div:unknown`, `> ul > [`} {
+		_, err := p.newTag(tagValue)
+		var selErr *selectorError
+		if !errors.As(err, &selErr) {
+			t.Fatalf("tag `%v` want selector error, but got %v", tagValue, err)
+		}
+	}
+
+	type InvalidData struct {
+		Title string `pagser:"h1["`
+	}
+	var data InvalidData
+	if err := p.Parse(&data, `<h1>title</h1>`); err == nil {
+		t.Fatal("invalid selector must return error")
+	}
+}
@@ -0,0 +1,191 @@
+package pagser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// documentUrlKey the context key of the *documentUrl of the document being parsed
+type documentUrlKey struct{}
+
+// documentUrl the url of document fetched by ParseURL or the `Url` of document parsed by ParseDocument
+type documentUrl struct {
+	root *html.Node
+	url  *url.URL
+}
+
+// withDocumentUrl returns the context with the `Url` of document to resolve the relative urls of its nodes
+func withDocumentUrl(ctx context.Context, document *goquery.Document) context.Context {
+	if document.Url == nil {
+		return ctx
+	}
+	root := documentRoot(document.Selection)
+	if root == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, documentUrlKey{}, &documentUrl{root: root, url: document.Url})
+}
+
+// parseBaseKey the context key of the *parseBase of the parse
+type parseBaseKey struct{}
+
+// parseBase the base url of the document being parsed, it is resolved once per parse when it is used first,
+// so the fields and functions of all items share it
+type parseBase struct {
+	root    *html.Node
+	docUrl  *url.URL
+	baseURL string // Config.BaseURL of the parse
+	once    sync.Once
+	url     *url.URL
+	err     error
+}
+
+// withParseBase returns the context with the base url of the document of selection, the base url of the outer
+// parse of the same document and config is kept, eg: the items of ParseListDetail
+func (p *Pagser) withParseBase(ctx context.Context, selection *goquery.Selection) context.Context {
+	root := documentRoot(selection)
+	if outer, ok := ctx.Value(parseBaseKey{}).(*parseBase); ok && outer.root == root && outer.baseURL == p.Config.BaseURL {
+		return ctx
+	}
+	base := &parseBase{root: root, baseURL: p.Config.BaseURL}
+	if docUrl, ok := ctx.Value(documentUrlKey{}).(*documentUrl); ok && root != nil && docUrl.root == root {
+		base.docUrl = docUrl.url
+	}
+	return context.WithValue(ctx, parseBaseKey{}, base)
+}
+
+// parseBaseOf returns the base url of the parse of ctx, nil if ctx is not of a parse
+func parseBaseOf(ctx context.Context) *parseBase {
+	base, _ := ctx.Value(parseBaseKey{}).(*parseBase)
+	return base
+}
+
+// get returns the base url, nil if no base url found.
+// The `<base href>` of document is used if exists (resolved against the document url if it is relative),
+// otherwise the document url is used, the document url is the url fetched by ParseURL, the `Url` of document
+// parsed by ParseDocument or the `Config.BaseURL`.
+func (b *parseBase) get() (*url.URL, error) {
+	if b == nil {
+		return nil, nil
+	}
+	b.once.Do(func() {
+		b.url, b.err = resolveBaseUrl(b.baseURL, b.docUrl, b.root)
+	})
+	return b.url, b.err
+}
+
+// baseUrlFunc the registered function which resolves the relative urls against the base url of the parse,
+// eg: absHref() and links()
+type baseUrlFunc func(parse *parseBase, node *goquery.Selection, args ...string) (out interface{}, err error)
+
+// callFunc calls the registered CallFunc, or the baseUrlFunc with the base url of the parse
+func callFunc(f interface{}, parse *parseBase, node *goquery.Selection, args ...string) (interface{}, error) {
+	if fn, ok := f.(baseUrlFunc); ok {
+		return fn(parse, node, args...)
+	}
+	return f.(CallFunc)(node, args...)
+}
+
+// resolveBaseUrl resolves the base url of the document root like parseBase.get
+func resolveBaseUrl(baseURL string, docUrl *url.URL, root *html.Node) (*url.URL, error) {
+	base := docUrl
+	if base == nil && baseURL != "" {
+		var err error
+		base, err = url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base url: %v error: %v", baseURL, err)
+		}
+	}
+	if root == nil {
+		return base, nil
+	}
+	href, ok := goquery.NewDocumentFromNode(root).Find("base[href]").First().Attr("href")
+	if !ok {
+		return base, nil
+	}
+	hrefUrl, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base href: %v error: %v", href, err)
+	}
+	if base != nil {
+		return base.ResolveReference(hrefUrl), nil
+	}
+	return hrefUrl, nil
+}
+
+// toUrlE casts an interface to a url.URL type, relative url is resolved against the base url of the parse.
+func toUrlE(i interface{}, parse *parseBase) (url.URL, error) {
+	var u *url.URL
+	switch v := i.(type) {
+	case url.URL:
+		u = &v
+	case *url.URL:
+		if v == nil {
+			return url.URL{}, fmt.Errorf("unable to cast %#v of type %T to url.URL", i, i)
+		}
+		u = v
+	case string:
+		var err error
+		u, err = url.Parse(strings.TrimSpace(v))
+		if err != nil {
+			return url.URL{}, err
+		}
+	default:
+		return url.URL{}, fmt.Errorf("unable to cast %#v of type %T to url.URL", i, i)
+	}
+
+	if !u.IsAbs() {
+		base, err := parse.get()
+		if err != nil {
+			return url.URL{}, err
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+	}
+	return *u, nil
+}
+
+// documentRoot get root node of the document which the selection belongs to.
+func documentRoot(node *goquery.Selection) *html.Node {
+	if node == nil || len(node.Nodes) == 0 {
+		return nil
+	}
+	root := node.Nodes[0]
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	return root
+}
+
+// absHref absHref(baseUrl='') get element attribute name `href`, and convert to absolute url, return *URL.
+// The base url of document is used if `baseUrl` is empty, such as the url fetched by ParseURL or `Config.BaseURL`.
+//	struct {
+//		Example string `pagser:".selector->absHref()"`
+//	}
+func absHref(parse *parseBase, node *goquery.Selection, args ...string) (out interface{}, err error) {
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		return builtinFun.AbsHref(node, args...)
+	}
+	base, err := parse.get()
+	if err != nil {
+		return "", err
+	}
+	if base == nil {
+		return "", fmt.Errorf("absHref(baseUrl) must has baseUrl if the document has no base url")
+	}
+	hrefUrl, err := url.Parse(strings.TrimSpace(node.AttrOr("href", "")))
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(hrefUrl), nil
+}
@@ -4,6 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cast"
 )
@@ -152,3 +157,102 @@ func prettyJson(v interface{}) string {
 	bytes, _ := json.MarshalIndent(v, "", "\t")
 	return string(bytes)
 }
+
+// toTimeE casts an interface to a time.Time type, parsing strings with layout if it is not empty.
+func toTimeE(i interface{}, layout string) (time.Time, error) {
+	if s, ok := i.(string); ok && layout != "" {
+		return time.Parse(layout, strings.TrimSpace(s))
+	}
+	return cast.ToTimeE(i)
+}
+
+// toTimeSliceE casts an interface to a []time.Time type.
+func toTimeSliceE(i interface{}, layout string) ([]time.Time, error) {
+	if i == nil {
+		return []time.Time{}, fmt.Errorf("unable to cast %#v of type %T to []time.Time", i, i)
+	}
+
+	switch v := i.(type) {
+	case []time.Time:
+		return v, nil
+	}
+
+	kind := reflect.TypeOf(i).Kind()
+	switch kind {
+	case reflect.Slice, reflect.Array:
+		s := reflect.ValueOf(i)
+		a := make([]time.Time, s.Len())
+		for j := 0; j < s.Len(); j++ {
+			val, err := toTimeE(s.Index(j).Interface(), layout)
+			if err != nil {
+				return []time.Time{}, fmt.Errorf("unable to cast %#v of type %T to []time.Time", i, i)
+			}
+			a[j] = val
+		}
+		return a, nil
+	default:
+		return []time.Time{}, fmt.Errorf("unable to cast %#v of type %T to []time.Time", i, i)
+	}
+}
+
+// toStringMapE casts an interface to a map[string]interface{} type, supports maps of any value type.
+func toStringMapE(i interface{}) (map[string]interface{}, error) {
+	if i != nil && reflect.TypeOf(i).Kind() == reflect.Map {
+		m := reflect.ValueOf(i)
+		out := make(map[string]interface{}, m.Len())
+		iter := m.MapRange()
+		for iter.Next() {
+			key, err := cast.ToStringE(iter.Key().Interface())
+			if err != nil {
+				return map[string]interface{}{}, fmt.Errorf("unable to cast %#v of type %T to map[string]interface{}", i, i)
+			}
+			out[key] = iter.Value().Interface()
+		}
+		return out, nil
+	}
+	return cast.ToStringMapE(i)
+}
+
+// regexpCache compiled regular expressions of function arguments
+var regexpCache sync.Map
+
+// compileRegexp compiles the regular expression and caches it
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if cache, ok := regexpCache.Load(pattern); ok {
+		return cache.(*regexp.Regexp), nil
+	}
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexpCache.Store(pattern, rx)
+	return rx, nil
+}
+
+// regexpFind returns the first capture group of the first match, or the whole match if there is no group
+func regexpFind(rx *regexp.Regexp, text string) string {
+	matches := rx.FindStringSubmatch(text)
+	if len(matches) == 0 {
+		return ""
+	}
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return matches[0]
+}
+
+// templateCache parsed text templates of function arguments
+var templateCache sync.Map
+
+// parseTemplate parses the text template and caches it, the missing keys of map are rendered as empty string
+func parseTemplate(text string) (*template.Template, error) {
+	if cache, ok := templateCache.Load(text); ok {
+		return cache.(*template.Template), nil
+	}
+	tpl, err := template.New("pagser").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	templateCache.Store(text, tpl)
+	return tpl, nil
+}
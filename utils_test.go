@@ -65,3 +65,20 @@ func TestToFloat64SliceE(t *testing.T) {
 	out, err := toFloat64SliceE(list)
 	t.Logf("out: %v, error: %v", out, err)
 }
+
+func TestToStringMapE(t *testing.T) {
+	out, err := toStringMapE(map[string]string{"a": "1"})
+	if err != nil || out["a"] != "1" {
+		t.Fatalf("out: %v, error: %v", out, err)
+	}
+
+	out, err = toStringMapE(map[int]int{1: 2})
+	if err != nil || out["1"] != 2 {
+		t.Fatalf("out: %v, error: %v", out, err)
+	}
+
+	_, err = toStringMapE(1)
+	if err == nil {
+		t.Fatal("1 not return error")
+	}
+}
@@ -0,0 +1,146 @@
+package pagser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validate checks the tags of struct and its nested structs before parsing, the tags are tokenized,
+// the selectors are compiled and the functions are resolved in the methods of structs and the registered functions,
+// all problems are returned joined by errors.Join, the problems of selectors and functions are *ParseError
+func (p *Pagser) Validate(v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return errors.New("nil is not a struct")
+	}
+	t = indirectPtrType(t)
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%v is not a struct", t)
+	}
+	var errs []error
+	p.validateFields(&errs, t, nil, "", make(map[reflect.Type]bool))
+	return errors.Join(errs...)
+}
+
+// validateFields validates the fields of struct type, stackTypes are the parent struct types whose methods can be called,
+// the visited types are validated only once, so the recursive structs are supported
+func (p *Pagser) validateFields(errs *[]error, t reflect.Type, stackTypes []reflect.Type, parent string, visited map[reflect.Type]bool) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	invalidTag := false
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		path := joinFieldPath(parent, fieldType.Name)
+		tagValue, tagOk := fieldType.Tag.Lookup(p.Config.TagName)
+		if !tagOk && fieldType.Anonymous {
+			if embeddedType := indirectPtrType(fieldType.Type); embeddedType.Kind() == reflect.Struct {
+				p.validateFields(errs, embeddedType, append(stackTypes, t), parent, visited)
+			}
+			continue
+		}
+		if !tagOk || tagValue == ignoreSymbol {
+			continue
+		}
+		tag, err := p.loadTag(tagValue)
+		if err == nil {
+			err = p.checkFuncSyntax(tagValue)
+		}
+		var selErr *selectorError
+		if errors.As(err, &selErr) {
+			*errs = append(*errs, &ParseError{Path: path, Tag: tagValue, Selector: selErr.Selector, Err: selErr})
+			continue
+		}
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("field %v %w", path, err))
+			invalidTag = true
+			continue
+		}
+		if tag.Computed != nil {
+			for _, name := range tag.Computed.Fields {
+				if _, ok := t.FieldByName(name); !ok {
+					*errs = append(*errs, &ParseError{Path: path, Tag: tagValue, Err: fmt.Errorf("expression references unknown field %v", name)})
+				}
+			}
+			continue
+		}
+		for _, fn := range tag.Funcs {
+			if !p.hasFunc(append(stackTypes, t), fn.Name) {
+				*errs = append(*errs, &ParseError{Path: path, Tag: tagValue, Selector: tag.Selector, Func: fn.Name,
+					Err: fmt.Errorf("method not found: %v", fn.Name)})
+			}
+		}
+
+		elemType := fieldType.Type
+		for elemType.Kind() == reflect.Ptr || elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Array || elemType.Kind() == reflect.Map {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Struct && p.isNestedStruct(elemType) {
+			p.validateFields(errs, elemType, append(stackTypes, t), path, visited)
+		}
+		for _, implType := range p.implementationStructs(elemType) {
+			p.validateFields(errs, implType, append(stackTypes, t), path, visited)
+		}
+	}
+	// the dependencies of fields are resolved only if all tags are valid
+	if !invalidTag {
+		if _, err := p.fieldOrder(t); err != nil {
+			*errs = append(*errs, err)
+		}
+	}
+}
+
+// ValidateTag validates the tag value without the struct, eg: the static analyzer cmd/pagservet,
+// returns the names of functions which are not registered, they must be the methods of structs
+func (p *Pagser) ValidateTag(tagValue string) ([]string, error) {
+	if tagValue == ignoreSymbol {
+		return nil, nil
+	}
+	tag, err := p.newTag(tagValue)
+	var selErr *selectorError
+	if errors.As(err, &selErr) {
+		return nil, selErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tag.Computed != nil {
+		return nil, nil
+	}
+	if err := p.checkFuncSyntax(tagValue); err != nil {
+		return nil, err
+	}
+	methods := make([]string, 0)
+	for _, fn := range tag.Funcs {
+		if _, ok := p.mapFuncs.Load(fn.Name); !ok {
+			methods = append(methods, fn.Name)
+		}
+	}
+	return methods, nil
+}
+
+// checkFuncSyntax checks the syntax of all functions, the tokenizer ignores the invalid single function for compatibility
+func (p *Pagser) checkFuncSyntax(tagValue string) error {
+	value, _ := splitTagModifiers(tagValue)
+	for _, funcValue := range splitFuncTokens(value, p.Config.FuncSymbol)[1:] {
+		if !rxFunc.MatchString(funcValue) {
+			return fmt.Errorf("tag=`%v` is invalid: function `%v` syntax error", tagValue, strings.TrimSpace(funcValue))
+		}
+	}
+	return nil
+}
+
+// hasFunc reports whether the function can be found in the methods of struct types or the registered functions,
+// the same order as findAndExecFunc
+func (p *Pagser) hasFunc(stackTypes []reflect.Type, name string) bool {
+	for i := len(stackTypes) - 1; i >= 0; i-- {
+		if _, ok := reflect.PtrTo(stackTypes[i]).MethodByName(name); ok {
+			return true
+		}
+	}
+	_, ok := p.mapFuncs.Load(name)
+	return ok
+}
@@ -0,0 +1,95 @@
+package pagser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+type validateMenu struct {
+	Name     string         `pagser:"> a->Upper()"`
+	Children []validateMenu `pagser:"> ul > li"`
+}
+
+func (m validateMenu) Upper(node *goquery.Selection, args ...string) (interface{}, error) {
+	return node.Text(), nil
+}
+
+type validateItem struct {
+	Name  string `pagser:"span->ParentFunc()"`
+	Price string `pagser:"span->missingItemFunc()"`
+}
+
+type validateData struct {
+	Title   string         `pagser:"h1->text()"`
+	Menus   []validateMenu `pagser:".menu > li"`
+	Items   []validateItem `pagser:".items li"`
+	Bad     string         `pagser:"h1[->text()"`
+	Func    string         `pagser:"h1->missingFunc()"`
+	Syntax  string         `pagser:"h1->text()->(x"`
+	Total   int            `pagser:"=Unknown * 2"`
+	Ignored string         `pagser:"-"`
+}
+
+func (d validateData) ParentFunc(node *goquery.Selection, args ...string) (interface{}, error) {
+	return node.Text(), nil
+}
+
+func TestValidate(t *testing.T) {
+	p := New()
+
+	type OkData struct {
+		Title string                  `pagser:"h1 || h2->text()"`
+		Menus []validateMenu          `pagser:".menu > li"`
+		Links map[string]validateMenu `pagser:".menu->mapOf(a)"`
+	}
+	require.NoError(t, p.Validate(&OkData{}))
+
+	err := p.Validate(&validateData{})
+	require.Error(t, err)
+	var paths []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var parseErr *ParseError
+		if errors.As(e, &parseErr) {
+			paths = append(paths, parseErr.Path)
+		}
+	}
+	require.Equal(t, []string{"Items.Price", "Bad", "Func", "Total"}, paths)
+	require.Contains(t, err.Error(), "field Syntax tag=`h1->text()->(x` is invalid")
+	require.Contains(t, err.Error(), "method not found: missingFunc")
+
+	type ComputedData struct {
+		Title string `pagser:"h1"`
+		Total int    `pagser:"=Unknown * 2"`
+	}
+	err = p.Validate(ComputedData{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "field Total expression references unknown field Unknown")
+
+	require.Error(t, p.Validate("text"))
+}
+
+func TestValidateTag(t *testing.T) {
+	p := New()
+
+	methods, err := p.ValidateTag(`h1 || h2->text()->MyFunc(a, 'b')->trim(),required`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"MyFunc"}, methods)
+
+	methods, err = p.ValidateTag(`=Price * 2`)
+	require.NoError(t, err)
+	require.Empty(t, methods)
+
+	_, err = p.ValidateTag(`h1->text(`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "function `text(` syntax error")
+
+	_, err = p.ValidateTag(`h1[->text()`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "selector `h1[` is invalid")
+
+	_, err = p.ValidateTag(`=Price *`)
+	require.Error(t, err)
+}
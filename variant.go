@@ -0,0 +1,149 @@
+package pagser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// ErrNoVariant the error of the page which matches no struct of Variant, it can be checked by errors.Is
+var ErrNoVariant = errors.New("no variant matches the page")
+
+// Variant parses the page to the first struct whose `pagser_match` selector matches the page, eg: the product page,
+// the category page or the captcha page of the same site. The `pagser_match` tag is set on any field of struct,
+// usually the blank field, the struct without it matches any page, so it is the fallback of the structs before it.
+//	type ProductPage struct {
+//		_     struct{} `pagser_match:"div.product"`
+//		Title string   `pagser:"h1"`
+//	}
+//	type CaptchaPage struct {
+//		_ struct{} `pagser_match:"form#captcha"`
+//	}
+//	variant, err := pagser.NewVariant(p, ProductPage{}, CaptchaPage{})
+//	v, err := variant.Parse(html)
+//	switch data := v.(type) {
+//	case *ProductPage:
+//	case *CaptchaPage:
+//	}
+type Variant struct {
+	p     *Pagser
+	cases []*variantCase
+}
+
+// variantCase the match selector and the schema of the struct of Variant
+type variantCase struct {
+	match  goquery.Matcher // nil matches any page
+	schema *Schema
+}
+
+// NewVariant returns the variant of the structs of prototypes in order, each prototype is a struct or a pointer to
+// a struct, it is compiled by Pagser.Compile, so the functions must be registered before
+func NewVariant(p *Pagser, prototypes ...interface{}) (*Variant, error) {
+	variant := &Variant{p: p}
+	for _, prototype := range prototypes {
+		t := reflect.TypeOf(prototype)
+		if t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("variant prototype %v is not a struct or a pointer to struct",
+				reflect.TypeOf(prototype))
+		}
+		vc := &variantCase{}
+		if selector, ok := variantSelector(t); ok {
+			matcher, err := cascadia.Compile(selector)
+			if err != nil {
+				return nil, fmt.Errorf("variant %v match selector `%v` is invalid: %w", t, selector, err)
+			}
+			vc.match = matcher
+		}
+		schema, err := p.Compile(t)
+		if err != nil {
+			return nil, fmt.Errorf("variant %v error: %w", t, err)
+		}
+		vc.schema = schema
+		variant.cases = append(variant.cases, vc)
+	}
+	return variant, nil
+}
+
+// variantSelector returns the `pagser_match` selector of the first field of struct type which has it
+func variantSelector(t reflect.Type) (string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if selector, ok := t.Field(i).Tag.Lookup(matchTagName); ok {
+			return strings.TrimSpace(selector), true
+		}
+	}
+	return "", false
+}
+
+// Match returns the struct type of the first variant which matches the document, false if no variant matches
+func (v *Variant) Match(document *goquery.Document) (reflect.Type, bool) {
+	if vc := v.matchCase(document); vc != nil {
+		return vc.schema.Type(), true
+	}
+	return nil, false
+}
+
+func (v *Variant) matchCase(document *goquery.Document) *variantCase {
+	for _, vc := range v.cases {
+		if vc.match == nil || document.FindMatcher(vc.match).Length() > 0 {
+			return vc
+		}
+	}
+	return nil
+}
+
+// Parse parse html to a new value of the struct type of the first variant which matches the page,
+// return the pointer to the value, the error wraps ErrNoVariant if no variant matches
+func (v *Variant) Parse(document string) (interface{}, error) {
+	return v.ParseReader(strings.NewReader(document))
+}
+
+// ParseReader parse reader to a new value of the struct type of the first variant which matches the page like Parse
+func (v *Variant) ParseReader(reader io.Reader) (interface{}, error) {
+	doc, err := v.p.newDocumentFromReader(reader, "")
+	if err != nil {
+		return nil, err
+	}
+	return v.ParseDocumentContext(context.Background(), doc)
+}
+
+// ParseDocument parse document to a new value of the struct type of the first variant which matches the page
+// like Parse
+func (v *Variant) ParseDocument(document *goquery.Document) (interface{}, error) {
+	return v.ParseDocumentContext(context.Background(), document)
+}
+
+// ParseDocumentContext parse document like ParseDocument, parse will be aborted when the ctx is done
+func (v *Variant) ParseDocumentContext(ctx context.Context, document *goquery.Document) (interface{}, error) {
+	vc := v.matchCase(document)
+	if vc == nil {
+		if document.Url != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNoVariant, document.Url)
+		}
+		return nil, ErrNoVariant
+	}
+	value := reflect.New(vc.schema.Type()).Interface()
+	if err := vc.schema.ParseDocumentContext(ctx, value, document); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// ParseURL fetch the page of url like Pagser.ParseURL and parse it to a new value of the struct type of the first
+// variant which matches the page like Parse
+func (v *Variant) ParseURL(url string, opts ...RequestOption) (interface{}, error) {
+	ro := newRequestOptions(opts...)
+	doc, err := v.p.fetchPage(ro, url)
+	if err != nil {
+		return nil, err
+	}
+	return v.ParseDocumentContext(ro.ctx, doc)
+}
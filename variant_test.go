@@ -0,0 +1,90 @@
+package pagser
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+type variantProduct struct {
+	_     struct{} `pagser_match:"div.product"`
+	Title string   `pagser:"h1"`
+	Price float64  `pagser:".price"`
+}
+
+type variantCategory struct {
+	_     struct{} `pagser_match:"ul.products"`
+	Links []struct {
+		Url string `pagser:"->absHref()"`
+	} `pagser:"ul.products a"`
+}
+
+type variantCaptcha struct {
+	_      struct{} `pagser_match:"form#captcha"`
+	Action string   `pagser:"form#captcha->attr(action)"`
+}
+
+type variantUnknown struct {
+	Title string `pagser:"title"`
+}
+
+func TestVariant_Parse(t *testing.T) {
+	p := New()
+	variant, err := NewVariant(p, variantCaptcha{}, &variantProduct{}, variantCategory{})
+	require.NoError(t, err)
+
+	v, err := variant.Parse(`<div class="product"><h1>Phone</h1><span class="price">9.9</span></div>`)
+	require.NoError(t, err)
+	require.Equal(t, &variantProduct{Title: "Phone", Price: 9.9}, v)
+
+	// the first matched variant wins
+	v, err = variant.Parse(`<form id="captcha" action="/verify"></form><div class="product"></div>`)
+	require.NoError(t, err)
+	require.Equal(t, &variantCaptcha{Action: "/verify"}, v)
+
+	_, err = variant.Parse(`<title>Not found</title>`)
+	require.True(t, errors.Is(err, ErrNoVariant), "%v", err)
+
+	// the struct without match selector is the fallback
+	variant, err = NewVariant(p, variantProduct{}, variantUnknown{})
+	require.NoError(t, err)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<title>Not found</title>`))
+	require.NoError(t, err)
+	typ, ok := variant.Match(doc)
+	require.True(t, ok)
+	require.Equal(t, reflect.TypeOf(variantUnknown{}), typ)
+}
+
+func TestVariant_ParseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<ul class="products"><li><a href="/p/1">1</a></li><li><a href="p/2">2</a></li></ul>`))
+	}))
+	defer server.Close()
+
+	variant, err := NewVariant(New(), variantProduct{}, variantCategory{})
+	require.NoError(t, err)
+	v, err := variant.ParseURL(server.URL + "/list/")
+	require.NoError(t, err)
+	category, ok := v.(*variantCategory)
+	require.True(t, ok)
+	require.Len(t, category.Links, 2)
+	require.Equal(t, server.URL+"/p/1", category.Links[0].Url)
+	require.Equal(t, server.URL+"/list/p/2", category.Links[1].Url)
+}
+
+func TestNewVariant(t *testing.T) {
+	p := New()
+	_, err := NewVariant(p, "string")
+	require.EqualError(t, err, "variant prototype string is not a struct or a pointer to struct")
+	_, err = NewVariant(p, struct {
+		_ struct{} `pagser_match:"div["`
+	}{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "match selector `div[` is invalid")
+}
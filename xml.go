@@ -0,0 +1,121 @@
+package pagser
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// ParseXML parse the xml document, such as the RSS or Atom feed, to struct with the same tag language as html.
+// The names of elements and attributes are lower case, and the prefix of namespace is kept, eg: `pubdate` for
+// `<pubDate>` and `content\:encoded` for `<content:encoded>`, the CDATA is the text of element.
+//	type Feed struct {
+//		Title string `pagser:"channel > title"`
+//		Items []struct {
+//			Title   string `pagser:"title"`
+//			Link    string `pagser:"link"`
+//			PubDate string `pagser:"pubdate"`
+//			Content string `pagser:"content\\:encoded"`
+//		} `pagser:"item"`
+//	}
+func (p *Pagser) ParseXML(v interface{}, document string) error {
+	return p.ParseXMLReaderContext(context.Background(), v, strings.NewReader(document))
+}
+
+// ParseXMLReader parse the xml of reader to struct like ParseXML
+func (p *Pagser) ParseXMLReader(v interface{}, reader io.Reader) error {
+	return p.ParseXMLReaderContext(context.Background(), v, reader)
+}
+
+// ParseXMLReaderContext parse the xml of reader to struct like ParseXML, parse will be aborted when the ctx is done
+func (p *Pagser) ParseXMLReaderContext(ctx context.Context, v interface{}, reader io.Reader) error {
+	doc, err := p.newXMLDocumentFromReader(reader)
+	if err != nil {
+		return err
+	}
+	return p.ParseDocumentContext(ctx, v, doc)
+}
+
+// newXMLDocumentFromReader reads at most Config.MaxDocumentBytes bytes of reader and parses them by NewXMLDocument
+func (p *Pagser) newXMLDocumentFromReader(reader io.Reader) (*goquery.Document, error) {
+	content, err := p.readDocument(reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewXMLDocument(bytes.NewReader(content))
+}
+
+// NewXMLDocument parse the xml of reader to the document which can be queried by goquery like html, see ParseXML.
+// The xml is parsed leniently, the html entities such as `&nbsp;` are allowed and the unclosed elements are closed
+// at the end, the charset of `<?xml encoding?>` is decoded to UTF-8.
+func NewXMLDocument(reader io.Reader) (*goquery.Document, error) {
+	decoder := xml.NewDecoder(reader)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	root := &html.Node{Type: html.DocumentNode}
+	stack := []*html.Node{root}
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &html.Node{Type: html.ElementNode, Data: xmlName(t.Name)}
+			for _, attr := range t.Attr {
+				node.Attr = append(node.Attr, html.Attribute{Key: xmlName(attr.Name), Val: attr.Value})
+			}
+			parent.AppendChild(node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			// the unmatched end element is ignored, the unclosed elements in it are closed
+			name := xmlName(t.Name)
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Data == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		case xml.CharData:
+			parent.AppendChild(&html.Node{Type: html.TextNode, Data: string(t)})
+		case xml.Comment:
+			parent.AppendChild(&html.Node{Type: html.CommentNode, Data: string(t)})
+		}
+	}
+	return goquery.NewDocumentFromNode(root), nil
+}
+
+// xmlName returns the lower case name of element or attribute with the prefix of namespace
+func xmlName(name xml.Name) string {
+	if name.Space != "" {
+		return strings.ToLower(name.Space + ":" + name.Local)
+	}
+	return strings.ToLower(name.Local)
+}
+
+// isXMLContentType reports whether the `Content-Type` is the xml document, such as the RSS or Atom feed,
+// the XHTML is parsed as html
+func isXMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/xml", "text/xml", "application/rss+xml", "application/atom+xml", "application/rdf+xml":
+		return true
+	}
+	return false
+}
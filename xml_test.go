@@ -0,0 +1,128 @@
+package pagser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+	<title>Pagser News</title>
+	<link>https://example.com/</link>
+	<item>
+		<title>First&nbsp;post</title>
+		<link>https://example.com/first</link>
+		<guid isPermaLink="false">post-1</guid>
+		<pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+		<description><![CDATA[<p>Hello <b>world</b></p>]]></description>
+		<content:encoded><![CDATA[<p>Full content</p>]]></content:encoded>
+	</item>
+	<item>
+		<title>Second post</title>
+		<link>https://example.com/second</link>
+		<guid>post-2</guid>
+	</item>
+</channel>
+</rss>`
+
+type rssFeed struct {
+	Title string `pagser:"channel > title"`
+	Link  string `pagser:"channel > link"`
+	Items []struct {
+		Title       string `pagser:"title"`
+		Link        string `pagser:"link"`
+		GUID        string `pagser:"guid"`
+		PermaLink   string `pagser:"guid->attr(ispermalink)"`
+		PubDate     string `pagser:"pubdate"`
+		Description string `pagser:"description"`
+		Content     string `pagser:"content\\:encoded"`
+	} `pagser:"item"`
+}
+
+const testAtom = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Atom caf` + "\xe9" + `</title>
+	<link href="https://example.com/" rel="alternate"/>
+	<entry>
+		<title>Entry</title>
+		<link href="https://example.com/entry"/>
+		<updated>2006-01-02T15:04:05Z</updated>
+	</entry>
+`
+
+type atomFeed struct {
+	Title   string `pagser:"feed > title"`
+	Link    string `pagser:"feed > link[rel=alternate]->attr(href)"`
+	Entries []struct {
+		Title   string `pagser:"title"`
+		Link    string `pagser:"link->attr(href)"`
+		Updated string `pagser:"updated"`
+	} `pagser:"entry"`
+}
+
+func TestPagser_ParseXML(t *testing.T) {
+	p := New()
+
+	var rss rssFeed
+	require.NoError(t, p.ParseXML(&rss, testRSS))
+	require.Equal(t, "Pagser News", rss.Title)
+	require.Equal(t, "https://example.com/", rss.Link)
+	require.Len(t, rss.Items, 2)
+	require.Equal(t, "First post", rss.Items[0].Title)
+	require.Equal(t, "https://example.com/first", rss.Items[0].Link)
+	require.Equal(t, "post-1", rss.Items[0].GUID)
+	require.Equal(t, "false", rss.Items[0].PermaLink)
+	require.Equal(t, "Mon, 02 Jan 2006 15:04:05 +0000", rss.Items[0].PubDate)
+	require.Equal(t, "<p>Hello <b>world</b></p>", rss.Items[0].Description)
+	require.Equal(t, "<p>Full content</p>", rss.Items[0].Content)
+	require.Equal(t, "Second post", rss.Items[1].Title)
+
+	// the unclosed elements are closed at the end
+	var atom atomFeed
+	require.NoError(t, p.ParseXML(&atom, testAtom))
+	require.Equal(t, "Atom café", atom.Title)
+	require.Equal(t, "https://example.com/", atom.Link)
+	require.Len(t, atom.Entries, 1)
+	require.Equal(t, "https://example.com/entry", atom.Entries[0].Link)
+	require.Equal(t, "2006-01-02T15:04:05Z", atom.Entries[0].Updated)
+}
+
+func TestPagser_ParseURL_Feed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+			_, _ = w.Write([]byte(testRSS))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<link rel="alternate" type="application/rss+xml" href="/feed"><title>Home</title>`))
+		}
+	}))
+	defer server.Close()
+
+	p := New()
+	var home struct {
+		Title string `pagser:"title"`
+		Feed  string `pagser:"link[type='application/rss+xml']->absHref()"`
+	}
+	require.NoError(t, p.ParseURL(&home, server.URL))
+	require.Equal(t, "Home", home.Title)
+	require.Equal(t, server.URL+"/feed", home.Feed)
+
+	var rss rssFeed
+	require.NoError(t, p.ParseURL(&rss, home.Feed))
+	require.Equal(t, "Pagser News", rss.Title)
+	require.Equal(t, "https://example.com/second", rss.Items[1].Link)
+}
+
+func TestIsXMLContentType(t *testing.T) {
+	require.True(t, isXMLContentType("application/atom+xml"))
+	require.True(t, isXMLContentType("text/xml; charset=utf-8"))
+	require.False(t, isXMLContentType("text/html"))
+	require.False(t, isXMLContentType("application/xhtml+xml"))
+	require.False(t, isXMLContentType(""))
+}